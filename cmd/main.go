@@ -1,28 +1,35 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository/postgres"
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
 	"log"
+	"net"
 	"os"
 
 	"github.com/labstack/echo/v4"
 	echoSwagger "github.com/swaggo/echo-swagger"
 
-	// auto-generated package with documentation
-	_ "github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/docs"
+	// auto-generated packages with documentation, one per API version
+	_ "github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/docs/v1"
+	_ "github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/docs/v2"
 
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/configs"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/graph"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/grpcserver"
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/handler"
-	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/notify"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/storage"
 )
 
 // @title Advertising API
 // @version 1.0
-// @description A service for submitting and storing advertisements
+// @description A service for submitting and storing advertisements.
+// @description Every endpoint is also served under the unversioned /api prefix;
+// @description the advert endpoints are deprecated in favour of /api/v2.
 // @host localhost:8080
-// @BasePath /api
+// @BasePath /api/v1
 
 func main() {
 	// Load environment variables
@@ -34,23 +41,183 @@ func main() {
 		log.Fatal("failed to load config:", err)
 	}
 
-	// Initialize database connection
-	db, err := repository.NewDb(cfg)
+	// Initialize storage: Postgres, or in-memory repositories for development
+	repos, err := newRepositories(cfg)
 	if err != nil {
-		log.Fatal("failed to connect to database:", err)
+		log.Fatal("failed to initialize storage:", err)
 	}
-	defer db.Close()
+	defer repos.close()
 
 	// Initialize web server
 	e := echo.New()
+	handler.RegisterErrorHandler(e)
+	e.IPExtractor, err = newIPExtractor(cfg.Server.TrustedProxies)
+	if err != nil {
+		log.Fatal("invalid trusted proxies:", err)
+	}
 
-	// Swagger UI available at: /swagger/index.html
-	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	// Swagger UI per API version: /swagger/v1/index.html and /swagger/v2/index.html,
+	// /swagger/index.html stays on v1
+	e.GET("/swagger/*", echoSwagger.EchoWrapHandler(echoSwagger.InstanceName("v1")))
+	e.GET("/swagger/v1/*", echoSwagger.EchoWrapHandler(echoSwagger.InstanceName("v1")))
+	e.GET("/swagger/v2/*", echoSwagger.EchoWrapHandler(echoSwagger.InstanceName("v2")))
+
+	// Routes under /api are served under /api/v1 too
+	handler.ServeV1Aliases(e)
 
 	// Register routes
 	// let's assume you're creating the service and passing it directly to the handler:
-	advertSvc := service.NewAdvertService(postgres.NewPostgresAdvertRepo(db), postgres.NewPostgresPhotoRepo(db))
-	handler.NewAdvertHandler(e, advertSvc)
+	advertRepo := repos.advert
+	photoRepo := repos.photo
+
+	// Uploaded photos go to the configured blob storage
+	store, err := storage.NewBlobStore(cfg)
+	if err != nil {
+		log.Fatal("failed to initialize media storage:", err)
+	}
+
+	// Perceptual hashes find the same photo reused by different owners
+	hashSvc := service.NewPhotoHashService(advertRepo, photoRepo, store, service.PhotoHashOptions{
+		Interval:             cfg.Dedup.Interval,
+		Timeout:              cfg.Dedup.Timeout,
+		BatchSize:            cfg.Dedup.BatchSize,
+		MaxSize:              cfg.Dedup.MaxSize,
+		AllowedSchemes:       cfg.PhotoCheck.AllowedSchemes,
+		AllowPrivateNetworks: cfg.PhotoCheck.AllowPrivateNetworks,
+		BaseURL:              cfg.Media.BaseURL,
+	})
+	if cfg.Dedup.Enabled {
+		go hashSvc.Run(context.Background())
+	}
+	if cfg.Admin.Token != "" {
+		handler.NewAdminHandler(e, hashSvc, cfg.Admin.Token)
+	}
+
+	// Advert events are stored per subscriber and delivered with retries
+	webhookSvc := service.NewWebhookService(repos.webhook, service.WebhookOptions{
+		Interval:             cfg.Webhooks.Interval,
+		Timeout:              cfg.Webhooks.Timeout,
+		BatchSize:            cfg.Webhooks.BatchSize,
+		MaxAttempts:          cfg.Webhooks.MaxAttempts,
+		BaseBackoff:          cfg.Webhooks.BaseBackoff,
+		MaxBackoff:           cfg.Webhooks.MaxBackoff,
+		AllowPrivateNetworks: cfg.Webhooks.AllowPrivateNetworks,
+	})
+	if cfg.Admin.Token != "" {
+		handler.NewWebhookHandler(e, webhookSvc, cfg.Admin.Token)
+	}
+
+	// Advert events are written to the outbox together with the change and relayed to publishers
+	var publishers []service.Publisher
+	if cfg.Webhooks.Enabled {
+		go webhookSvc.Run(context.Background())
+		publishers = append(publishers, service.NewWebhookPublisher(webhookSvc, photoRepo))
+	}
+	// Changes reach the SSE clients of every replica through the change feed
+	changeFeed := repos.changeFeed
+	publishers = append(publishers, service.NewChangeFeedPublisher(photoRepo, changeFeed))
+	advertStream := service.NewAdvertStream(changeFeed, cfg.Stream.ReplaySize)
+	go advertStream.Run(context.Background())
+	handler.NewStreamHandler(e, advertStream, cfg.Stream.KeepAlive)
+	if cfg.Outbox.Log {
+		publishers = append(publishers, service.NewLogPublisher(os.Stdout))
+	}
+	relay := service.NewOutboxRelay(repos.outbox, publishers, service.OutboxOptions{
+		Interval:    cfg.Outbox.Interval,
+		BatchSize:   cfg.Outbox.BatchSize,
+		BaseBackoff: cfg.Outbox.BaseBackoff,
+		MaxBackoff:  cfg.Outbox.MaxBackoff,
+		Retention:   cfg.Outbox.Retention,
+	})
+	go relay.Run(context.Background())
+	if cfg.Admin.Token != "" {
+		handler.NewOutboxHandler(e, relay, cfg.Admin.Token)
+	}
+
+	favoriteRepo := repos.favorite
+	advertSvc := service.NewAdvertService(advertRepo, photoRepo, favoriteRepo)
+	if cfg.Dedup.FlagOnCreate {
+		advertSvc = service.NewDuplicateFlaggingService(advertSvc, hashSvc)
+	}
+
+	// New adverts are matched against saved searches in the background
+	notifier, err := notify.NewNotifier(cfg)
+	if err != nil {
+		log.Fatal("failed to initialize notifier:", err)
+	}
+	savedSearchRepo := repos.savedSearch
+	matcher := service.NewSearchMatcher(advertRepo, savedSearchRepo, notifier)
+	go matcher.Run(context.Background())
+	advertSvc = service.NewSearchMatchingService(advertSvc, matcher)
+	// Reads are cached outermost, so that every write through advertSvc invalidates them;
+	// the other changes of adverts reach the cache through the change feed and invalidator
+	invalidator := service.NoInvalidation
+	if cfg.AdvertCache.Enabled {
+		cachingSvc := service.NewCachingAdvertService(advertSvc, service.NewLRUAdvertCache(cfg.AdvertCache.Size), cfg.AdvertCache.TTL, changeFeed)
+		go cachingSvc.Run(context.Background())
+		invalidator = cachingSvc
+		if cfg.Admin.Token != "" {
+			handler.NewAdvertCacheHandler(e, cachingSvc, cfg.Admin.Token)
+		}
+		advertSvc = cachingSvc
+	}
+	handler.NewSavedSearchHandler(e, service.NewSavedSearchService(savedSearchRepo, advertSvc))
+	// Views are buffered in memory and written in batches
+	viewSvc := service.NewViewService(advertRepo, invalidator, cfg.Views.FlushInterval, cfg.Views.DedupWindow)
+	go viewSvc.Run(context.Background())
+	handler.NewAdvertHandler(e, advertSvc, viewSvc)
+	photoSvc := service.NewPhotoService(advertRepo, photoRepo)
+	handler.NewPhotoHandler(e, photoSvc)
+	// GraphQL over the same services, photos of a page are loaded in one query
+	e.Any("/graphql", echo.WrapHandler(graph.NewHandler(advertSvc, photoSvc, cfg.GraphQL.ComplexityLimit)))
+	handler.NewFavoriteHandler(e, service.NewFavoriteService(advertRepo, favoriteRepo, invalidator))
+	statsRepo := repos.stats
+	handler.NewStatsHandler(e, service.NewStatsService(statsRepo, cfg.Stats.PriceBuckets, cfg.Stats.CacheTTL))
+
+	variantSpecs := make([]service.VariantSpec, 0, len(cfg.Media.Variants))
+	for _, v := range cfg.Media.Variants {
+		variantSpecs = append(variantSpecs, service.VariantSpec{Name: v.Name, Width: v.Width, Height: v.Height})
+	}
+	// Photos whose variants were never generated (full queue, restart) are picked up by a sweep
+	variantSvc := service.NewVariantService(photoRepo, invalidator, store, variantSpecs, service.VariantOptions{
+		Workers:       cfg.Media.VariantWorkers,
+		SweepInterval: cfg.Media.VariantSweepInterval,
+		BaseURL:       cfg.Media.BaseURL,
+	})
+	go variantSvc.Run(context.Background())
+	uploadSvc := service.NewUploadService(advertRepo, photoSvc, variantSvc, store, cfg.Media.MaxUploadSize, cfg.Media.BaseURL)
+	handler.NewMediaHandler(e, uploadSvc, store, cfg.Media.MaxUploadSize)
+
+	// Verify photo URLs in the background; broken photos are skipped as main photo
+	if cfg.PhotoCheck.Enabled {
+		photoCheckSvc := service.NewPhotoCheckService(photoRepo, invalidator, service.PhotoCheckOptions{
+			Interval:             cfg.PhotoCheck.Interval,
+			RecheckAfter:         cfg.PhotoCheck.RecheckAfter,
+			Timeout:              cfg.PhotoCheck.Timeout,
+			BatchSize:            cfg.PhotoCheck.BatchSize,
+			AllowedSchemes:       cfg.PhotoCheck.AllowedSchemes,
+			AllowPrivateNetworks: cfg.PhotoCheck.AllowPrivateNetworks,
+		})
+		go photoCheckSvc.Run(context.Background())
+	}
+	// Import jobs are kept in memory, finished ones expire after an hour
+	importSvc := service.NewImportService(advertSvc, cfg.Import.BackgroundThreshold, cfg.Import.MaxRows)
+	go importSvc.Run(context.Background())
+	handler.NewImportHandler(e, importSvc, cfg.Import.MaxSize)
+
+	// Internal services use the same advert service over gRPC
+	if cfg.GRPC.Port != 0 {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+		if err != nil {
+			log.Fatal("failed to listen for gRPC:", err)
+		}
+		go func() {
+			log.Printf("Starting gRPC server on %s...", lis.Addr())
+			if err := grpcserver.NewServer(advertSvc).Serve(lis); err != nil {
+				log.Fatalf("error starting gRPC server: %v", err)
+			}
+		}()
+	}
 
 	// Start HTTP server
 	address := fmt.Sprintf(":%d", cfg.Server.Port)
@@ -60,3 +227,24 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// newIPExtractor trusts X-Forwarded-For only from the given proxy CIDRs, so
+// clients can't pick their own IP; without proxies the peer address is used.
+func newIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
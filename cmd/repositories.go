@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/configs"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository/memory"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository/postgres"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository/sqlite"
+)
+
+// repositories are the storage the services run on, selected by cfg.DB.Driver.
+type repositories struct {
+	advert      repository.AdvertRepo
+	photo       repository.PhotoRepo
+	favorite    repository.FavoriteRepo
+	savedSearch repository.SavedSearchRepo
+	webhook     repository.WebhookRepo
+	outbox      repository.OutboxRepo
+	stats       repository.StatsRepo
+	changeFeed  repository.ChangeFeed
+	// close releases the database connection
+	close func() error
+}
+
+func newRepositories(cfg *configs.Config) (*repositories, error) {
+	switch cfg.DB.Driver {
+	case "memory":
+		store := memory.NewStore()
+		return &repositories{
+			advert:      memory.NewMemoryAdvertRepo(store),
+			photo:       memory.NewMemoryPhotoRepo(store),
+			favorite:    memory.NewMemoryFavoriteRepo(store),
+			savedSearch: memory.NewMemorySavedSearchRepo(store),
+			webhook:     memory.NewMemoryWebhookRepo(store),
+			outbox:      memory.NewMemoryOutboxRepo(store),
+			stats:       memory.NewMemoryStatsRepo(store),
+			changeFeed:  memory.NewMemoryChangeFeed(),
+			close:       func() error { return nil },
+		}, nil
+	case "sqlite":
+		db, err := repository.NewDb(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open database: %w", err)
+		}
+		if err := sqlite.Migrate(context.Background(), db); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+		return &repositories{
+			advert:      sqlite.NewSQLiteAdvertRepo(db),
+			photo:       sqlite.NewSQLitePhotoRepo(db),
+			favorite:    sqlite.NewSQLiteFavoriteRepo(db),
+			savedSearch: sqlite.NewSQLiteSavedSearchRepo(db),
+			webhook:     sqlite.NewSQLiteWebhookRepo(db),
+			outbox:      sqlite.NewSQLiteOutboxRepo(db),
+			stats:       sqlite.NewSQLiteStatsRepo(db),
+			// A SQLite database is served by a single process, so its changes stay in it
+			changeFeed: memory.NewMemoryChangeFeed(),
+			close:      db.Close,
+		}, nil
+	case "", "postgres":
+		db, err := repository.NewDb(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		return &repositories{
+			advert:      postgres.NewPostgresAdvertRepo(db),
+			photo:       postgres.NewPostgresPhotoRepo(db),
+			favorite:    postgres.NewPostgresFavoriteRepo(db),
+			savedSearch: postgres.NewPostgresSavedSearchRepo(db),
+			webhook:     postgres.NewPostgresWebhookRepo(db),
+			outbox:      postgres.NewPostgresOutboxRepo(db),
+			stats:       postgres.NewPostgresStatsRepo(db),
+			// Changes reach the SSE clients of every replica through LISTEN/NOTIFY
+			changeFeed: postgres.NewPostgresChangeFeed(db, repository.DSN(cfg)),
+			close:      db.Close,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.DB.Driver)
+	}
+}
@@ -0,0 +1,9 @@
+package main
+
+// General information of the v2 API documentation (docs/v2), see `make swagger`.
+//
+// @title Advertising API
+// @version 2.0
+// @description Advert endpoints with enveloped responses and RFC 7807 (application/problem+json) errors.
+// @host localhost:8080
+// @BasePath /api/v2
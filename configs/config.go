@@ -1,19 +1,156 @@
 package configs
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 type Config struct {
 	Server struct {
 		Host string
 		Port int
+		// TrustedProxies lists the CIDRs of the reverse proxies whose X-Forwarded-For
+		// is used as the client IP; when empty the peer address is used
+		TrustedProxies []string `mapstructure:"trusted_proxies"`
+	}
+	GRPC struct {
+		// Port of the gRPC API, 0 disables it
+		Port int
 	}
 	DB struct {
+		// Driver is "postgres", "sqlite" or "memory"; the memory repositories lose everything on restart
+		Driver string
+		// Path is the database file of the sqlite driver
+		Path     string
 		Host     string
 		Port     int
 		User     string
 		Password string
 		Name     string
 	}
+	Import struct {
+		// Files with more rows are processed as a background job
+		BackgroundThreshold int `mapstructure:"background_threshold"`
+		MaxRows             int `mapstructure:"max_rows"`
+		// MaxSize limits the import file in bytes
+		MaxSize int64 `mapstructure:"max_size"`
+	}
+	Media struct {
+		// Storage is "local" or "s3"
+		Storage string
+		// BaseURL is prepended to /media/... in stored photo URLs, empty keeps them relative
+		BaseURL       string `mapstructure:"base_url"`
+		MaxUploadSize int64  `mapstructure:"max_upload_size"`
+		Local         struct {
+			Root string
+		}
+		S3 struct {
+			Endpoint  string
+			Region    string
+			Bucket    string
+			AccessKey string `mapstructure:"access_key"`
+			SecretKey string `mapstructure:"secret_key"`
+		}
+		// Variants are generated for uploaded JPEG/PNG photos
+		Variants []struct {
+			Name   string
+			Width  int
+			Height int
+		}
+		VariantWorkers int `mapstructure:"variant_workers"`
+		// VariantSweepInterval is how often photos still missing variants are looked for
+		VariantSweepInterval time.Duration `mapstructure:"variant_sweep_interval"`
+	}
+	PhotoCheck struct {
+		Enabled        bool
+		Interval       time.Duration
+		RecheckAfter   time.Duration `mapstructure:"recheck_after"`
+		Timeout        time.Duration
+		BatchSize      int      `mapstructure:"batch_size"`
+		AllowedSchemes []string `mapstructure:"allowed_schemes"`
+		// AllowPrivateNetworks lets photo URLs point at loopback and private addresses,
+		// which is only safe when users cannot reach internal services through them
+		AllowPrivateNetworks bool `mapstructure:"allow_private_networks"`
+	} `mapstructure:"photo_check"`
+	Dedup struct {
+		// Enabled runs background perceptual hashing of verified photos
+		Enabled bool
+		// FlagOnCreate flags new adverts whose photos are used by another owner
+		FlagOnCreate bool `mapstructure:"flag_on_create"`
+		Interval     time.Duration
+		Timeout      time.Duration
+		BatchSize    int   `mapstructure:"batch_size"`
+		MaxSize      int64 `mapstructure:"max_size"`
+	}
+	Views struct {
+		// FlushInterval is how often buffered views are written to the database
+		FlushInterval time.Duration `mapstructure:"flush_interval"`
+		// DedupWindow counts repeated views of one viewer (IP and session) once
+		DedupWindow time.Duration `mapstructure:"dedup_window"`
+	}
+	Stats struct {
+		CacheTTL time.Duration `mapstructure:"cache_ttl"`
+		// PriceBuckets are the default ascending histogram bounds
+		PriceBuckets []float64 `mapstructure:"price_buckets"`
+	}
+	AdvertCache struct {
+		// Enabled caches advert reads (GetByID, List) in memory
+		Enabled bool
+		// Size is the number of cached entries, the least recently used are evicted
+		Size int
+		// TTL also bounds how stale favorites and views made on another replica get
+		TTL time.Duration
+	} `mapstructure:"advert_cache"`
+	Notify struct {
+		// Driver is "log", "file" or "smtp"
+		Driver string
+		File   string
+		SMTP   struct {
+			Host     string
+			Port     int
+			From     string
+			Username string
+			Password string
+		}
+	}
+	GraphQL struct {
+		// ComplexityLimit rejects queries estimated to be more expensive,
+		// a list of adverts multiplies the cost of its fields by the page size
+		ComplexityLimit int `mapstructure:"complexity_limit"`
+	}
+	Outbox struct {
+		Interval    time.Duration
+		BatchSize   int           `mapstructure:"batch_size"`
+		BaseBackoff time.Duration `mapstructure:"base_backoff"`
+		MaxBackoff  time.Duration `mapstructure:"max_backoff"`
+		// Retention is how long published events are kept
+		Retention time.Duration
+		// Log also writes every event to stdout as a JSON line
+		Log bool
+	}
+	Stream struct {
+		// ReplaySize is how many changes are kept for Last-Event-ID resume
+		ReplaySize int           `mapstructure:"replay_size"`
+		KeepAlive  time.Duration `mapstructure:"keep_alive"`
+	}
+	Webhooks struct {
+		// Enabled publishes advert events to webhook subscribers
+		Enabled  bool
+		Interval time.Duration
+		Timeout  time.Duration
+		// BatchSize is how many due deliveries one dispatch claims
+		BatchSize   int           `mapstructure:"batch_size"`
+		MaxAttempts int           `mapstructure:"max_attempts"`
+		BaseBackoff time.Duration `mapstructure:"base_backoff"`
+		MaxBackoff  time.Duration `mapstructure:"max_backoff"`
+		// AllowPrivateNetworks lets deliveries reach loopback and private addresses
+		AllowPrivateNetworks bool `mapstructure:"allow_private_networks"`
+	}
+	Admin struct {
+		// Token protects /api/admin, admin routes are disabled when empty
+		Token string
+	}
 }
 
 // LoadConfig reads config.yaml and overrides with ENV
@@ -36,6 +173,12 @@ func LoadConfig(path string) (*Config, error) {
 	if viper.IsSet("PORT") {
 		cfg.Server.Port = viper.GetInt("PORT")
 	}
+	if viper.IsSet("DB_DRIVER") {
+		cfg.DB.Driver = viper.GetString("DB_DRIVER")
+	}
+	if viper.IsSet("DB_PATH") {
+		cfg.DB.Path = viper.GetString("DB_PATH")
+	}
 	if viper.IsSet("DB_HOST") {
 		cfg.DB.Host = viper.GetString("DB_HOST")
 	}
@@ -52,5 +195,22 @@ func LoadConfig(path string) (*Config, error) {
 		cfg.DB.Name = viper.GetString("DB_NAME")
 	}
 
+	if viper.IsSet("S3_ACCESS_KEY") {
+		cfg.Media.S3.AccessKey = viper.GetString("S3_ACCESS_KEY")
+	}
+	if viper.IsSet("S3_SECRET_KEY") {
+		cfg.Media.S3.SecretKey = viper.GetString("S3_SECRET_KEY")
+	}
+
+	if viper.IsSet("SMTP_USERNAME") {
+		cfg.Notify.SMTP.Username = viper.GetString("SMTP_USERNAME")
+	}
+	if viper.IsSet("SMTP_PASSWORD") {
+		cfg.Notify.SMTP.Password = viper.GetString("SMTP_PASSWORD")
+	}
+	if viper.IsSet("ADMIN_TOKEN") {
+		cfg.Admin.Token = viper.GetString("ADMIN_TOKEN")
+	}
+
 	return &cfg, nil
 }
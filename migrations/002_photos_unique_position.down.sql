@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS photos DROP CONSTRAINT IF EXISTS photos_advert_id_position_key;
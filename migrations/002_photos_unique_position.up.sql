@@ -0,0 +1,11 @@
+-- Compact existing positions to 1..n per advert (1 = main photo)
+UPDATE photos p
+   SET position = r.rn
+  FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY advert_id ORDER BY position, id) AS rn
+          FROM photos) r
+ WHERE p.id = r.id;
+
+-- Deferred, so positions can be shifted inside a transaction
+ALTER TABLE photos
+    ADD CONSTRAINT photos_advert_id_position_key UNIQUE (advert_id, position)
+        DEFERRABLE INITIALLY DEFERRED;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS photo_variants (
+                                photo_id   INTEGER NOT NULL REFERENCES photos(id) ON DELETE CASCADE,
+                                name       VARCHAR(32) NOT NULL,   -- thumb, medium, large…
+                                url        TEXT NOT NULL,
+                                width      INTEGER NOT NULL,
+                                height     INTEGER NOT NULL,
+                                PRIMARY KEY (photo_id, name)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_photos_checked_at;
+ALTER TABLE IF EXISTS photos
+    DROP COLUMN IF EXISTS checked_at,
+    DROP COLUMN IF EXISTS check_error,
+    DROP COLUMN IF EXISTS status;
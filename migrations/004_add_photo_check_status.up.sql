@@ -0,0 +1,6 @@
+ALTER TABLE photos
+    ADD COLUMN IF NOT EXISTS status      VARCHAR(16) NOT NULL DEFAULT 'unchecked',  -- unchecked | ok | broken
+    ADD COLUMN IF NOT EXISTS check_error TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS checked_at  TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS idx_photos_checked_at ON photos(checked_at NULLS FIRST);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_photos_phash;
+ALTER TABLE IF EXISTS photos DROP COLUMN IF EXISTS phash;
+ALTER TABLE IF EXISTS adverts
+    DROP COLUMN IF EXISTS flag_reason,
+    DROP COLUMN IF EXISTS owner_id;
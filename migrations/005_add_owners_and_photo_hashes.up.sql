@@ -0,0 +1,9 @@
+ALTER TABLE adverts
+    ADD COLUMN IF NOT EXISTS owner_id    VARCHAR(64) NOT NULL DEFAULT '',  -- '' = anonymous
+    ADD COLUMN IF NOT EXISTS flag_reason TEXT NOT NULL DEFAULT '';
+
+-- Perceptual (difference) hash; 0 = could not be computed / no information
+ALTER TABLE photos
+    ADD COLUMN IF NOT EXISTS phash BIGINT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_photos_phash ON photos(phash) WHERE phash IS NOT NULL AND phash <> 0;
@@ -0,0 +1,11 @@
+-- No foreign key on advert_id: a favorite outlives its advert and is shown as deleted
+CREATE TABLE IF NOT EXISTS favorites (
+                           user_id      VARCHAR(64) NOT NULL,
+                           advert_id    INTEGER NOT NULL,
+                           name         VARCHAR(200) NOT NULL,      -- advert name when it was favorited
+                           price_at_add NUMERIC(12, 2) NOT NULL,
+                           created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+                           PRIMARY KEY (user_id, advert_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorites_advert_id ON favorites(advert_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_adverts_view_count;
+ALTER TABLE IF EXISTS adverts DROP COLUMN IF EXISTS view_count;
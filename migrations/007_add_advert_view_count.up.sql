@@ -0,0 +1,4 @@
+ALTER TABLE adverts
+    ADD COLUMN IF NOT EXISTS view_count BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_adverts_view_count ON adverts(view_count DESC, id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS saved_searches (
+                                id           SERIAL PRIMARY KEY,
+                                user_id      VARCHAR(64) NOT NULL,
+                                email        TEXT NOT NULL DEFAULT '',       -- '' = no e-mail notifications
+                                name         VARCHAR(200) NOT NULL,
+                                min_price    NUMERIC(12, 2) NULL,
+                                max_price    NUMERIC(12, 2) NULL,
+                                created_from TIMESTAMP NULL,
+                                created_to   TIMESTAMP NULL,                  -- exclusive
+                                sort         VARCHAR(32) NOT NULL DEFAULT '', -- list sort param, e.g. price_asc
+                                created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user_id ON saved_searches(user_id);
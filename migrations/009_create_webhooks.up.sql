@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+                                       id         SERIAL PRIMARY KEY,
+                                       url        TEXT NOT NULL,
+                                       secret     TEXT NOT NULL,   -- HMAC-SHA256 key of the payload signature
+                                       events     TEXT[] NOT NULL, -- advert.created, advert.updated, advert.deleted
+                                       created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+                                    id              BIGSERIAL PRIMARY KEY,
+                                    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+                                    event           VARCHAR(32) NOT NULL,
+                                    payload         TEXT NOT NULL,
+                                    status          VARCHAR(16) NOT NULL DEFAULT 'pending', -- pending | delivered | dead
+                                    attempts        INTEGER NOT NULL DEFAULT 0,
+                                    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                    last_error      TEXT NOT NULL DEFAULT '',
+                                    response_code   INTEGER NOT NULL DEFAULT 0,
+                                    created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+                                    delivered_at    TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, id DESC);
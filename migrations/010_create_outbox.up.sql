@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS outbox (
+                        id              BIGSERIAL PRIMARY KEY,
+                        aggregate_id    INTEGER NOT NULL,  -- advert ID, no FK: deleted adverts keep their events
+                        event           VARCHAR(32) NOT NULL,
+                        payload         TEXT NOT NULL,
+                        attempts        INTEGER NOT NULL DEFAULT 0,
+                        next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                        last_error      TEXT NOT NULL DEFAULT '',
+                        created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+                        published_at    TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox(aggregate_id, id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_published_at ON outbox(published_at) WHERE published_at IS NOT NULL;
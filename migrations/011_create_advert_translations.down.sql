@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS advert_translations;
+ALTER TABLE IF EXISTS adverts DROP COLUMN IF EXISTS default_locale;
@@ -0,0 +1,16 @@
+-- adverts.name/description keep the text of the default locale for sorting, export and favorites
+ALTER TABLE adverts
+    ADD COLUMN IF NOT EXISTS default_locale VARCHAR(16) NOT NULL DEFAULT 'en';
+
+CREATE TABLE IF NOT EXISTS advert_translations (
+                                     advert_id   INTEGER NOT NULL REFERENCES adverts(id) ON DELETE CASCADE,
+                                     locale      VARCHAR(16) NOT NULL, -- BCP 47 tag: en, ru, en-GB, ...
+                                     name        VARCHAR(200) NOT NULL,
+                                     description TEXT NOT NULL,
+                                     PRIMARY KEY (advert_id, locale)
+);
+
+INSERT INTO advert_translations (advert_id, locale, name, description)
+SELECT id, default_locale, name, description
+  FROM adverts
+    ON CONFLICT DO NOTHING;
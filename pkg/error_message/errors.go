@@ -1,24 +1,138 @@
 package error_message
 
-import "errors"
+import (
+	"net/http"
+	"strings"
+)
+
+// Error is an API error with a stable machine-readable Code that clients can match on.
+// Status is the HTTP status it is reported with and Title a short summary of its kind;
+// Field names the request field (body field, query or path param) the error is about.
+type Error struct {
+	Code   string
+	Status int
+	Title  string
+	Field  string
+	detail string
+}
+
+// New returns an error that is reported with the given status, code, title and detail.
+func New(status int, code, title, detail string) *Error {
+	return &Error{Code: code, Status: status, Title: title, detail: detail}
+}
+
+func newFieldError(status int, field, code, title, detail string) *Error {
+	return &Error{Code: code, Status: status, Title: title, Field: field, detail: detail}
+}
+
+// Error returns the human-readable detail of the error.
+func (e *Error) Error() string {
+	return e.detail
+}
+
+// ValidationError reports every invalid field of a request at once.
+type ValidationError struct {
+	Errors []*Error
+}
+
+// Validate returns a ValidationError of the given field errors, skipping nils;
+// it returns nil when there are none.
+func Validate(errs ...*Error) error {
+	var invalid []*Error
+	for _, err := range errs {
+		if err != nil {
+			invalid = append(invalid, err)
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: invalid}
+}
 
-type ErrorResponse struct {
-	Error string `json:"error"`
+func (e *ValidationError) Error() string {
+	details := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		details = append(details, err.Error())
+	}
+	return strings.Join(details, "; ")
 }
 
-func NewErrorResponse(msg string) ErrorResponse {
-	return ErrorResponse{Error: msg}
+// Unwrap lets errors.Is find the field errors.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
 }
 
+// ErrValidationFailed is the kind of a ValidationError.
+var ErrValidationFailed = New(http.StatusBadRequest, "validation_failed", "Validation failed", "request contains invalid fields")
+
+// ErrInternal replaces errors that are not meant to be shown to clients.
+var ErrInternal = New(http.StatusInternalServerError, "internal_error", "Internal Server Error", "the server failed to handle the request")
+
 var (
-	ErrWrongPageNumber  = errors.New("wrong page number")
-	ErrWrongSortParams  = errors.New("wrong sort params")
-	ErrWrongAdvertID    = errors.New("wrong advert id")
-	ErrWrongFieldsParam = errors.New("wrong fields param")
-	ErrWrongTitle       = errors.New("title must contain from 1 to 200 characters")
-	ErrWrongDescription = errors.New("description must contain from 1 to 1000 characters")
-	ErrWrongPhotos      = errors.New("advert must contain from 1 to 3 photos")
-	ErrNotPositivePrice = errors.New("price must be positive number")
-	ErrBadRequestBody   = errors.New("invalid request body")
-	ErrAdvertNotFound   = errors.New("advert not found")
+	ErrWrongPageNumber  = newFieldError(http.StatusBadRequest, "page", "wrong_page_number", "Wrong page number", "wrong page number")
+	ErrWrongSortParams  = newFieldError(http.StatusBadRequest, "sort", "wrong_sort_params", "Wrong sort params", "wrong sort params")
+	ErrWrongAdvertID    = newFieldError(http.StatusBadRequest, "id", "wrong_advert_id", "Wrong advert ID", "wrong advert id")
+	ErrWrongFieldsParam = newFieldError(http.StatusBadRequest, "fields", "wrong_fields_param", "Wrong fields param", "fields must be a comma-separated list of name, price, main_photo, description, photos, created_at, favorite_count, view_count")
+	ErrWrongTitle       = newFieldError(http.StatusBadRequest, "name", "wrong_title", "Wrong title", "title must contain from 1 to 200 characters")
+	ErrWrongDescription = newFieldError(http.StatusBadRequest, "description", "wrong_description", "Wrong description", "description must contain from 1 to 1000 characters")
+	ErrWrongPhotos      = newFieldError(http.StatusBadRequest, "photos", "wrong_photos", "Wrong photos", "advert must contain from 1 to 3 photos")
+	ErrNotPositivePrice = newFieldError(http.StatusBadRequest, "price", "not_positive_price", "Price is not positive", "price must be positive number")
+	ErrWrongPriceFormat = newFieldError(http.StatusBadRequest, "price", "wrong_price_format", "Wrong price format", "price must be a number")
+	ErrMissingName      = newFieldError(http.StatusBadRequest, "name", "missing_name", "Missing name", "name is required")
+	ErrBadRequestBody   = New(http.StatusBadRequest, "bad_request_body", "Invalid request body", "invalid request body")
+	ErrAdvertNotFound   = New(http.StatusNotFound, "advert_not_found", "Advert not found", "advert not found")
+
+	ErrWrongLocale        = newFieldError(http.StatusBadRequest, "locale", "wrong_locale", "Wrong locale", "locale must be a language tag like 'en' or 'ru'")
+	ErrWrongLang          = newFieldError(http.StatusBadRequest, "lang", "wrong_lang", "Wrong lang param", "lang must be a language tag like 'en' or 'ru'")
+	ErrWrongTranslation   = newFieldError(http.StatusBadRequest, "translations", "wrong_translation", "Wrong translation", "translations must map language tags like 'en' or 'ru' to a name of 1 to 200 and a description of up to 1000 characters")
+	ErrMissingDefaultText = newFieldError(http.StatusBadRequest, "locale", "missing_default_text", "Missing default text", "advert must have a name in its default locale")
+
+	ErrWrongFilterParams = New(http.StatusBadRequest, "wrong_filter_params", "Wrong filter params", "wrong filter params: min_price/max_price must be non-negative numbers, from/to dates in YYYY-MM-DD format")
+	ErrWrongDateRange    = New(http.StatusBadRequest, "wrong_date_range", "Wrong date range", "date range must not exceed 366 days")
+	ErrWrongBuckets      = newFieldError(http.StatusBadRequest, "buckets", "wrong_buckets", "Wrong price buckets", "buckets must be a comma-separated ascending list of non-negative prices")
+
+	ErrWrongImportFormat  = newFieldError(http.StatusBadRequest, "format", "wrong_import_format", "Wrong import format", "import format must be 'csv' or 'ndjson'")
+	ErrWrongImportHeader  = New(http.StatusBadRequest, "wrong_import_header", "Wrong CSV header", "csv header must contain name, description, price and photos columns and may contain locale")
+	ErrUnknownImportField = New(http.StatusBadRequest, "unknown_import_field", "Unknown import field", "import rows may only contain name, description, price, photos, locale and translations, the owner is the importing user")
+	ErrTooManyImportRows  = New(http.StatusBadRequest, "too_many_import_rows", "Too many import rows", "import file contains too many rows")
+	ErrImportTooLarge     = New(http.StatusRequestEntityTooLarge, "import_too_large", "Import file too large", "import file is too large")
+	ErrImportJobNotFound  = New(http.StatusNotFound, "import_job_not_found", "Import job not found", "import job not found")
+	ErrWrongExportFormat  = newFieldError(http.StatusBadRequest, "format", "wrong_export_format", "Wrong export format", "export format must be 'csv' or 'ndjson'")
+
+	ErrWrongPhotoID       = newFieldError(http.StatusBadRequest, "photoID", "wrong_photo_id", "Wrong photo ID", "wrong photo id")
+	ErrWrongPhotoURL      = newFieldError(http.StatusBadRequest, "url", "wrong_photo_url", "Wrong photo URL", "photo url must not be empty")
+	ErrWrongPhotoPosition = newFieldError(http.StatusBadRequest, "position", "wrong_photo_position", "Wrong photo position", "photo position must not be negative")
+	ErrWrongPhotoOrder    = newFieldError(http.StatusBadRequest, "photo_ids", "wrong_photo_order", "Wrong photo order", "photo order must list every photo of the advert exactly once")
+	ErrPhotoNotFound      = New(http.StatusNotFound, "photo_not_found", "Photo not found", "photo not found")
+
+	ErrPhotoTooLarge        = newFieldError(http.StatusRequestEntityTooLarge, "file", "photo_too_large", "Photo too large", "photo file is too large")
+	ErrUnsupportedPhotoType = newFieldError(http.StatusUnsupportedMediaType, "file", "unsupported_photo_type", "Unsupported photo type", "photo must be a jpeg, png, gif or webp image")
+	ErrMissingPhotoFile     = newFieldError(http.StatusBadRequest, "file", "missing_photo_file", "Missing photo file", "multipart field 'file' is required")
+	ErrMediaNotFound        = New(http.StatusNotFound, "media_not_found", "Media not found", "media not found")
+
+	ErrMissingUserID    = New(http.StatusUnauthorized, "missing_user_id", "Missing user ID", "X-User-ID header is required")
+	ErrFavoriteNotFound = New(http.StatusNotFound, "favorite_not_found", "Favorite not found", "advert is not in favorites")
+
+	ErrWrongSearchName     = newFieldError(http.StatusBadRequest, "name", "wrong_search_name", "Wrong search name", "search name must contain from 1 to 200 characters")
+	ErrWrongEmail          = newFieldError(http.StatusBadRequest, "email", "wrong_email", "Wrong e-mail address", "wrong e-mail address")
+	ErrWrongSearchID       = newFieldError(http.StatusBadRequest, "searchID", "wrong_search_id", "Wrong saved search ID", "wrong saved search id")
+	ErrSavedSearchNotFound = New(http.StatusNotFound, "saved_search_not_found", "Saved search not found", "saved search not found")
+
+	ErrWrongWebhookURL     = newFieldError(http.StatusBadRequest, "url", "wrong_webhook_url", "Wrong webhook URL", "webhook url must be an absolute http or https url")
+	ErrWrongWebhookEvents  = newFieldError(http.StatusBadRequest, "events", "wrong_webhook_events", "Wrong webhook events", "webhook events must be a non-empty list of advert.created, advert.updated, advert.deleted")
+	ErrWrongWebhookID      = newFieldError(http.StatusBadRequest, "webhookID", "wrong_webhook_id", "Wrong webhook ID", "wrong webhook id")
+	ErrWebhookNotFound     = New(http.StatusNotFound, "webhook_not_found", "Webhook not found", "webhook not found")
+	ErrWrongDeliveryID     = newFieldError(http.StatusBadRequest, "deliveryID", "wrong_delivery_id", "Wrong delivery ID", "wrong delivery id")
+	ErrWrongDeliveryStatus = newFieldError(http.StatusBadRequest, "status", "wrong_delivery_status", "Wrong delivery status", "delivery status must be pending, delivered or dead")
+	ErrDeliveryNotFound    = New(http.StatusNotFound, "delivery_not_found", "Delivery not found", "undelivered webhook delivery not found")
+
+	ErrWrongLastEventID = New(http.StatusBadRequest, "wrong_last_event_id", "Wrong last event ID", "last event id must be an id received from the stream")
+
+	ErrWrongAdminToken = New(http.StatusUnauthorized, "wrong_admin_token", "Wrong admin token", "admin token is missing or invalid")
+	ErrWrongLimit      = newFieldError(http.StatusBadRequest, "limit", "wrong_limit", "Wrong limit", "limit must be a positive number")
 )
@@ -0,0 +1,133 @@
+package error_message
+
+import "golang.org/x/text/language"
+
+// Message is the title and detail of an error in one language.
+type Message struct {
+	Title  string
+	Detail string
+}
+
+// Languages are the languages errors are reported in; the first one is the fallback
+// and holds the messages the errors are declared with.
+var Languages = []language.Tag{language.English, language.Russian}
+
+var matcher = language.NewMatcher(Languages)
+
+// translations holds the messages of every language but English, keyed by error code.
+var translations = map[language.Tag]map[string]Message{
+	language.Russian: {
+		"validation_failed": {"Ошибка валидации", "запрос содержит некорректные поля"},
+		"internal_error":    {"Внутренняя ошибка сервера", "сервер не смог обработать запрос"},
+		"not_found":         {"Не найдено", "ресурс не найден"},
+		"method_not_allowed": {
+			"Метод не поддерживается", "метод не поддерживается для этого ресурса",
+		},
+
+		"wrong_page_number": {"Неверный номер страницы", "неверный номер страницы"},
+		"wrong_sort_params": {"Неверные параметры сортировки", "неверные параметры сортировки"},
+		"wrong_advert_id":   {"Неверный ID объявления", "неверный id объявления"},
+		"wrong_fields_param": {
+			"Неверный параметр fields",
+			"fields должен быть списком через запятую из name, price, main_photo, description, photos, created_at, favorite_count, view_count",
+		},
+		"wrong_title":        {"Неверное название", "название должно содержать от 1 до 200 символов"},
+		"wrong_description":  {"Неверное описание", "описание должно содержать от 1 до 1000 символов"},
+		"wrong_photos":       {"Неверные фотографии", "объявление должно содержать от 1 до 3 фотографий"},
+		"not_positive_price": {"Неположительная цена", "цена должна быть положительным числом"},
+		"wrong_price_format": {"Неверный формат цены", "цена должна быть числом"},
+		"missing_name":       {"Не указано название", "название обязательно"},
+		"bad_request_body":   {"Некорректное тело запроса", "некорректное тело запроса"},
+		"advert_not_found":   {"Объявление не найдено", "объявление не найдено"},
+
+		"wrong_locale": {"Неверный язык", "язык должен быть языковым тегом, например 'en' или 'ru'"},
+		"wrong_lang":   {"Неверный параметр lang", "lang должен быть языковым тегом, например 'en' или 'ru'"},
+		"wrong_translation": {
+			"Неверный перевод",
+			"translations должен сопоставлять языковым тегам, например 'en' или 'ru', название от 1 до 200 и описание до 1000 символов",
+		},
+		"missing_default_text": {"Нет текста на основном языке", "у объявления должно быть название на основном языке"},
+
+		"wrong_filter_params": {
+			"Неверные параметры фильтра",
+			"неверные параметры фильтра: min_price/max_price должны быть неотрицательными числами, даты from/to — в формате YYYY-MM-DD",
+		},
+		"wrong_date_range": {"Неверный диапазон дат", "диапазон дат не должен превышать 366 дней"},
+		"wrong_buckets": {
+			"Неверные ценовые интервалы",
+			"buckets должен быть списком неотрицательных цен через запятую в порядке возрастания",
+		},
+
+		"wrong_import_format": {"Неверный формат импорта", "формат импорта должен быть 'csv' или 'ndjson'"},
+		"wrong_import_header": {"Неверный заголовок CSV", "заголовок csv должен содержать столбцы name, description, price и photos и может содержать locale"},
+		"unknown_import_field": {
+			"Неизвестное поле импорта",
+			"строки импорта могут содержать только name, description, price, photos, locale и translations, владелец — импортирующий пользователь",
+		},
+		"too_many_import_rows": {"Слишком много строк для импорта", "файл импорта содержит слишком много строк"},
+		"import_too_large":     {"Слишком большой файл импорта", "файл импорта слишком большой"},
+		"import_job_not_found": {"Задача импорта не найдена", "задача импорта не найдена"},
+		"wrong_export_format":  {"Неверный формат экспорта", "формат экспорта должен быть 'csv' или 'ndjson'"},
+
+		"wrong_photo_id":       {"Неверный ID фотографии", "неверный id фотографии"},
+		"wrong_photo_url":      {"Неверный URL фотографии", "url фотографии не должен быть пустым"},
+		"wrong_photo_position": {"Неверная позиция фотографии", "позиция фотографии не должна быть отрицательной"},
+		"wrong_photo_order": {
+			"Неверный порядок фотографий",
+			"порядок фотографий должен содержать каждую фотографию объявления ровно один раз",
+		},
+		"photo_not_found": {"Фотография не найдена", "фотография не найдена"},
+
+		"photo_too_large":        {"Слишком большая фотография", "файл фотографии слишком большой"},
+		"unsupported_photo_type": {"Неподдерживаемый тип фотографии", "фотография должна быть изображением jpeg, png, gif или webp"},
+		"missing_photo_file":     {"Нет файла фотографии", "поле multipart 'file' обязательно"},
+		"media_not_found":        {"Файл не найден", "файл не найден"},
+
+		"missing_user_id":    {"Не указан ID пользователя", "заголовок X-User-ID обязателен"},
+		"favorite_not_found": {"Нет в избранном", "объявления нет в избранном"},
+
+		"wrong_search_name":      {"Неверное название поиска", "название поиска должно содержать от 1 до 200 символов"},
+		"wrong_email":            {"Неверный адрес e-mail", "неверный адрес e-mail"},
+		"wrong_search_id":        {"Неверный ID сохранённого поиска", "неверный id сохранённого поиска"},
+		"saved_search_not_found": {"Сохранённый поиск не найден", "сохранённый поиск не найден"},
+
+		"wrong_webhook_url": {"Неверный URL вебхука", "url вебхука должен быть абсолютным http или https url"},
+		"wrong_webhook_events": {
+			"Неверные события вебхука",
+			"события вебхука должны быть непустым списком из advert.created, advert.updated, advert.deleted",
+		},
+		"wrong_webhook_id":      {"Неверный ID вебхука", "неверный id вебхука"},
+		"webhook_not_found":     {"Вебхук не найден", "вебхук не найден"},
+		"wrong_delivery_id":     {"Неверный ID доставки", "неверный id доставки"},
+		"wrong_delivery_status": {"Неверный статус доставки", "статус доставки должен быть pending, delivered или dead"},
+		"delivery_not_found":    {"Доставка не найдена", "недоставленное событие вебхука не найдено"},
+
+		"wrong_last_event_id": {"Неверный ID последнего события", "id последнего события должен быть получен из потока"},
+
+		"wrong_admin_token": {"Неверный токен администратора", "токен администратора отсутствует или неверен"},
+		"wrong_limit":       {"Неверный лимит", "limit должен быть положительным числом"},
+	},
+}
+
+// NegotiateLanguage picks the best of Languages for an Accept-Language header,
+// falling back to English when the header is empty, malformed or matches none of them.
+func NegotiateLanguage(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return Languages[0]
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Languages[0]
+	}
+	return Languages[index]
+}
+
+// Message returns the title and detail of e in lang, one of Languages.
+// Errors without a translation keep their English messages.
+func (e *Error) Message(lang language.Tag) Message {
+	if msg, ok := translations[lang][e.Code]; ok {
+		return msg
+	}
+	return Message{Title: e.Title, Detail: e.detail}
+}
@@ -0,0 +1,68 @@
+package error_message
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	cases := map[string]language.Tag{
+		"":                         language.English,
+		"ru":                       language.Russian,
+		"ru-RU,ru;q=0.9,en;q=0.8":  language.Russian,
+		"en-US,en;q=0.9,ru;q=0.8":  language.English,
+		"de-DE,ru;q=0.5":           language.Russian,
+		"de, fr":                   language.English,
+		"not a ; valid ;; header=": language.English,
+	}
+	for header, want := range cases {
+		assert.Equal(t, want, NegotiateLanguage(header), header)
+	}
+}
+
+func TestError_Message(t *testing.T) {
+	assert.Equal(t, Message{Title: "Объявление не найдено", Detail: "объявление не найдено"}, ErrAdvertNotFound.Message(language.Russian))
+	assert.Equal(t, Message{Title: "Advert not found", Detail: "advert not found"}, ErrAdvertNotFound.Message(language.English))
+
+	untranslated := New(418, "teapot", "I'm a teapot", "short and stout")
+	assert.Equal(t, Message{Title: "I'm a teapot", Detail: "short and stout"}, untranslated.Message(language.Russian))
+}
+
+// TestTranslations makes sure every error declared in errors.go has a Russian message.
+func TestTranslations(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "errors.go", nil, 0)
+	require.NoError(t, err)
+
+	codeArg := map[string]int{"New": 1, "newFieldError": 2}
+	var codes []string
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn, ok := call.Fun.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if i, ok := codeArg[fn.Name]; ok {
+			if lit, ok := call.Args[i].(*ast.BasicLit); ok {
+				code, err := strconv.Unquote(lit.Value)
+				require.NoError(t, err)
+				codes = append(codes, code)
+			}
+		}
+		return true
+	})
+	require.NotEmpty(t, codes)
+
+	for _, code := range codes {
+		assert.Contains(t, translations[language.Russian], code)
+	}
+}
@@ -0,0 +1,37 @@
+package graph
+
+import (
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/graph/model"
+	pkgmodel "github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// toAdvertFilter converts the GraphQL filter, including the whole createdTo day.
+func toAdvertFilter(in *model.AdvertFilter) (pkgmodel.AdvertFilter, error) {
+	var filter pkgmodel.AdvertFilter
+	if in == nil {
+		return filter, nil
+	}
+	if in.MinPrice != nil && *in.MinPrice < 0 || in.MaxPrice != nil && *in.MaxPrice < 0 {
+		return filter, error_message.ErrWrongFilterParams
+	}
+	filter.MinPrice, filter.MaxPrice = in.MinPrice, in.MaxPrice
+	if in.CreatedFrom != nil {
+		from, err := time.Parse(time.DateOnly, *in.CreatedFrom)
+		if err != nil {
+			return filter, error_message.ErrWrongFilterParams
+		}
+		filter.CreatedFrom = &from
+	}
+	if in.CreatedTo != nil {
+		to, err := time.Parse(time.DateOnly, *in.CreatedTo)
+		if err != nil {
+			return filter, error_message.ErrWrongFilterParams
+		}
+		to = to.AddDate(0, 0, 1)
+		filter.CreatedTo = &to
+	}
+	return filter, nil
+}
@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// loaderWait is how long a loader collects keys before fetching them.
+// Sibling fields are resolved concurrently, so one tick gathers a whole list.
+const loaderWait = 2 * time.Millisecond
+
+// loaderTimeout bounds a fetch. The fetch serves every caller of the batch, so it
+// does not end with the request of the first one.
+const loaderTimeout = 10 * time.Second
+
+type loadResult[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// batchLoader fetches the keys requested within loaderWait with a single call
+// and remembers the results for the lifetime of the loader (one request).
+type batchLoader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	results map[K]*loadResult[V]
+	pending []K
+}
+
+func newBatchLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *batchLoader[K, V] {
+	return &batchLoader[K, V]{fetch: fetch, results: make(map[K]*loadResult[V])}
+}
+
+// Load returns the value of key; keys missing from the fetched map get the zero value.
+func (l *batchLoader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	res, ok := l.results[key]
+	if !ok {
+		res = &loadResult[V]{done: make(chan struct{})}
+		l.results[key] = res
+		l.pending = append(l.pending, key)
+		if len(l.pending) == 1 {
+			time.AfterFunc(loaderWait, func() { l.dispatch(ctx) })
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (l *batchLoader[K, V]) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loaderTimeout)
+	defer cancel()
+	values, err := l.fetch(ctx, keys)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		res := l.results[key]
+		res.value, res.err = values[key], err
+		close(res.done)
+	}
+}
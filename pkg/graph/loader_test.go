@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchLoader_FirstCallerGoesAway(t *testing.T) {
+	loader := newBatchLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		// The fetch outlives the request that started the batch
+		time.Sleep(10 * time.Millisecond)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		_, ok := ctx.Deadline()
+		assert.True(t, ok, "the fetch has its own timeout")
+		values := make(map[int]string, len(keys))
+		for _, key := range keys {
+			values[key] = "advert"
+		}
+		return values, nil
+	})
+
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := loader.Load(first, 1)
+		firstErr <- err
+	}()
+	time.Sleep(time.Millisecond)
+	go func() {
+		time.Sleep(loaderWait)
+		cancel()
+	}()
+
+	value, err := loader.Load(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "advert", value)
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+}
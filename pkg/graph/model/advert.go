@@ -0,0 +1,9 @@
+package model
+
+import "github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+// Advert is the GraphQL advert. Lists only carry the summary,
+// the remaining fields are loaded on demand.
+type Advert struct {
+	service.AdvertSummary
+}
@@ -0,0 +1,143 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type AdvertFilter struct {
+	MinPrice *float64 `json:"minPrice,omitempty"`
+	MaxPrice *float64 `json:"maxPrice,omitempty"`
+	// Creation date range in YYYY-MM-DD, both inclusive
+	CreatedFrom *string `json:"createdFrom,omitempty"`
+	CreatedTo   *string `json:"createdTo,omitempty"`
+}
+
+type AdvertSort struct {
+	Field AdvertSortField `json:"field"`
+	Order SortOrder       `json:"order"`
+}
+
+type PhotoVariant struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type Query struct {
+}
+
+type AdvertSortField string
+
+const (
+	AdvertSortFieldPrice   AdvertSortField = "PRICE"
+	AdvertSortFieldDate    AdvertSortField = "DATE"
+	AdvertSortFieldPopular AdvertSortField = "POPULAR"
+)
+
+var AllAdvertSortField = []AdvertSortField{
+	AdvertSortFieldPrice,
+	AdvertSortFieldDate,
+	AdvertSortFieldPopular,
+}
+
+func (e AdvertSortField) IsValid() bool {
+	switch e {
+	case AdvertSortFieldPrice, AdvertSortFieldDate, AdvertSortFieldPopular:
+		return true
+	}
+	return false
+}
+
+func (e AdvertSortField) String() string {
+	return string(e)
+}
+
+func (e *AdvertSortField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AdvertSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AdvertSortField", str)
+	}
+	return nil
+}
+
+func (e AdvertSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AdvertSortField) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AdvertSortField) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "ASC"
+	SortOrderDesc SortOrder = "DESC"
+)
+
+var AllSortOrder = []SortOrder{
+	SortOrderAsc,
+	SortOrderDesc,
+}
+
+func (e SortOrder) IsValid() bool {
+	switch e {
+	case SortOrderAsc, SortOrderDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortOrder) String() string {
+	return string(e)
+}
+
+func (e *SortOrder) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortOrder", str)
+	}
+	return nil
+}
+
+func (e SortOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SortOrder) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SortOrder) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
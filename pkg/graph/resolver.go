@@ -0,0 +1,9 @@
+package graph
+
+import "github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+// Resolver is the root resolver, it is shared by all requests.
+type Resolver struct {
+	advertSvc service.AdvertService
+	photoSvc  service.PhotoService
+}
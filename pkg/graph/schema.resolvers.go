@@ -0,0 +1,111 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/graph/model"
+	model1 "github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+)
+
+// Description is the resolver for the description field.
+func (r *advertResolver) Description(ctx context.Context, obj *model.Advert) (string, error) {
+	detail, err := loadersFor(ctx).details.Load(ctx, obj.ID)
+	return detail.Description, err
+}
+
+// FavoriteCount is the resolver for the favoriteCount field.
+func (r *advertResolver) FavoriteCount(ctx context.Context, obj *model.Advert) (int, error) {
+	detail, err := loadersFor(ctx).details.Load(ctx, obj.ID)
+	return detail.FavoriteCount, err
+}
+
+// ViewCount is the resolver for the viewCount field.
+func (r *advertResolver) ViewCount(ctx context.Context, obj *model.Advert) (int, error) {
+	detail, err := loadersFor(ctx).details.Load(ctx, obj.ID)
+	return int(detail.ViewCount), err
+}
+
+// Photos is the resolver for the photos field.
+func (r *advertResolver) Photos(ctx context.Context, obj *model.Advert) ([]model1.Photo, error) {
+	photos, err := loadersFor(ctx).photos.Load(ctx, obj.ID)
+	if photos == nil {
+		photos = []model1.Photo{}
+	}
+	return photos, err
+}
+
+// Variants is the resolver for the variants field.
+func (r *photoResolver) Variants(ctx context.Context, obj *model1.Photo) ([]model.PhotoVariant, error) {
+	variants := make([]model.PhotoVariant, 0, len(obj.Variants))
+	for name, url := range obj.Variants {
+		variants = append(variants, model.PhotoVariant{Name: name, URL: url})
+	}
+	sort.Slice(variants, func(i, j int) bool { return variants[i].Name < variants[j].Name })
+	return variants, nil
+}
+
+// Advert is the resolver for the advert field.
+func (r *queryResolver) Advert(ctx context.Context, id int) (*model.Advert, error) {
+	if id < 1 {
+		return nil, error_message.ErrWrongAdvertID
+	}
+	detail, err := r.advertSvc.GetByID(ctx, id, service.SummaryFields)
+	if errors.Is(err, error_message.ErrAdvertNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &model.Advert{AdvertSummary: detail.AdvertSummary}, nil
+}
+
+// Adverts is the resolver for the adverts field.
+func (r *queryResolver) Adverts(ctx context.Context, page *int, filter *model.AdvertFilter, sort *model.AdvertSort) ([]model.Advert, error) {
+	p := 1
+	if page != nil {
+		p = *page
+	}
+	if p < 1 {
+		return nil, error_message.ErrWrongPageNumber
+	}
+	advertFilter, err := toAdvertFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	var sortField, sortOrder string
+	if sort != nil {
+		sortField, sortOrder = strings.ToLower(string(sort.Field)), strings.ToLower(string(sort.Order))
+	}
+
+	details, err := r.advertSvc.List(ctx, advertFilter, p, sortField, sortOrder, service.SummaryFields)
+	if err != nil {
+		return nil, err
+	}
+	adverts := make([]model.Advert, 0, len(details))
+	for _, d := range details {
+		adverts = append(adverts, model.Advert{AdvertSummary: d.AdvertSummary})
+	}
+	return adverts, nil
+}
+
+// Advert returns AdvertResolver implementation.
+func (r *Resolver) Advert() AdvertResolver { return &advertResolver{r} }
+
+// Photo returns PhotoResolver implementation.
+func (r *Resolver) Photo() PhotoResolver { return &photoResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type advertResolver struct{ *Resolver }
+type photoResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
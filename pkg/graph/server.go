@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/graph/model"
+	pkgmodel "github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+)
+
+// Complexity of the fields that cost more than a column read.
+const (
+	// maxPhotosPerAdvert bounds the photo list when estimating its complexity
+	maxPhotosPerAdvert = 10
+	// detailComplexity is charged for fields that need the full advert
+	detailComplexity = 5
+	// defaultComplexityLimit is used when no limit is configured
+	defaultComplexityLimit = 1000
+)
+
+// detailFields are the advert fields resolved through the details loader.
+var detailFields = service.NewAdvertFields(service.FieldDescription, service.FieldFavoriteCount, service.FieldViewCount)
+
+type loadersKey struct{}
+
+// loaders are created for every request so that their cache never outlives it.
+type loaders struct {
+	photos  *batchLoader[int, []pkgmodel.Photo]
+	details *batchLoader[int, service.AdvertDetail]
+}
+
+// NewHandler returns the GraphQL HTTP handler (POST and GET) over the advert and photo
+// services. Queries with a complexity above complexityLimit are rejected.
+func NewHandler(advertSvc service.AdvertService, photoSvc service.PhotoService, complexityLimit int) http.Handler {
+	if complexityLimit <= 0 {
+		complexityLimit = defaultComplexityLimit
+	}
+	cfg := Config{Resolvers: &Resolver{advertSvc: advertSvc, photoSvc: photoSvc}}
+	cfg.Complexity.Query.Adverts = func(childComplexity int, _ *int, _ *model.AdvertFilter, _ *model.AdvertSort) int {
+		return service.ListPageSize * childComplexity
+	}
+	cfg.Complexity.Advert.Photos = func(childComplexity int) int {
+		return maxPhotosPerAdvert * childComplexity
+	}
+	detail := func(int) int { return detailComplexity }
+	cfg.Complexity.Advert.Description = detail
+	cfg.Complexity.Advert.FavoriteCount = detail
+	cfg.Complexity.Advert.ViewCount = detail
+
+	srv := handler.New(NewExecutableSchema(cfg))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.FixedComplexityLimit(complexityLimit))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersKey{}, newLoaders(advertSvc, photoSvc))
+		srv.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func newLoaders(advertSvc service.AdvertService, photoSvc service.PhotoService) *loaders {
+	return &loaders{
+		photos: newBatchLoader(photoSvc.ListByAdverts),
+		details: newBatchLoader(func(ctx context.Context, ids []int) (map[int]service.AdvertDetail, error) {
+			return advertSvc.GetByIDs(ctx, ids, detailFields)
+		}),
+	}
+}
+
+func loadersFor(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,144 @@
+package graph_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/graph"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPhotoService serves photos from a map and records every batch
+type countingPhotoService struct {
+	service.PhotoService
+	mu      sync.Mutex
+	photos  map[int][]model.Photo
+	batches [][]int
+}
+
+func (s *countingPhotoService) ListByAdverts(_ context.Context, advertIDs []int) (map[int][]model.Photo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, advertIDs)
+	return s.photos, nil
+}
+
+type gqlResponse struct {
+	Data   json.RawMessage
+	Errors []struct{ Message string }
+}
+
+func query(t *testing.T, h http.Handler, q string) gqlResponse {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"query": q})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var resp gqlResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp), rec.Body.String())
+	return resp
+}
+
+func TestGraphQL_AdvertsBatchPhotos(t *testing.T) {
+	advertSvc := new(mocks.MockAdvertService)
+	photoSvc := &countingPhotoService{photos: map[int][]model.Photo{
+		1: {{ID: 10, AdvertID: 1, URL: "http://img/1", Position: 1, Variants: map[string]string{"thumb": "http://img/1t"}}},
+		2: {{ID: 20, AdvertID: 2, URL: "http://img/2", Position: 1}, {ID: 21, AdvertID: 2, URL: "http://img/3", Position: 2}},
+	}}
+	h := graph.NewHandler(advertSvc, photoSvc, 0)
+	min := 100.0
+
+	advertSvc.On("List", mock.Anything, model.AdvertFilter{MinPrice: &min}, 2, "price", "desc", service.SummaryFields, []string(nil)).Return([]service.AdvertDetail{
+		{AdvertSummary: service.AdvertSummary{ID: 1, Name: "Bike", Price: 300}},
+		{AdvertSummary: service.AdvertSummary{ID: 2, Name: "Car", Price: 200}},
+		{AdvertSummary: service.AdvertSummary{ID: 3, Name: "Boat", Price: 100}},
+	}, nil).Once()
+
+	resp := query(t, h, `{
+		adverts(page: 2, filter: {minPrice: 100}, sort: {field: PRICE, order: DESC}) {
+			id name photos { id url variants { name url } }
+		}
+	}`)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"adverts": [
+		{"id": "1", "name": "Bike", "photos": [{"id": "10", "url": "http://img/1", "variants": [{"name": "thumb", "url": "http://img/1t"}]}]},
+		{"id": "2", "name": "Car", "photos": [{"id": "20", "url": "http://img/2", "variants": []}, {"id": "21", "url": "http://img/3", "variants": []}]},
+		{"id": "3", "name": "Boat", "photos": []}
+	]}`, string(resp.Data))
+	require.Len(t, photoSvc.batches, 1)
+	assert.ElementsMatch(t, []int{1, 2, 3}, photoSvc.batches[0])
+	advertSvc.AssertExpectations(t)
+}
+
+func TestGraphQL_AdvertsBatchDetails(t *testing.T) {
+	advertSvc := new(mocks.MockAdvertService)
+	h := graph.NewHandler(advertSvc, &countingPhotoService{}, 0)
+
+	advertSvc.On("List", mock.Anything, model.AdvertFilter{}, 1, "", "", service.SummaryFields, []string(nil)).Return([]service.AdvertDetail{
+		{AdvertSummary: service.AdvertSummary{ID: 1, Name: "Bike"}},
+		{AdvertSummary: service.AdvertSummary{ID: 2, Name: "Car"}},
+	}, nil).Once()
+	// One read for every advert of the page
+	advertSvc.On("GetByIDs", mock.Anything, mock.MatchedBy(func(ids []int) bool {
+		return assert.ObjectsAreEqual([]int{1, 2}, slices.Sorted(slices.Values(ids)))
+	}), mock.Anything, []string(nil)).Return(map[int]service.AdvertDetail{
+		1: {Description: "Red bike", ViewCount: 4},
+		2: {Description: "Old car", ViewCount: 7},
+	}, nil).Once()
+
+	resp := query(t, h, `{ adverts { id description viewCount } }`)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"adverts": [
+		{"id": "1", "description": "Red bike", "viewCount": 4},
+		{"id": "2", "description": "Old car", "viewCount": 7}
+	]}`, string(resp.Data))
+	advertSvc.AssertExpectations(t)
+}
+
+func TestGraphQL_Advert(t *testing.T) {
+	advertSvc := new(mocks.MockAdvertService)
+	h := graph.NewHandler(advertSvc, &countingPhotoService{}, 0)
+
+	summary := service.AdvertSummary{ID: 9, Name: "Bike", MainPhotoURL: "http://img", Price: 120}
+	advertSvc.On("GetByID", mock.Anything, 9, service.SummaryFields, []string(nil)).Return(service.AdvertDetail{AdvertSummary: summary}, nil).Once()
+	// Both detail fields share one read of the full advert
+	advertSvc.On("GetByIDs", mock.Anything, []int{9}, service.NewAdvertFields(
+		service.FieldDescription, service.FieldFavoriteCount, service.FieldViewCount), []string(nil)).
+		Return(map[int]service.AdvertDetail{9: {AdvertSummary: summary, Description: "Red", ViewCount: 40}}, nil).Once()
+	advertSvc.On("GetByID", mock.Anything, 10, service.SummaryFields, []string(nil)).Return(service.AdvertDetail{}, error_message.ErrAdvertNotFound).Once()
+
+	resp := query(t, h, `{ advert(id: 9) { name mainPhotoURL description viewCount } }`)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"advert": {"name": "Bike", "mainPhotoURL": "http://img", "description": "Red", "viewCount": 40}}`, string(resp.Data))
+
+	resp = query(t, h, `{ advert(id: 10) { name } }`)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"advert": null}`, string(resp.Data))
+	advertSvc.AssertExpectations(t)
+}
+
+func TestGraphQL_ComplexityLimit(t *testing.T) {
+	advertSvc := new(mocks.MockAdvertService)
+	h := graph.NewHandler(advertSvc, &countingPhotoService{}, 100)
+
+	// 10 adverts * (name + description 5 + 10 photos * (url + 1 variant * name)) is far above 100
+	resp := query(t, h, `{ adverts { name description photos { url variants { name } } } }`)
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "complexity")
+	advertSvc.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,213 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	advertv1 "github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/pb/advert/v1"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UserIDKey is the metadata key with the current user, like the X-User-ID header of the REST API.
+const UserIDKey = "x-user-id"
+
+// AdvertServer implements advertv1.AdvertServiceServer on top of service.AdvertService.
+type AdvertServer struct {
+	advertv1.UnimplementedAdvertServiceServer
+	advertSvc service.AdvertService
+}
+
+func NewAdvertServer(svc service.AdvertService) *AdvertServer {
+	return &AdvertServer{advertSvc: svc}
+}
+
+func (s *AdvertServer) Create(ctx context.Context, req *advertv1.CreateRequest) (*advertv1.CreateResponse, error) {
+	var ownerID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(UserIDKey); len(values) > 0 {
+			ownerID = values[0]
+		}
+	}
+
+	id, err := s.advertSvc.Create(ctx, service.CreateAdvertInput{
+		OwnerID:      ownerID,
+		Name:         req.GetName(),
+		Description:  req.GetDescription(),
+		Photos:       req.GetPhotos(),
+		Price:        req.GetPrice(),
+		Locale:       req.GetLocale(),
+		Translations: advertTexts(req.GetTranslations()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &advertv1.CreateResponse{Id: int64(id)}, nil
+}
+
+func (s *AdvertServer) GetByID(ctx context.Context, req *advertv1.GetByIDRequest) (*advertv1.Advert, error) {
+	if req.GetId() < 1 {
+		return nil, toStatus(error_message.ErrWrongAdvertID)
+	}
+
+	fields := service.SummaryFields
+	if req.GetFields() {
+		fields = service.AllAdvertFields
+	}
+	detail, err := s.advertSvc.GetByID(ctx, int(req.GetId()), fields)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &advertv1.Advert{
+		Summary:       toSummary(detail.AdvertSummary),
+		Description:   detail.Description,
+		AllPhotosUrls: detail.AllPhotosURLs,
+		FavoriteCount: int64(detail.FavoriteCount),
+		ViewCount:     detail.ViewCount,
+	}, nil
+}
+
+func (s *AdvertServer) List(ctx context.Context, req *advertv1.ListRequest) (*advertv1.ListResponse, error) {
+	page := int(req.GetPage())
+	if page == 0 {
+		page = 1
+	}
+	if page < 0 {
+		return nil, toStatus(error_message.ErrWrongPageNumber)
+	}
+	if err := service.ValidateSort(req.GetSortField(), req.GetSortOrder()); err != nil {
+		return nil, toStatus(err)
+	}
+	filter := model.AdvertFilter{MinPrice: req.MinPrice, MaxPrice: req.MaxPrice}
+	if req.CreatedFrom != nil {
+		from := req.GetCreatedFrom().AsTime()
+		filter.CreatedFrom = &from
+	}
+	if req.CreatedTo != nil {
+		to := req.GetCreatedTo().AsTime()
+		filter.CreatedTo = &to
+	}
+	if filter.MinPrice != nil && *filter.MinPrice < 0 || filter.MaxPrice != nil && *filter.MaxPrice < 0 {
+		return nil, toStatus(error_message.ErrWrongFilterParams)
+	}
+
+	adverts, err := s.advertSvc.List(ctx, filter, page, req.GetSortField(), req.GetSortOrder(), service.SummaryFields)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &advertv1.ListResponse{Adverts: make([]*advertv1.AdvertSummary, 0, len(adverts))}
+	for _, ad := range adverts {
+		resp.Adverts = append(resp.Adverts, toSummary(ad.AdvertSummary))
+	}
+	return resp, nil
+}
+
+func (s *AdvertServer) Update(ctx context.Context, req *advertv1.UpdateRequest) (*advertv1.UpdateResponse, error) {
+	if req.GetId() < 1 {
+		return nil, toStatus(error_message.ErrWrongAdvertID)
+	}
+
+	input := service.UpdateAdvertInput{
+		Name:         req.Name,
+		Description:  req.Description,
+		Price:        req.Price,
+		Locale:       req.Locale,
+		Translations: advertTextUpdates(req.GetTranslations(), req.GetRemoveTranslations()),
+	}
+	if req.Photos != nil {
+		photos := req.GetPhotos().GetUrls()
+		input.Photos = &photos
+	}
+	if err := s.advertSvc.Update(ctx, int(req.GetId()), input); err != nil {
+		return nil, toStatus(err)
+	}
+	return &advertv1.UpdateResponse{}, nil
+}
+
+func (s *AdvertServer) Delete(ctx context.Context, req *advertv1.DeleteRequest) (*advertv1.DeleteResponse, error) {
+	if req.GetId() < 1 {
+		return nil, toStatus(error_message.ErrWrongAdvertID)
+	}
+
+	if err := s.advertSvc.Delete(ctx, int(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &advertv1.DeleteResponse{}, nil
+}
+
+// advertTexts converts the translations of a request, nil if there are none.
+func advertTexts(translations map[string]*advertv1.AdvertText) map[string]service.AdvertText {
+	if len(translations) == 0 {
+		return nil
+	}
+	texts := make(map[string]service.AdvertText, len(translations))
+	for locale, text := range translations {
+		texts[locale] = service.AdvertText{Name: text.GetName(), Description: text.GetDescription()}
+	}
+	return texts
+}
+
+// advertTextUpdates converts the translations of an update request, removed locales
+// becoming nil texts; nil if the request changes no translations.
+func advertTextUpdates(translations map[string]*advertv1.AdvertText, removed []string) map[string]*service.AdvertText {
+	if len(translations) == 0 && len(removed) == 0 {
+		return nil
+	}
+	texts := make(map[string]*service.AdvertText, len(translations)+len(removed))
+	for locale, text := range translations {
+		texts[locale] = &service.AdvertText{Name: text.GetName(), Description: text.GetDescription()}
+	}
+	for _, locale := range removed {
+		texts[locale] = nil
+	}
+	return texts
+}
+
+func toSummary(ad service.AdvertSummary) *advertv1.AdvertSummary {
+	return &advertv1.AdvertSummary{
+		Id:                int64(ad.ID),
+		Name:              ad.Name,
+		MainPhotoUrl:      ad.MainPhotoURL,
+		MainPhotoThumbUrl: ad.MainPhotoThumbURL,
+		Price:             ad.Price,
+	}
+}
+
+// toStatus maps service errors to gRPC status codes by their HTTP status: 401 is
+// Unauthenticated, 404 NotFound, 413 ResourceExhausted and other client errors
+// InvalidArgument (415 included, the payload itself is wrong). Unexpected errors are
+// logged and reported as Internal without details.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	var validationErr *error_message.ValidationError
+	if errors.As(err, &validationErr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	var apiErr *error_message.Error
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.Status == http.StatusUnauthorized:
+			return status.Error(codes.Unauthenticated, err.Error())
+		case apiErr.Status == http.StatusNotFound:
+			return status.Error(codes.NotFound, err.Error())
+		case apiErr.Status == http.StatusRequestEntityTooLarge:
+			return status.Error(codes.ResourceExhausted, err.Error())
+		case apiErr.Status >= 400 && apiErr.Status < 500:
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	log.Printf("grpc: %v", err)
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,226 @@
+package grpcserver_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/grpcserver"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	advertv1 "github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/pb/advert/v1"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newClient serves svc over an in-memory bufconn listener
+func newClient(t *testing.T, svc service.AdvertService) advertv1.AdvertServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpcserver.NewServer(svc)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return advertv1.NewAdvertServiceClient(conn)
+}
+
+func TestAdvertServer_Create(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	client := newClient(t, svc)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), grpcserver.UserIDKey, "seller")
+
+	svc.On("Create", mock.Anything, service.CreateAdvertInput{
+		OwnerID: "seller", Name: "Bike", Description: "Red", Photos: []string{"http://img"}, Price: 120,
+	}).Return(9, nil).Once()
+	svc.On("Create", mock.Anything, mock.MatchedBy(func(in service.CreateAdvertInput) bool { return in.Name == "" })).
+		Return(0, error_message.ErrWrongTitle).Once()
+
+	resp, err := client.Create(ctx, &advertv1.CreateRequest{Name: "Bike", Description: "Red", Photos: []string{"http://img"}, Price: 120})
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), resp.GetId())
+
+	_, err = client.Create(ctx, &advertv1.CreateRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, error_message.ErrWrongTitle.Error(), status.Convert(err).Message())
+	svc.AssertExpectations(t)
+}
+
+func TestAdvertServer_CreateTranslations(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	client := newClient(t, svc)
+
+	svc.On("Create", mock.Anything, service.CreateAdvertInput{
+		Name: "Велосипед", Description: "Красный", Photos: []string{"http://img"}, Price: 120, Locale: "ru",
+		Translations: map[string]service.AdvertText{"en": {Name: "Bike", Description: "Red"}},
+	}).Return(3, nil).Once()
+
+	resp, err := client.Create(context.Background(), &advertv1.CreateRequest{
+		Name: "Велосипед", Description: "Красный", Photos: []string{"http://img"}, Price: 120, Locale: "ru",
+		Translations: map[string]*advertv1.AdvertText{"en": {Name: "Bike", Description: "Red"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), resp.GetId())
+	svc.AssertExpectations(t)
+}
+
+func TestAdvertServer_CreateErrorCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"MissingName", error_message.ErrMissingName, codes.InvalidArgument},
+		{"WrongTranslation", fmt.Errorf("service.Create: %w", error_message.ErrWrongTranslation), codes.InvalidArgument},
+		{"Validation", error_message.Validate(error_message.ErrMissingName, error_message.ErrWrongPhotos), codes.InvalidArgument},
+		{"NotFound", error_message.ErrAdvertNotFound, codes.NotFound},
+		{"Unauthenticated", error_message.ErrMissingUserID, codes.Unauthenticated},
+		{"TooLarge", error_message.ErrPhotoTooLarge, codes.ResourceExhausted},
+		{"UnsupportedType", error_message.ErrUnsupportedPhotoType, codes.InvalidArgument},
+		{"Internal", errors.New("db down"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(mocks.MockAdvertService)
+			client := newClient(t, svc)
+			svc.On("Create", mock.Anything, mock.Anything).Return(0, tt.err).Once()
+
+			_, err := client.Create(context.Background(), &advertv1.CreateRequest{})
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestAdvertServer_GetByID(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	client := newClient(t, svc)
+	ctx := context.Background()
+
+	svc.On("GetByID", mock.Anything, 9, service.AllAdvertFields, []string(nil)).Return(service.AdvertDetail{
+		AdvertSummary: service.AdvertSummary{ID: 9, Name: "Bike", MainPhotoURL: "http://img", Price: 120},
+		Description:   "Red",
+		AllPhotosURLs: []string{"http://img"},
+		FavoriteCount: 2,
+		ViewCount:     40,
+	}, nil).Once()
+	svc.On("GetByID", mock.Anything, 10, service.SummaryFields, []string(nil)).Return(service.AdvertDetail{}, error_message.ErrAdvertNotFound).Once()
+	svc.On("GetByID", mock.Anything, 11, service.SummaryFields, []string(nil)).Return(service.AdvertDetail{}, errors.New("connection refused")).Once()
+
+	advert, err := client.GetByID(ctx, &advertv1.GetByIDRequest{Id: 9, Fields: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Bike", advert.GetSummary().GetName())
+	assert.Equal(t, int64(2), advert.GetFavoriteCount())
+	assert.Equal(t, int64(40), advert.GetViewCount())
+
+	cases := []struct {
+		name string
+		id   int64
+		code codes.Code
+	}{
+		{"NotFound", 10, codes.NotFound},
+		{"Internal", 11, codes.Internal},
+		{"WrongID", 0, codes.InvalidArgument},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := client.GetByID(ctx, &advertv1.GetByIDRequest{Id: tc.id})
+			assert.Equal(t, tc.code, status.Code(err))
+		})
+	}
+	svc.AssertExpectations(t)
+}
+
+func TestAdvertServer_List(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	client := newClient(t, svc)
+	ctx := context.Background()
+	min := 100.0
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	svc.On("List", mock.Anything, mock.MatchedBy(func(f model.AdvertFilter) bool {
+		return *f.MinPrice == 100 && f.MaxPrice == nil && f.CreatedFrom.Equal(from) && f.CreatedTo == nil
+	}), 1, "price", "desc", service.SummaryFields, []string(nil)).Return([]service.AdvertDetail{
+		{AdvertSummary: service.AdvertSummary{ID: 9, Name: "Bike", Price: 120}},
+	}, nil).Once()
+
+	resp, err := client.List(ctx, &advertv1.ListRequest{
+		SortField: "price", SortOrder: "desc", MinPrice: &min, CreatedFrom: timestamppb.New(from),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetAdverts(), 1)
+	assert.Equal(t, int64(9), resp.GetAdverts()[0].GetId())
+
+	_, err = client.List(ctx, &advertv1.ListRequest{SortField: "name", SortOrder: "asc"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	// A sort field alone is what the service rejects, not a gRPC-only rule
+	_, err = client.List(ctx, &advertv1.ListRequest{SortField: "price"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.List(ctx, &advertv1.ListRequest{Page: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	svc.AssertExpectations(t)
+}
+
+func TestAdvertServer_UpdateDelete(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	client := newClient(t, svc)
+	ctx := context.Background()
+	price := 150.0
+
+	svc.On("Update", mock.Anything, 9, mock.MatchedBy(func(in service.UpdateAdvertInput) bool {
+		return in.Name == nil && *in.Price == 150 && len(*in.Photos) == 0
+	})).Return(nil).Once()
+	svc.On("Update", mock.Anything, 10, mock.MatchedBy(func(in service.UpdateAdvertInput) bool {
+		return in.Photos == nil
+	})).Return(error_message.ErrAdvertNotFound).Once()
+	svc.On("Delete", mock.Anything, 9).Return(nil).Once()
+
+	_, err := client.Update(ctx, &advertv1.UpdateRequest{Id: 9, Price: &price, Photos: &advertv1.PhotoList{}})
+	assert.NoError(t, err)
+	_, err = client.Update(ctx, &advertv1.UpdateRequest{Id: 10, Price: &price})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.Delete(ctx, &advertv1.DeleteRequest{Id: 9})
+	assert.NoError(t, err)
+	svc.AssertExpectations(t)
+}
+
+func TestAdvertServer_UpdateTranslations(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	client := newClient(t, svc)
+	locale := "ru"
+
+	svc.On("Update", mock.Anything, 9, service.UpdateAdvertInput{
+		Locale: &locale,
+		Translations: map[string]*service.AdvertText{
+			"en": {Name: "Bike", Description: "Red"},
+			"de": nil,
+		},
+	}).Return(nil).Once()
+
+	_, err := client.Update(context.Background(), &advertv1.UpdateRequest{
+		Id:                 9,
+		Locale:             &locale,
+		Translations:       map[string]*advertv1.AdvertText{"en": {Name: "Bike", Description: "Red"}},
+		RemoveTranslations: []string{"de"},
+	})
+	assert.NoError(t, err)
+	svc.AssertExpectations(t)
+}
@@ -0,0 +1,33 @@
+package grpcserver
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+
+	advertv1 "github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/pb/advert/v1"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer returns a gRPC server with the advert service registered.
+// A panicking handler is logged and answered with Internal instead of crashing the process.
+func NewServer(svc service.AdvertService, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(recoverUnary))
+	s := grpc.NewServer(opts...)
+	advertv1.RegisterAdvertServiceServer(s, NewAdvertServer(svc))
+	return s
+}
+
+func recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("grpc: panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return next(ctx, req)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminTokenHeader carries the token required by /api/admin endpoints.
+const AdminTokenHeader = "X-Admin-Token"
+
+// defaultDuplicatesLimit is how many duplicate groups are returned without ?limit.
+const defaultDuplicatesLimit = 50
+
+// AdminHandler is responsible for HTTP endpoints under /api/admin.
+type AdminHandler struct {
+	hashSvc service.PhotoHashService
+}
+
+// ListDuplicatePhotos godoc
+// @Summary     Find photos reused across owners
+// @Description Groups of visually identical photos (same perceptual hash) used by adverts of different owners
+// @Tags        admin
+// @Produce     json
+// @Param       X-Admin-Token header string true  "Admin token"
+// @Param       limit         query  int    false "Maximum number of groups (default 50)"
+// @Success     200 {array}  service.DuplicateGroup
+// @Failure     400 {object} handler.Problem
+// @Failure     401 {object} handler.Problem
+// @Failure     500 {object} handler.Problem
+// @Router      /admin/duplicate-photos [get]
+func (h *AdminHandler) ListDuplicatePhotos(c echo.Context) error {
+	limit := defaultDuplicatesLimit
+	if raw := c.QueryParam("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return error_message.ErrWrongLimit
+		}
+		limit = n
+	}
+
+	groups, err := h.hashSvc.FindDuplicates(c.Request().Context(), limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, groups)
+}
+
+// requireAdminToken rejects requests without the configured admin token.
+func requireAdminToken(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			got := c.Request().Header.Get(AdminTokenHeader)
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				return error_message.ErrWrongAdminToken
+			}
+			return next(c)
+		}
+	}
+}
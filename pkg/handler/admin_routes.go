@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/labstack/echo/v4"
+)
+
+// NewAdminHandler registers admin routes with Swagger annotations.
+// Every route requires the X-Admin-Token header to match token.
+func NewAdminHandler(e *echo.Echo, hashSvc service.PhotoHashService, token string) *AdminHandler {
+	h := &AdminHandler{hashSvc: hashSvc}
+
+	// Admin group
+	g := e.Group("/api/admin", requireAdminToken(token))
+
+	g.GET("/duplicate-photos", h.ListDuplicatePhotos)
+
+	return h
+}
@@ -1,35 +1,58 @@
 package handler
 
-// CreateAdvertRequest — payload для POST /api/adverts
+import "time"
+
+// CreateAdvertRequest — payload для POST /api/adverts.
+// Name и Description — на основном языке объявления Locale (по умолчанию en),
+// Translations — тексты на других языках
 type CreateAdvertRequest struct {
-	Name        string   `json:"name" validate:"required"`
-	Description string   `json:"description" validate:"required"`
-	Photos      []string `json:"photos" validate:"required"`
-	Price       float64  `json:"price" validate:"required"`
+	Name         string                       `json:"name" validate:"required"`
+	Description  string                       `json:"description" validate:"required"`
+	Photos       []string                     `json:"photos" validate:"required"`
+	Price        float64                      `json:"price" validate:"required"`
+	Locale       string                       `json:"locale,omitempty" example:"ru"`
+	Translations map[string]AdvertTextRequest `json:"translations,omitempty"`
+}
+
+// AdvertTextRequest — название и описание объявления на одном языке
+type AdvertTextRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 // AdvertSummaryResponse — элемент списка GET /api/adverts
 type AdvertSummaryResponse struct {
-	ID           int     `json:"id"`
-	Name         string  `json:"name"`
-	MainPhotoURL string  `json:"main_photo_url"`
-	Price        float64 `json:"price"`
+	ID                int     `json:"id"`
+	Name              string  `json:"name"`
+	MainPhotoURL      string  `json:"main_photo_url"`
+	MainPhotoThumbURL string  `json:"main_photo_thumb_url,omitempty"`
+	Price             float64 `json:"price"`
 }
 
-// GetAdvertResponse — ответ GET /api/adverts/:id
+// GetAdvertResponse — ответ GET /api/adverts/:id и элемент списка GET /api/adverts.
+// Содержит только поля, запрошенные параметром fields (id есть всегда)
 type GetAdvertResponse struct {
-	ID            int      `json:"id"`
-	Name          string   `json:"name"`
-	MainPhotoURL  string   `json:"main_photo_url"`
-	Price         float64  `json:"price"`
-	Description   *string  `json:"description,omitempty"`
-	AllPhotosURLs []string `json:"all_photos_urls,omitempty"`
+	ID                int        `json:"id"`
+	Name              *string    `json:"name,omitempty"`
+	Locale            string     `json:"locale,omitempty"`
+	MainPhotoURL      *string    `json:"main_photo_url,omitempty"`
+	MainPhotoThumbURL string     `json:"main_photo_thumb_url,omitempty"`
+	Price             *float64   `json:"price,omitempty"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	Description       *string    `json:"description,omitempty"`
+	AllPhotosURLs     []string   `json:"all_photos_urls,omitempty"`
+	FavoriteCount     *int       `json:"favorite_count,omitempty"`
+	ViewCount         *int64     `json:"view_count,omitempty"`
 }
 
-// UpdateAdvertRequest — payload для PUT /api/adverts/:id
+// UpdateAdvertRequest — payload для PUT /api/adverts/:id.
+// Locale меняет основной язык, Name и Description — текст на нём.
+// Translations задаёт тексты перечисленных языков, null удаляет язык
 type UpdateAdvertRequest struct {
-	Name        *string   `json:"name,omitempty"`
-	Description *string   `json:"description,omitempty"`
-	Photos      *[]string `json:"photos,omitempty"`
-	Price       *float64  `json:"price,omitempty"`
+	Name         *string                       `json:"name,omitempty"`
+	Description  *string                       `json:"description,omitempty"`
+	Photos       *[]string                     `json:"photos,omitempty"`
+	Price        *float64                      `json:"price,omitempty"`
+	Locale       *string                       `json:"locale,omitempty" example:"ru"`
+	Translations map[string]*AdvertTextRequest `json:"translations,omitempty"`
 }
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdvertCacheHandler is responsible for HTTP endpoints under /api/admin/cache.
+type AdvertCacheHandler struct {
+	svc service.CachingAdvertService
+}
+
+// GetAdvertCacheMetrics godoc
+// @Summary     Advert cache metrics
+// @Description Hits, misses and errors of the advert read cache since the start of the process
+// @Tags        admin
+// @Produce     json
+// @Param       X-Admin-Token header string true "Admin token"
+// @Success     200 {object} service.AdvertCacheMetrics
+// @Failure     401 {object} handler.Problem
+// @Router      /admin/cache [get]
+func (h *AdvertCacheHandler) GetAdvertCacheMetrics(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.svc.Metrics())
+}
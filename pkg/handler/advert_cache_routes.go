@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/labstack/echo/v4"
+)
+
+// NewAdvertCacheHandler registers advert cache routes with Swagger annotations.
+// Like other admin routes they require the X-Admin-Token header to match token.
+func NewAdvertCacheHandler(e *echo.Echo, svc service.CachingAdvertService, token string) *AdvertCacheHandler {
+	h := &AdvertCacheHandler{svc: svc}
+
+	// Cache group
+	g := e.Group("/api/admin/cache", requireAdminToken(token))
+
+	g.GET("", h.GetAdvertCacheMetrics)
+
+	return h
+}
@@ -1,19 +1,27 @@
 package handler
 
 import (
-	"errors"
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+// UserIDHeader carries the ID of the user making the request.
+const UserIDHeader = "X-User-ID"
+
+// SessionIDHeader identifies an anonymous browsing session for view deduplication.
+const SessionIDHeader = "X-Session-ID"
+
 // AdvertHandler is responsible for HTTP endpoints under /api/adverts.
 type AdvertHandler struct {
 	advertSvc service.AdvertService
+	views     service.ViewService
 }
 
 // NewAdvertHandler creates a new instance and registers routes in Echo.
@@ -25,39 +33,34 @@ type AdvertHandler struct {
 // @Tags        adverts
 // @Accept      json
 // @Produce     json
-// @Param       advert body     handler.CreateAdvertRequest true "Advertisement payload"
+// @Param       advert    body     handler.CreateAdvertRequest true  "Advertisement payload"
+// @Param       X-User-ID header   string                      false "Owner of the advert (anonymous if omitted)"
 // @Success     201    {object} map[string]int           "New advert ID"
-// @Failure     400    {object} handler.ErrorResponse
-// @Failure     500    {object} handler.ErrorResponse
+// @Failure     400    {object} handler.Problem
+// @Failure     500    {object} handler.Problem
 // @Router      /adverts [post]
 func (h *AdvertHandler) CreateAdvert(c echo.Context) error {
 	var req CreateAdvertRequest
 	if err := c.Bind(&req); err != nil {
-		return SendError(c, http.StatusBadRequest, error_message.ErrBadRequestBody)
+		return error_message.ErrBadRequestBody
 	}
-	if err := c.Validate(&req); err != nil {
-		return SendError(c, http.StatusBadRequest, err)
+	if err := service.ValidateAdvertPayload(&req.Name, &req.Description, &req.Photos, &req.Price); err != nil {
+		return err
 	}
 
 	svcInput := service.CreateAdvertInput{
-		Name:        req.Name,
-		Description: req.Description,
-		Photos:      req.Photos,
-		Price:       req.Price,
+		OwnerID:      c.Request().Header.Get(UserIDHeader),
+		Name:         req.Name,
+		Description:  req.Description,
+		Photos:       req.Photos,
+		Price:        req.Price,
+		Locale:       req.Locale,
+		Translations: advertTexts(req.Translations),
 	}
 
 	newID, err := h.advertSvc.Create(c.Request().Context(), svcInput)
 	if err != nil {
-		// Handle all possible "known" errors
-		switch {
-		case errors.Is(err, error_message.ErrWrongTitle),
-			errors.Is(err, error_message.ErrWrongDescription),
-			errors.Is(err, error_message.ErrWrongPhotos),
-			errors.Is(err, error_message.ErrNotPositivePrice):
-			return SendError(c, http.StatusBadRequest, err)
-		default:
-			return SendError(c, http.StatusInternalServerError, err)
-		}
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, map[string]int{"id": newID})
@@ -65,99 +68,210 @@ func (h *AdvertHandler) CreateAdvert(c echo.Context) error {
 
 // GetAdvertByID godoc
 // @Summary     Get an advertisement by ID
-// @Description Retrieve a single advert detail by its ID
+// @Description Retrieve a single advert detail by its ID; every call counts as a view
+// @Description (once per session or IP within the dedup window)
 // @Tags        adverts
 // @Accept      json
 // @Produce     json
-// @Param       id    path     int                     true "Advert ID"
+// @Param       id           path     int    true  "Advert ID"
+// @Param       fields       query    string false "Comma-separated fields: name, price, main_photo, description, photos, created_at, favorite_count, view_count (default name,main_photo,price; true — all)"
+// @Param       lang         query    string false "Locale of the names and descriptions, e.g. ru (overrides Accept-Language)"
+// @Param       Accept-Language header   string false "Preferred locales of the names and descriptions, the default locale of each advert is the fallback"
+// @Param       X-Session-ID header   string false "Session telling apart viewers behind one client IP for view deduplication"
 // @Success     200   {object} handler.GetAdvertResponse
-// @Failure     400   {object} handler.ErrorResponse
-// @Failure     404   {object} handler.ErrorResponse
+// @Failure     400   {object} handler.Problem
+// @Failure     404   {object} handler.Problem
 // @Router      /adverts/{id} [get]
 func (h *AdvertHandler) GetAdvertByID(c echo.Context) error {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil || id < 1 {
-		return SendError(c, http.StatusBadRequest, error_message.ErrWrongAdvertID)
+		return error_message.ErrWrongAdvertID
 	}
 
-	fields := false
-	if fieldParams := c.QueryParam("fields"); fieldParams != "" {
-		if f, err := strconv.ParseBool(fieldParams); err != nil {
-			return SendError(c, http.StatusBadRequest, error_message.ErrWrongFieldsParam)
-		} else {
-			fields = f
-		}
+	fields, err := service.ParseAdvertFields(c.QueryParam("fields"), service.SummaryFields)
+	if err != nil {
+		return err
+	}
+	locales, err := preferredLocales(c)
+	if err != nil {
+		return err
 	}
 
-	adv, err := h.advertSvc.GetByID(c.Request().Context(), id, fields)
+	adv, err := h.advertSvc.GetByID(c.Request().Context(), id, fields, locales...)
 	if err != nil {
-		if errors.Is(err, error_message.ErrAdvertNotFound) {
-			return SendError(c, http.StatusNotFound, err)
-		}
-		return SendError(c, http.StatusInternalServerError, err)
+		return err
 	}
+	h.views.Record(id, viewerKey(c))
+
+	setContentLanguage(c, adv.Locale)
+	return c.JSON(http.StatusOK, newAdvertResponse(adv, fields))
+}
 
-	response := GetAdvertResponse{
-		ID:           adv.ID,
-		Name:         adv.Name,
-		MainPhotoURL: adv.MainPhotoURL,
-		Price:        adv.Price,
+// newAdvertResponse copies the requested fields of the advert to the response.
+func newAdvertResponse(adv service.AdvertDetail, fields service.AdvertFields) GetAdvertResponse {
+	response := GetAdvertResponse{ID: adv.ID, Locale: adv.Locale}
+	if fields.Has(service.FieldName) {
+		response.Name = &adv.Name
+	}
+	if fields.Has(service.FieldMainPhoto) {
+		response.MainPhotoURL = &adv.MainPhotoURL
+		response.MainPhotoThumbURL = adv.MainPhotoThumbURL
+	}
+	if fields.Has(service.FieldPrice) {
+		response.Price = &adv.Price
+	}
+	if fields.Has(service.FieldCreatedAt) {
+		response.CreatedAt = &adv.CreatedAt
 	}
-	if fields {
+	if fields.Has(service.FieldDescription) {
 		response.Description = &adv.Description
+	}
+	if fields.Has(service.FieldPhotos) {
 		response.AllPhotosURLs = adv.AllPhotosURLs
 	}
-	return c.JSON(http.StatusOK, response)
+	if fields.Has(service.FieldFavoriteCount) {
+		response.FavoriteCount = &adv.FavoriteCount
+	}
+	if fields.Has(service.FieldViewCount) {
+		response.ViewCount = &adv.ViewCount
+	}
+	return response
 }
 
 // ListAdverts godoc
 // @Summary     List advertisements
-// @Description Get list of adverts with optional pagination and sorting
+// @Description Get list of adverts with optional filters, pagination and sorting
 // @Tags        adverts
 // @Accept      json
 // @Produce     json
-// @Param       page  query    int                     false "Page number"
-// @Param       size  query    int                     false "Page size"
-// @Param       sort  query    string                  false "Sort by field, e.g. price_asc"
+// @Param       page      query    int                     false "Page number"
+// @Param       size      query    int                     false "Page size"
+// @Param       sort      query    string                  false "Sort by field, e.g. price_asc, date_desc or popular_desc"
+// @Param       min_price query    number                  false "Minimum price"
+// @Param       max_price query    number                  false "Maximum price"
+// @Param       from      query    string                  false "Created on or after the date (YYYY-MM-DD)"
+// @Param       to        query    string                  false "Created on or before the date (YYYY-MM-DD)"
+// @Param       fields    query    string                  false "Comma-separated fields like in GET /adverts/{id} (default name,main_photo,price)"
+// @Param       lang      query    string                  false "Locale of the names and descriptions, e.g. ru (overrides Accept-Language)"
+// @Param       Accept-Language header   string                  false "Preferred locales of the names and descriptions, the default locale of each advert is the fallback"
 // @Success     200   {array}  handler.GetAdvertResponse
-// @Failure     500   {object} handler.ErrorResponse
+// @Failure     400   {object} handler.Problem
+// @Failure     500   {object} handler.Problem
 // @Router      /adverts [get]
 func (h *AdvertHandler) ListAdverts(c echo.Context) error {
-	// 1) Parse page (default is 1)
-	pageParam := c.QueryParam("page")
-	page := 1
-	if pageParam != "" {
-		if p, err := strconv.Atoi(pageParam); err == nil && p > 0 {
-			page = p
-		}
+	// 1) Parse page (default is 1) and filters
+	page := parsePageParam(c.QueryParam("page"))
+	filter, err := parseAdvertFilter(c)
+	if err != nil {
+		return err
+	}
+
+	fields, err := service.ParseAdvertFields(c.QueryParam("fields"), service.SummaryFields)
+	if err != nil {
+		return err
+	}
+	locales, err := preferredLocales(c)
+	if err != nil {
+		return err
 	}
 
 	// 2) Read sortParam; if empty — leave sortField and sortOrder as empty strings
-	sortParam := c.QueryParam("sort")
-	var sortField, sortOrder string
-	if sortParam != "" {
-		parts := strings.SplitN(sortParam, "_", 2)
-		if len(parts) != 2 {
-			return c.JSON(http.StatusBadRequest, map[string]string{
-				"error": "invalid sort parameter; expected 'price_asc', 'date_desc', etc.",
-			})
-		}
-		sortField = parts[0] // "price" or "date" (or invalid string)
-		sortOrder = parts[1] // "asc" or "desc" (or invalid string)
+	sortField, sortOrder, ok := parseSortParam(c.QueryParam("sort"))
+	if !ok {
+		return error_message.ErrWrongSortParams
 	}
 	// If sortParam == "", then sortField == "" and sortOrder == "" —
 	// and the service will apply the default “id ASC”.
 
 	// 3) Call the service, passing empty strings if no sorting
-	listResp, err := h.advertSvc.List(c.Request().Context(), page, sortField, sortOrder)
+	listResp, err := h.advertSvc.List(c.Request().Context(), filter, page, sortField, sortOrder, fields, locales...)
 	if err != nil {
 		// For example, if sortField/sortOrder turned out invalid, the service will return an error.
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	// 4) Send response
-	return c.JSON(http.StatusOK, listResp)
+	response := make([]GetAdvertResponse, 0, len(listResp))
+	for _, adv := range listResp {
+		response = append(response, newAdvertResponse(adv, fields))
+	}
+	setContentLanguage(c, "")
+	return c.JSON(http.StatusOK, response)
+}
+
+// viewerKey identifies the viewer for view deduplication: the client IP, split by
+// the session if the client sends one. The session only tells apart viewers behind
+// one IP, so a client cannot count its views again by making up sessions elsewhere.
+func viewerKey(c echo.Context) string {
+	key := "ip:" + c.RealIP()
+	if session := c.Request().Header.Get(SessionIDHeader); session != "" {
+		key += " session:" + session
+	}
+	return key
+}
+
+// parseAdvertFilter reads the list filters from the query: min_price, max_price and
+// an inclusive from/to range of creation dates (YYYY-MM-DD).
+func parseAdvertFilter(c echo.Context) (model.AdvertFilter, error) {
+	return parseFilterValues(c.QueryParam)
+}
+
+// parseFilterValues parses the list filters looked up by name with param.
+func parseFilterValues(param func(name string) string) (model.AdvertFilter, error) {
+	var filter model.AdvertFilter
+	for name, dst := range map[string]**float64{"min_price": &filter.MinPrice, "max_price": &filter.MaxPrice} {
+		if raw := param(name); raw != "" {
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil || v < 0 {
+				return model.AdvertFilter{}, error_message.ErrWrongFilterParams
+			}
+			*dst = &v
+		}
+	}
+	if raw := param("from"); raw != "" {
+		from, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return model.AdvertFilter{}, error_message.ErrWrongFilterParams
+		}
+		filter.CreatedFrom = &from
+	}
+	if raw := param("to"); raw != "" {
+		to, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return model.AdvertFilter{}, error_message.ErrWrongFilterParams
+		}
+		// The whole "to" day is included
+		to = to.AddDate(0, 0, 1)
+		filter.CreatedTo = &to
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice ||
+		filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return model.AdvertFilter{}, error_message.ErrWrongFilterParams
+	}
+	return filter, nil
+}
+
+// parsePageParam returns the 1-based page number, falling back to 1 for missing or invalid values.
+func parsePageParam(pageParam string) int {
+	if p, err := strconv.Atoi(pageParam); err == nil && p > 0 {
+		return p
+	}
+	return 1
+}
+
+// parseSortParam splits "price_asc"-like values into field and order.
+// An empty param yields empty strings; ok is false for malformed values.
+func parseSortParam(sortParam string) (sortField, sortOrder string, ok bool) {
+	if sortParam == "" {
+		return "", "", true
+	}
+	parts := strings.SplitN(sortParam, "_", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	// "price" or "date" and "asc" or "desc" (or invalid strings — the service validates them)
+	return parts[0], parts[1], true
 }
 
 // UpdateAdvert godoc
@@ -169,37 +283,32 @@ func (h *AdvertHandler) ListAdverts(c echo.Context) error {
 // @Param       id     path     int                     true "Advert ID"
 // @Param       advert body     handler.UpdateAdvertRequest true "Advertisement payload"
 // @Success     200    {object} handler.GetAdvertResponse
-// @Failure     400    {object} handler.ErrorResponse
-// @Failure     404    {object} handler.ErrorResponse
-// @Failure     500    {object} handler.ErrorResponse
+// @Failure     400    {object} handler.Problem
+// @Failure     404    {object} handler.Problem
+// @Failure     500    {object} handler.Problem
 // @Router      /adverts/{id} [put]
 func (h *AdvertHandler) UpdateAdvert(c echo.Context) error {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil || id < 1 {
-		return SendError(c, http.StatusBadRequest, error_message.ErrWrongAdvertID)
+		return error_message.ErrWrongAdvertID
 	}
 	var req UpdateAdvertRequest
 	if err := c.Bind(&req); err != nil {
-		return SendError(c, http.StatusBadRequest, error_message.ErrBadRequestBody)
+		return error_message.ErrBadRequestBody
 	}
 
 	update := service.UpdateAdvertInput{
-		Name:        req.Name,
-		Description: req.Description,
-		Photos:      req.Photos,
-		Price:       req.Price,
+		Name:         req.Name,
+		Description:  req.Description,
+		Photos:       req.Photos,
+		Price:        req.Price,
+		Locale:       req.Locale,
+		Translations: advertTextUpdates(req.Translations),
 	}
 
 	if err := h.advertSvc.Update(c.Request().Context(), id, update); err != nil {
-		switch {
-		case errors.Is(err, error_message.ErrAdvertNotFound):
-			return SendError(c, http.StatusNotFound, err)
-		case errors.Is(err, error_message.ErrNotPositivePrice):
-			return SendError(c, http.StatusBadRequest, err)
-		default:
-			return SendError(c, http.StatusInternalServerError, err)
-		}
+		return err
 	}
 	return c.NoContent(http.StatusNoContent)
 }
@@ -212,23 +321,18 @@ func (h *AdvertHandler) UpdateAdvert(c echo.Context) error {
 // @Produce     json
 // @Param       id path int true "Advert ID"
 // @Success     204 {string} string "No content"
-// @Failure     400 {object} handler.ErrorResponse
-// @Failure     404 {object} handler.ErrorResponse
+// @Failure     400 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
 // @Router      /adverts/{id} [delete]
 func (h *AdvertHandler) DeleteAdvert(c echo.Context) error {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil || id < 1 {
-		return SendError(c, http.StatusBadRequest, error_message.ErrWrongAdvertID)
+		return error_message.ErrWrongAdvertID
 	}
 
 	if err := h.advertSvc.Delete(c.Request().Context(), id); err != nil {
-		switch {
-		case errors.Is(err, error_message.ErrAdvertNotFound):
-			return SendError(c, http.StatusNotFound, err)
-		default:
-			return SendError(c, http.StatusInternalServerError, err)
-		}
+		return err
 	}
 	return c.NoContent(http.StatusNoContent)
 }
@@ -5,12 +5,15 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-// NewAdvertHandler registers advert routes with Swagger annotations
-func NewAdvertHandler(e *echo.Echo, svc service.AdvertService) *AdvertHandler {
-	h := &AdvertHandler{advertSvc: svc}
+// NewAdvertHandler registers advert routes of both API versions with Swagger annotations;
+// views of GET .../adverts/:id are counted by views.
+// v1 (/api/adverts, also /api/v1/adverts with ServeV1Aliases) keeps its response shapes
+// and is marked deprecated; v2 (/api/v2/adverts) wraps responses in an envelope.
+func NewAdvertHandler(e *echo.Echo, svc service.AdvertService, views service.ViewService) *AdvertHandler {
+	h := &AdvertHandler{advertSvc: svc, views: views}
 
-	// Advert group
-	g := e.Group("/api/adverts")
+	// Advert group, v1
+	g := e.Group("/api/adverts", deprecatedBy(APIv2Prefix+"/adverts"))
 
 	g.POST("", h.CreateAdvert)
 	g.GET("", h.ListAdverts)
@@ -18,5 +21,17 @@ func NewAdvertHandler(e *echo.Echo, svc service.AdvertService) *AdvertHandler {
 	g.PUT("/:id", h.UpdateAdvert)
 	g.DELETE("/:id", h.DeleteAdvert)
 
+	// Export has no v2 counterpart yet
+	e.GET("/api/adverts/export", h.ExportAdverts)
+
+	// Advert group, v2
+	v2 := e.Group(APIv2Prefix + "/adverts")
+
+	v2.POST("", h.CreateAdvertV2)
+	v2.GET("", h.ListAdvertsV2)
+	v2.GET("/:id", h.GetAdvertByIDV2)
+	v2.PUT("/:id", h.UpdateAdvertV2)
+	v2.DELETE("/:id", h.DeleteAdvertV2)
+
 	return h
 }
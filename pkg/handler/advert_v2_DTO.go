@@ -0,0 +1,43 @@
+package handler
+
+import "time"
+
+// AdvertV2 — объявление в ответах /api/v2/adverts.
+// Содержит только поля, запрошенные параметром fields (id есть всегда);
+// Locale — язык названия и описания
+type AdvertV2 struct {
+	ID            int          `json:"id"`
+	Name          *string      `json:"name,omitempty"`
+	Locale        string       `json:"locale,omitempty"`
+	Description   *string      `json:"description,omitempty"`
+	Price         *float64     `json:"price,omitempty"`
+	MainPhoto     *MainPhotoV2 `json:"main_photo,omitempty"`
+	Photos        []string     `json:"photos,omitempty"`
+	CreatedAt     *time.Time   `json:"created_at,omitempty"`
+	FavoriteCount *int         `json:"favorite_count,omitempty"`
+	ViewCount     *int64       `json:"view_count,omitempty"`
+}
+
+// MainPhotoV2 — главная фотография объявления и её миниатюра (если создана)
+type MainPhotoV2 struct {
+	URL      string `json:"url"`
+	ThumbURL string `json:"thumb_url,omitempty"`
+}
+
+// AdvertResponseV2 — конверт ответа v2 с одним объявлением
+type AdvertResponseV2 struct {
+	Data AdvertV2 `json:"data"`
+}
+
+// AdvertListResponseV2 — конверт ответа GET /api/v2/adverts со страницей объявлений
+type AdvertListResponseV2 struct {
+	Data []AdvertV2 `json:"data"`
+	Meta PageMetaV2 `json:"meta"`
+}
+
+// PageMetaV2 — номер страницы, её размер и число объявлений на ней
+type PageMetaV2 struct {
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+	Count   int `json:"count"`
+}
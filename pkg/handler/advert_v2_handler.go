@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateAdvertV2 godoc
+// @Summary     Create a new advertisement
+// @Description Create advertisement with title, description, 1 to 3 photos and a positive price
+// @Tags        adverts-v2
+// @Accept      json
+// @Produce     json
+// @Param       advert    body     handler.CreateAdvertRequest true  "Advertisement payload"
+// @Param       X-User-ID header   string                      false "Owner of the advert (anonymous if omitted)"
+// @Success     201       {object} handler.AdvertResponseV2    "New advert ID, the advert URL is in Location"
+// @Failure     400       {object} handler.Problem
+// @Failure     500       {object} handler.Problem
+// @Router      /adverts [post]
+func (h *AdvertHandler) CreateAdvertV2(c echo.Context) error {
+	var req CreateAdvertRequest
+	if err := c.Bind(&req); err != nil {
+		return error_message.ErrBadRequestBody
+	}
+	if err := service.ValidateAdvertPayload(&req.Name, &req.Description, &req.Photos, &req.Price); err != nil {
+		return err
+	}
+
+	newID, err := h.advertSvc.Create(c.Request().Context(), service.CreateAdvertInput{
+		OwnerID:      c.Request().Header.Get(UserIDHeader),
+		Name:         req.Name,
+		Description:  req.Description,
+		Photos:       req.Photos,
+		Price:        req.Price,
+		Locale:       req.Locale,
+		Translations: advertTexts(req.Translations),
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("%s/adverts/%d", APIv2Prefix, newID))
+	return c.JSON(http.StatusCreated, AdvertResponseV2{Data: AdvertV2{ID: newID}})
+}
+
+// GetAdvertByIDV2 godoc
+// @Summary     Get an advertisement by ID
+// @Description Retrieve a single advert by its ID; every call counts as a view
+// @Description (once per session or IP within the dedup window)
+// @Tags        adverts-v2
+// @Produce     json
+// @Param       id           path     int    true  "Advert ID"
+// @Param       fields       query    string false "Comma-separated fields: name, price, main_photo, description, photos, created_at, favorite_count, view_count (default name,main_photo,price)"
+// @Param       lang         query    string false "Locale of the names and descriptions, e.g. ru (overrides Accept-Language)"
+// @Param       Accept-Language header   string false "Preferred locales of the names and descriptions, the default locale of each advert is the fallback"
+// @Param       X-Session-ID header   string false "Session telling apart viewers behind one client IP for view deduplication"
+// @Success     200 {object} handler.AdvertResponseV2
+// @Failure     400 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Failure     500 {object} handler.Problem
+// @Router      /adverts/{id} [get]
+func (h *AdvertHandler) GetAdvertByIDV2(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		return error_message.ErrWrongAdvertID
+	}
+	fields, err := service.ParseAdvertFields(c.QueryParam("fields"), service.SummaryFields)
+	if err != nil {
+		return err
+	}
+	locales, err := preferredLocales(c)
+	if err != nil {
+		return err
+	}
+
+	adv, err := h.advertSvc.GetByID(c.Request().Context(), id, fields, locales...)
+	if err != nil {
+		return err
+	}
+	h.views.Record(id, viewerKey(c))
+
+	setContentLanguage(c, adv.Locale)
+	return c.JSON(http.StatusOK, AdvertResponseV2{Data: newAdvertV2(adv, fields)})
+}
+
+// ListAdvertsV2 godoc
+// @Summary     List advertisements
+// @Description Get a page of adverts with optional filters and sorting
+// @Tags        adverts-v2
+// @Produce     json
+// @Param       page      query    int    false "Page number (1-based)"
+// @Param       sort      query    string false "Sort by field, e.g. price_asc, date_desc or popular_desc"
+// @Param       min_price query    number false "Minimum price"
+// @Param       max_price query    number false "Maximum price"
+// @Param       from      query    string false "Created on or after the date (YYYY-MM-DD)"
+// @Param       to        query    string false "Created on or before the date (YYYY-MM-DD)"
+// @Param       fields    query    string false "Comma-separated fields like in GET /adverts/{id} (default name,main_photo,price)"
+// @Param       lang      query    string false "Locale of the names and descriptions, e.g. ru (overrides Accept-Language)"
+// @Param       Accept-Language header   string false "Preferred locales of the names and descriptions, the default locale of each advert is the fallback"
+// @Success     200 {object} handler.AdvertListResponseV2
+// @Failure     400 {object} handler.Problem
+// @Failure     500 {object} handler.Problem
+// @Router      /adverts [get]
+func (h *AdvertHandler) ListAdvertsV2(c echo.Context) error {
+	page := 1
+	if raw := c.QueryParam("page"); raw != "" {
+		p, err := strconv.Atoi(raw)
+		if err != nil || p < 1 {
+			return error_message.ErrWrongPageNumber
+		}
+		page = p
+	}
+	filter, err := parseAdvertFilter(c)
+	if err != nil {
+		return err
+	}
+	sortField, sortOrder, ok := parseSortParam(c.QueryParam("sort"))
+	if !ok {
+		return error_message.ErrWrongSortParams
+	}
+	if err := service.ValidateSort(sortField, sortOrder); err != nil {
+		return err
+	}
+	fields, err := service.ParseAdvertFields(c.QueryParam("fields"), service.SummaryFields)
+	if err != nil {
+		return err
+	}
+	locales, err := preferredLocales(c)
+	if err != nil {
+		return err
+	}
+
+	adverts, err := h.advertSvc.List(c.Request().Context(), filter, page, sortField, sortOrder, fields, locales...)
+	if err != nil {
+		return err
+	}
+
+	response := AdvertListResponseV2{
+		Data: make([]AdvertV2, 0, len(adverts)),
+		Meta: PageMetaV2{Page: page, PerPage: service.ListPageSize, Count: len(adverts)},
+	}
+	for _, adv := range adverts {
+		response.Data = append(response.Data, newAdvertV2(adv, fields))
+	}
+	setContentLanguage(c, "")
+	return c.JSON(http.StatusOK, response)
+}
+
+// UpdateAdvertV2 godoc
+// @Summary     Update an advertisement
+// @Description Update the given advertisement fields by ID and return the full advert
+// @Tags        adverts-v2
+// @Accept      json
+// @Produce     json
+// @Param       id     path     int                         true "Advert ID"
+// @Param       advert body     handler.UpdateAdvertRequest true "Advertisement payload"
+// @Success     200    {object} handler.AdvertResponseV2
+// @Failure     400    {object} handler.Problem
+// @Failure     404    {object} handler.Problem
+// @Failure     500    {object} handler.Problem
+// @Router      /adverts/{id} [put]
+func (h *AdvertHandler) UpdateAdvertV2(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		return error_message.ErrWrongAdvertID
+	}
+	var req UpdateAdvertRequest
+	if err := c.Bind(&req); err != nil {
+		return error_message.ErrBadRequestBody
+	}
+	if err := service.ValidateAdvertPayload(req.Name, req.Description, req.Photos, req.Price); err != nil {
+		return err
+	}
+	locales, err := preferredLocales(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	err = h.advertSvc.Update(ctx, id, service.UpdateAdvertInput{
+		Name:         req.Name,
+		Description:  req.Description,
+		Photos:       req.Photos,
+		Price:        req.Price,
+		Locale:       req.Locale,
+		Translations: advertTextUpdates(req.Translations),
+	})
+	if err != nil {
+		return err
+	}
+
+	adv, err := h.advertSvc.GetByID(ctx, id, service.AllAdvertFields, locales...)
+	if err != nil {
+		return err
+	}
+	setContentLanguage(c, adv.Locale)
+	return c.JSON(http.StatusOK, AdvertResponseV2{Data: newAdvertV2(adv, service.AllAdvertFields)})
+}
+
+// DeleteAdvertV2 godoc
+// @Summary     Delete an advertisement
+// @Description Delete advertisement identified by its ID
+// @Tags        adverts-v2
+// @Produce     json
+// @Param       id path int true "Advert ID"
+// @Success     204 {string} string "No content"
+// @Failure     400 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Failure     500 {object} handler.Problem
+// @Router      /adverts/{id} [delete]
+func (h *AdvertHandler) DeleteAdvertV2(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		return error_message.ErrWrongAdvertID
+	}
+
+	if err := h.advertSvc.Delete(c.Request().Context(), id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// newAdvertV2 copies the requested fields of the advert to the v2 representation.
+func newAdvertV2(adv service.AdvertDetail, fields service.AdvertFields) AdvertV2 {
+	advert := AdvertV2{ID: adv.ID, Locale: adv.Locale}
+	if fields.Has(service.FieldName) {
+		advert.Name = &adv.Name
+	}
+	if fields.Has(service.FieldDescription) {
+		advert.Description = &adv.Description
+	}
+	if fields.Has(service.FieldPrice) {
+		advert.Price = &adv.Price
+	}
+	if fields.Has(service.FieldMainPhoto) && adv.MainPhotoURL != "" {
+		advert.MainPhoto = &MainPhotoV2{URL: adv.MainPhotoURL, ThumbURL: adv.MainPhotoThumbURL}
+	}
+	if fields.Has(service.FieldPhotos) {
+		advert.Photos = adv.AllPhotosURLs
+	}
+	if fields.Has(service.FieldCreatedAt) {
+		advert.CreatedAt = &adv.CreatedAt
+	}
+	if fields.Has(service.FieldFavoriteCount) {
+		advert.FavoriteCount = &adv.FavoriteCount
+	}
+	if fields.Has(service.FieldViewCount) {
+		advert.ViewCount = &adv.ViewCount
+	}
+	return advert
+}
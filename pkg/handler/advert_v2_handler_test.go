@@ -0,0 +1,235 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/handler"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// nopViewService ignores views, counting is covered by the service tests
+type nopViewService struct{}
+
+func (nopViewService) Record(int, string)          {}
+func (nopViewService) Flush(context.Context) error { return nil }
+func (nopViewService) Run(context.Context)         {}
+
+// viewerRecorder remembers the viewer keys of recorded views
+type viewerRecorder struct {
+	nopViewService
+	viewers []string
+}
+
+func (r *viewerRecorder) Record(_ int, viewer string) { r.viewers = append(r.viewers, viewer) }
+
+func TestAdvertHandler_ViewerKey(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	views := &viewerRecorder{}
+	e := echo.New()
+	handler.NewAdvertHandler(e, svc, views)
+	svc.On("GetByID", mock.Anything, 7, service.SummaryFields, []string(nil)).Return(service.AdvertDetail{}, nil)
+
+	for _, session := range []string{"", "a", "b"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/adverts/7", nil)
+		req.RemoteAddr = "203.0.113.7:4000"
+		if session != "" {
+			req.Header.Set(handler.SessionIDHeader, session)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+	// Made-up sessions only split the views of the client IP
+	assert.Equal(t, []string{"ip:203.0.113.7", "ip:203.0.113.7 session:a", "ip:203.0.113.7 session:b"}, views.viewers)
+}
+
+func newAdvertServer(svc service.AdvertService) *echo.Echo {
+	e := echo.New()
+	handler.RegisterErrorHandler(e)
+	handler.ServeV1Aliases(e)
+	handler.NewAdvertHandler(e, svc, nopViewService{})
+	return e
+}
+
+func serve(e *echo.Echo, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdvertHandler_V1Aliases(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	e := newAdvertServer(svc)
+
+	detail := service.AdvertDetail{AdvertSummary: service.AdvertSummary{ID: 7, Name: "Bike", MainPhotoURL: "http://img", Price: 120}}
+	svc.On("GetByID", mock.Anything, 7, service.SummaryFields, []string(nil)).Return(detail, nil).Twice()
+
+	for _, target := range []string{"/api/adverts/7", "/api/v1/adverts/7"} {
+		rec := serve(e, http.MethodGet, target, "")
+		require.Equal(t, http.StatusOK, rec.Code, target)
+		assert.JSONEq(t, `{"id": 7, "name": "Bike", "main_photo_url": "http://img", "price": 120}`, rec.Body.String())
+		assert.True(t, strings.HasPrefix(rec.Header().Get("Deprecation"), "@"), target)
+		assert.Equal(t, `</api/v2/adverts>; rel="successor-version"`, rec.Header().Get("Link"))
+	}
+	svc.AssertExpectations(t)
+}
+
+func TestAdvertHandler_V2(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	e := newAdvertServer(svc)
+
+	fields := service.NewAdvertFields(service.FieldName, service.FieldMainPhoto, service.FieldPhotos)
+	svc.On("GetByID", mock.Anything, 7, fields, []string(nil)).Return(service.AdvertDetail{
+		AdvertSummary: service.AdvertSummary{ID: 7, Name: "Bike", MainPhotoURL: "http://img", MainPhotoThumbURL: "http://thumb"},
+		AllPhotosURLs: []string{"http://img", "http://img2"},
+	}, nil).Once()
+	svc.On("List", mock.Anything, model.AdvertFilter{}, 2, "price", "asc", service.SummaryFields, []string(nil)).Return([]service.AdvertDetail{
+		{AdvertSummary: service.AdvertSummary{ID: 1, Name: "Car", Price: 200}},
+	}, nil).Once()
+
+	rec := serve(e, http.MethodGet, "/api/v2/adverts/7?fields=name,main_photo,photos", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+	assert.JSONEq(t, `{"data": {
+		"id": 7, "name": "Bike",
+		"main_photo": {"url": "http://img", "thumb_url": "http://thumb"},
+		"photos": ["http://img", "http://img2"]
+	}}`, rec.Body.String())
+
+	rec = serve(e, http.MethodGet, "/api/v2/adverts?page=2&sort=price_asc", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"data": [{"id": 1, "name": "Car", "price": 200}],
+		"meta": {"page": 2, "per_page": 10, "count": 1}
+	}`, rec.Body.String())
+
+	svc.On("Create", mock.Anything, service.CreateAdvertInput{
+		Name: "Bike", Description: "Red", Photos: []string{"http://img"}, Price: 120,
+	}).Return(9, nil).Once()
+	rec = serve(e, http.MethodPost, "/api/v2/adverts", `{"name": "Bike", "description": "Red", "photos": ["http://img"], "price": 120}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/api/v2/adverts/9", rec.Header().Get(echo.HeaderLocation))
+	assert.JSONEq(t, `{"data": {"id": 9}}`, rec.Body.String())
+	svc.AssertExpectations(t)
+}
+
+func TestAdvertHandler_Problems(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	e := newAdvertServer(svc)
+
+	svc.On("GetByID", mock.Anything, 8, service.SummaryFields, []string(nil)).Return(service.AdvertDetail{}, error_message.ErrAdvertNotFound).Twice()
+
+	cases := []struct {
+		name, method, target, body string
+		err                        *error_message.Error
+	}{
+		{"NotFound", http.MethodGet, "/api/v2/adverts/8", "", error_message.ErrAdvertNotFound},
+		{"NotFoundV1", http.MethodGet, "/api/v1/adverts/8", "", error_message.ErrAdvertNotFound},
+		{"WrongID", http.MethodGet, "/api/v2/adverts/x", "", error_message.ErrWrongAdvertID},
+		{"WrongPage", http.MethodGet, "/api/v2/adverts?page=0", "", error_message.ErrWrongPageNumber},
+		{"WrongSort", http.MethodGet, "/api/v2/adverts?sort=name_asc", "", error_message.ErrWrongSortParams},
+		{"WrongFieldsV1", http.MethodGet, "/api/adverts?fields=owner", "", error_message.ErrWrongFieldsParam},
+		{"WrongFields", http.MethodGet, "/api/v2/adverts?fields=owner", "", error_message.ErrWrongFieldsParam},
+		{"BadBody", http.MethodPost, "/api/v2/adverts", `{"name": 1}`, error_message.ErrBadRequestBody},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serve(e, tc.method, tc.target, tc.body)
+			require.Equal(t, tc.err.Status, rec.Code)
+			assert.Equal(t, handler.ProblemContentType, rec.Header().Get(echo.HeaderContentType))
+
+			var problem handler.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			assert.Equal(t, "urn:advertising:problem:"+tc.err.Code, problem.Type)
+			assert.Equal(t, tc.err.Code, problem.Code)
+			assert.Equal(t, tc.err.Title, problem.Title)
+			assert.Equal(t, tc.err.Status, problem.Status)
+			assert.Equal(t, tc.err.Error(), problem.Detail)
+			assert.NotEmpty(t, problem.Instance)
+			assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), problem.Instance)
+		})
+	}
+	svc.AssertExpectations(t)
+}
+
+func TestAdvertHandler_ValidationProblems(t *testing.T) {
+	e := newAdvertServer(new(mocks.MockAdvertService))
+
+	cases := []struct {
+		name, method, target, body string
+		fields                     []handler.ProblemField
+	}{
+		{"TooManyPhotos", http.MethodPost, "/api/v2/adverts",
+			`{"name": "Bike", "description": "Red", "photos": ["1", "2", "3", "4"], "price": 1}`,
+			[]handler.ProblemField{{Field: "photos", Code: "wrong_photos", Detail: error_message.ErrWrongPhotos.Error()}}},
+		{"EveryInvalidField", http.MethodPost, "/api/adverts",
+			`{"name": "", "description": "Red", "photos": [], "price": 0}`,
+			[]handler.ProblemField{
+				{Field: "name", Code: "wrong_title", Detail: error_message.ErrWrongTitle.Error()},
+				{Field: "photos", Code: "wrong_photos", Detail: error_message.ErrWrongPhotos.Error()},
+				{Field: "price", Code: "not_positive_price", Detail: error_message.ErrNotPositivePrice.Error()},
+			}},
+		{"NotPositivePrice", http.MethodPut, "/api/v2/adverts/8", `{"price": -1}`,
+			[]handler.ProblemField{{Field: "price", Code: "not_positive_price", Detail: error_message.ErrNotPositivePrice.Error()}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := serve(e, tc.method, tc.target, tc.body)
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var problem handler.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			assert.Equal(t, "validation_failed", problem.Code)
+			assert.Equal(t, tc.fields, problem.Errors)
+		})
+	}
+}
+
+func TestAdvertHandler_Locales(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	e := newAdvertServer(svc)
+
+	detail := service.AdvertDetail{AdvertSummary: service.AdvertSummary{ID: 7, Name: "Велосипед", Locale: "ru"}}
+	svc.On("GetByID", mock.Anything, 7, service.SummaryFields, []string{"ru"}).Return(detail, nil).Once()
+	svc.On("GetByID", mock.Anything, 7, service.SummaryFields, []string{"ru-RU", "en"}).Return(detail, nil).Once()
+	svc.On("List", mock.Anything, model.AdvertFilter{}, 1, "", "", service.SummaryFields, []string{"en"}).
+		Return([]service.AdvertDetail{{AdvertSummary: service.AdvertSummary{ID: 7, Name: "Bike", Locale: "en"}}}, nil).Once()
+
+	rec := serve(e, http.MethodGet, "/api/v2/adverts/7?lang=RU", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ru", rec.Header().Get("Content-Language"))
+	assert.JSONEq(t, `{"data": {"id": 7, "name": "Велосипед", "locale": "ru", "price": 0}}`, rec.Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/adverts/7", nil)
+	req.Header.Set("Accept-Language", "ru-RU, en;q=0.5")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ru", rec.Header().Get("Content-Language"))
+	assert.Contains(t, rec.Header().Values(echo.HeaderVary), "Accept-Language")
+
+	rec = serve(e, http.MethodGet, "/api/v2/adverts?lang=en", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Language"))
+	assert.JSONEq(t, `{"data": [{"id": 7, "name": "Bike", "locale": "en", "price": 0}], "meta": {"page": 1, "per_page": 10, "count": 1}}`, rec.Body.String())
+
+	rec = serve(e, http.MethodGet, "/api/v2/adverts/7?lang=!!", "")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"wrong_lang"`)
+	svc.AssertExpectations(t)
+}
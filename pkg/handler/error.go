@@ -1,15 +1,127 @@
 package handler
 
 import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/text/language"
+)
+
+// ProblemContentType is the media type of RFC 7807 error responses.
+const ProblemContentType = "application/problem+json"
+
+// Headers of language negotiation.
+const (
+	acceptLanguageHeader  = "Accept-Language"
+	contentLanguageHeader = "Content-Language"
 )
 
-// ErrorResponse describes a JSON error response.
-type ErrorResponse struct {
-	Error string `json:"error"`
+// problemTypePrefix prefixes the error code in the problem type URI.
+const problemTypePrefix = "urn:advertising:problem:"
+
+// Problem describes an RFC 7807 error response. Code is the stable error code
+// (also the last part of Type), Instance the ID of the failed request and Errors
+// lists the invalid fields of a validation failure.
+type Problem struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Code     string         `json:"code"`
+	Errors   []ProblemField `json:"errors,omitempty"`
+}
+
+// ProblemField describes why a single request field is invalid.
+type ProblemField struct {
+	Field  string `json:"field"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// RegisterErrorHandler makes e report every error returned by a handler as an RFC 7807
+// problem and tags requests with an X-Request-ID that the problems refer to.
+func RegisterErrorHandler(e *echo.Echo) {
+	e.Pre(middleware.RequestID())
+	e.HTTPErrorHandler = HTTPErrorHandler
+}
+
+// HTTPErrorHandler writes err as an application/problem+json response.
+// error_message errors carry their own status and code, echo errors (unknown route,
+// wrong method) keep their status and any other error is an internal one. Titles and
+// details are in the language negotiated from Accept-Language.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	lang := error_message.NegotiateLanguage(c.Request().Header.Get(acceptLanguageHeader))
+	problem := newProblem(err, lang)
+	if problem.Status >= http.StatusInternalServerError {
+		c.Logger().Errorf("%s %s: %v", c.Request().Method, c.Request().URL.Path, err)
+	}
+	problem.Instance = c.Response().Header().Get(echo.HeaderXRequestID)
+
+	c.Response().Header().Set(echo.HeaderContentType, ProblemContentType)
+	c.Response().Header().Set(contentLanguageHeader, lang.String())
+	c.Response().Header().Add(echo.HeaderVary, acceptLanguageHeader)
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(problem.Status)
+	} else {
+		err = c.JSON(problem.Status, problem)
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+func newProblem(err error, lang language.Tag) Problem {
+	var (
+		validationErr *error_message.ValidationError
+		apiErr        *error_message.Error
+		httpErr       *echo.HTTPError
+	)
+	switch {
+	case errors.As(err, &validationErr):
+		problem := problemOf(error_message.ErrValidationFailed, lang)
+		for _, fieldErr := range validationErr.Errors {
+			problem.Errors = append(problem.Errors, problemFieldOf(fieldErr, lang))
+		}
+		return problem
+	case errors.As(err, &apiErr):
+		problem := problemOf(apiErr, lang)
+		if apiErr.Field != "" {
+			problem.Errors = []ProblemField{problemFieldOf(apiErr, lang)}
+		}
+		return problem
+	case errors.As(err, &httpErr):
+		title := http.StatusText(httpErr.Code)
+		detail, ok := httpErr.Message.(string)
+		if !ok {
+			detail = title
+		}
+		code := strings.ReplaceAll(strings.ToLower(title), " ", "_")
+		return problemOf(error_message.New(httpErr.Code, code, title, detail), lang)
+	default:
+		return problemOf(error_message.ErrInternal, lang)
+	}
+}
+
+func problemOf(err *error_message.Error, lang language.Tag) Problem {
+	msg := err.Message(lang)
+	return Problem{
+		Type:   problemTypePrefix + err.Code,
+		Title:  msg.Title,
+		Status: err.Status,
+		Detail: msg.Detail,
+		Code:   err.Code,
+	}
 }
 
-// SendError sends to the client a JSON { "error": "<msg>" } with the specified HTTP status code.
-func SendError(c echo.Context, code int, err error) error {
-	return c.JSON(code, ErrorResponse{Error: err.Error()})
+func problemFieldOf(err *error_message.Error, lang language.Tag) ProblemField {
+	return ProblemField{Field: err.Field, Code: err.Code, Detail: err.Message(lang).Detail}
 }
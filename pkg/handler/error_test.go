@@ -0,0 +1,72 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/handler"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	e := echo.New()
+	handler.RegisterErrorHandler(e)
+	e.GET("/boom", func(echo.Context) error { return errors.New("pq: connection refused") })
+
+	rec := serve(e, http.MethodGet, "/boom", "")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, "internal_error", problem.Code)
+	assert.NotContains(t, problem.Detail, "pq")
+
+	rec = serve(e, http.MethodGet, "/missing", "")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, handler.ProblemContentType, rec.Header().Get(echo.HeaderContentType))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, "not_found", problem.Code)
+
+	rec = serve(e, http.MethodHead, "/missing", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestHTTPErrorHandler_Language(t *testing.T) {
+	e := newAdvertServer(new(mocks.MockAdvertService))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/adverts", strings.NewReader(`{"name": "", "description": "Red", "photos": ["1"], "price": 0}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en;q=0.8")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "ru", rec.Header().Get("Content-Language"))
+	var problem handler.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, "validation_failed", problem.Code)
+	assert.Equal(t, "Ошибка валидации", problem.Title)
+	assert.Equal(t, []handler.ProblemField{
+		{Field: "name", Code: "wrong_title", Detail: "название должно содержать от 1 до 200 символов"},
+		{Field: "price", Code: "not_positive_price", Detail: "цена должна быть положительным числом"},
+	}, problem.Errors)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v2/adverts/x", nil)
+	req.Header.Set("Accept-Language", "de")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "en", rec.Header().Get("Content-Language"))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, "Wrong advert ID", problem.Title)
+	assert.Equal(t, "wrong advert id", problem.Detail)
+}
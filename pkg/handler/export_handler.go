@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// exportFlushEvery is how many rows are written before the response is flushed to the client.
+const exportFlushEvery = 100
+
+var exportCSVHeader = []string{"id", "name", "description", "price", "created_at", "photos"}
+
+// ExportAdverts godoc
+// @Summary     Export advertisements
+// @Description Stream the whole catalogue as CSV (photos separated by "|") or NDJSON.
+// @Description The response is gzip-compressed when the client sends Accept-Encoding: gzip.
+// @Tags        adverts
+// @Produce     text/csv,application/x-ndjson
+// @Param       format query    string false "csv (default) or ndjson"
+// @Param       sort      query    string  false "Sort by field, e.g. price_asc"
+// @Param       min_price query    number  false "Minimum price"
+// @Param       max_price query    number  false "Maximum price"
+// @Param       from      query    string  false "Created on or after (YYYY-MM-DD)"
+// @Param       to        query    string  false "Created on or before (YYYY-MM-DD)"
+// @Success     200    {string} string "Exported adverts"
+// @Failure     400    {object} handler.Problem
+// @Router      /adverts/export [get]
+func (h *AdvertHandler) ExportAdverts(c echo.Context) error {
+	format := strings.ToLower(c.QueryParam("format"))
+	if format == "" {
+		format = string(service.ImportFormatCSV)
+	}
+	if format != string(service.ImportFormatCSV) && format != string(service.ImportFormatNDJSON) {
+		return error_message.ErrWrongExportFormat
+	}
+
+	sortField, sortOrder, ok := parseSortParam(c.QueryParam("sort"))
+	if !ok {
+		return error_message.ErrWrongSortParams
+	}
+	if err := service.ValidateSort(sortField, sortOrder); err != nil {
+		return err
+	}
+	filter, err := parseAdvertFilter(c)
+	if err != nil {
+		return err
+	}
+
+	res := c.Response()
+	useGzip := acceptsGzip(c.Request().Header.Get(echo.HeaderAcceptEncoding))
+
+	var (
+		out     io.Writer = res
+		gz      *gzip.Writer
+		csvOut  *csv.Writer
+		jsonOut *json.Encoder
+		written int
+	)
+	// start commits the response lazily, so that errors raised before
+	// the first row (e.g. a failed query) can still be sent as a problem.
+	start := func() error {
+		res.Header().Set(echo.HeaderContentDisposition, "attachment; filename=adverts."+format)
+		res.Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+		if format == string(service.ImportFormatCSV) {
+			res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		} else {
+			res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+		}
+		if useGzip {
+			res.Header().Set(echo.HeaderContentEncoding, "gzip")
+		}
+		res.WriteHeader(http.StatusOK)
+
+		if useGzip {
+			gz = gzip.NewWriter(res)
+			out = gz
+		}
+		if format == string(service.ImportFormatCSV) {
+			csvOut = csv.NewWriter(out)
+			return csvOut.Write(exportCSVHeader)
+		}
+		jsonOut = json.NewEncoder(out)
+		return nil
+	}
+	flush := func() error {
+		if csvOut != nil {
+			csvOut.Flush()
+			if err := csvOut.Error(); err != nil {
+				return err
+			}
+		}
+		if gz != nil {
+			if err := gz.Flush(); err != nil {
+				return err
+			}
+		}
+		res.Flush()
+		return nil
+	}
+
+	err = h.advertSvc.Export(c.Request().Context(), filter, sortField, sortOrder, func(row service.AdvertExportRow) error {
+		if !res.Committed {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if csvOut != nil {
+			if err := csvOut.Write([]string{
+				strconv.Itoa(row.ID),
+				row.Name,
+				row.Description,
+				strconv.FormatFloat(row.Price, 'f', -1, 64),
+				row.CreatedAt.Format(time.RFC3339),
+				strings.Join(row.PhotoURLs, "|"),
+			}); err != nil {
+				return err
+			}
+		} else if err := jsonOut.Encode(row); err != nil {
+			return err
+		}
+
+		written++
+		if written%exportFlushEvery == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil && !res.Committed {
+		// The params are valid at this point, so the error handler reports a failure of storage as 500
+		return err
+	}
+
+	// An empty catalogue still gets a response (and a CSV header)
+	if !res.Committed {
+		if startErr := start(); startErr != nil {
+			return startErr
+		}
+	}
+	if flushErr := flush(); flushErr != nil && err == nil {
+		err = flushErr
+	}
+	if gz != nil {
+		if closeErr := gz.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	// The status is already sent, so a failure here can only be logged by Echo
+	return err
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, honouring
+// q-values: "gzip;q=0" refuses it and "*" allows it unless gzip is listed.
+func acceptsGzip(header string) bool {
+	wildcard := false
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "x-gzip" && coding != "*" {
+			continue
+		}
+		allowed := true
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(param, "=")
+			if strings.EqualFold(strings.TrimSpace(name), "q") {
+				q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				allowed = err == nil && q > 0
+			}
+		}
+		if coding == "*" {
+			wildcard = allowed
+			continue
+		}
+		return allowed
+	}
+	return wildcard
+}
@@ -0,0 +1,65 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdvertHandler_ExportErrors(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	e := newAdvertServer(svc)
+
+	// Rejected before the service is called
+	rec := serve(e, http.MethodGet, "/api/adverts/export?sort=name_asc", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// A failure of storage is not the client's fault
+	svc.On("Export", mock.Anything, model.AdvertFilter{}, "price", "asc", mock.Anything).
+		Return(errors.New("connection refused")).Once()
+	rec = serve(e, http.MethodGet, "/api/adverts/export?sort=price_asc", "")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestAdvertHandler_ExportGzip(t *testing.T) {
+	svc := new(mocks.MockAdvertService)
+	e := newAdvertServer(svc)
+	svc.On("Export", mock.Anything, model.AdvertFilter{}, "", "", mock.Anything).Return(nil)
+
+	cases := map[string]bool{
+		"":                       false,
+		"gzip":                   true,
+		"deflate, GZIP;q=0.5":    true,
+		"gzip;q=0":               false,
+		"gzip; q=0.000":          false,
+		"*":                      true,
+		"*;q=0":                  false,
+		"gzip;q=0, *":            false,
+		"br, identity":           false,
+		"x-gzip":                 true,
+		"deflate;q=1, gzip;q=0.": false,
+	}
+	for header, gzipped := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/api/adverts/export", nil)
+		req.Header.Set(echo.HeaderAcceptEncoding, header)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code, header)
+		if gzipped {
+			assert.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding), header)
+		} else {
+			assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding), header)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FavoriteHandler is responsible for the buyers' watchlist endpoints.
+type FavoriteHandler struct {
+	favoriteSvc service.FavoriteService
+}
+
+// AddFavorite godoc
+// @Summary     Add an advertisement to favorites
+// @Description Bookmark the advert for the current user; adding it twice is a no-op
+// @Tags        favorites
+// @Produce     json
+// @Param       id        path   int    true "Advert ID"
+// @Param       X-User-ID header string true "Current user"
+// @Success     204 {string} string "No content"
+// @Failure     400 {object} handler.Problem
+// @Failure     401 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Router      /adverts/{id}/favorite [post]
+func (h *FavoriteHandler) AddFavorite(c echo.Context) error {
+	advertID, err := advertIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.favoriteSvc.Add(c.Request().Context(), c.Request().Header.Get(UserIDHeader), advertID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RemoveFavorite godoc
+// @Summary     Remove an advertisement from favorites
+// @Description Delete the bookmark, also when the advert itself was deleted
+// @Tags        favorites
+// @Produce     json
+// @Param       id        path   int    true "Advert ID"
+// @Param       X-User-ID header string true "Current user"
+// @Success     204 {string} string "No content"
+// @Failure     400 {object} handler.Problem
+// @Failure     401 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Router      /adverts/{id}/favorite [delete]
+func (h *FavoriteHandler) RemoveFavorite(c echo.Context) error {
+	advertID, err := advertIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.favoriteSvc.Remove(c.Request().Context(), c.Request().Header.Get(UserIDHeader), advertID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListFavorites godoc
+// @Summary     List favorite advertisements
+// @Description Current user's favorites with pagination and sorting like GET /adverts;
+// @Description deleted adverts and price changes are reported in the status field
+// @Tags        favorites
+// @Produce     json
+// @Param       X-User-ID header string true  "Current user"
+// @Param       page      query  int    false "Page number"
+// @Param       sort      query  string false "Sort by field, e.g. price_asc or date_desc (date of favoriting)"
+// @Success     200 {array}  service.FavoriteItem
+// @Failure     400 {object} handler.Problem
+// @Failure     401 {object} handler.Problem
+// @Router      /me/favorites [get]
+func (h *FavoriteHandler) ListFavorites(c echo.Context) error {
+	page := parsePageParam(c.QueryParam("page"))
+	sortField, sortOrder, ok := parseSortParam(c.QueryParam("sort"))
+	if !ok {
+		return error_message.ErrWrongSortParams
+	}
+
+	items, err := h.favoriteSvc.List(c.Request().Context(), c.Request().Header.Get(UserIDHeader), page, sortField, sortOrder)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, items)
+}
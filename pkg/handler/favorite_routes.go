@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/labstack/echo/v4"
+)
+
+// NewFavoriteHandler registers favorites routes with Swagger annotations
+func NewFavoriteHandler(e *echo.Echo, svc service.FavoriteService) *FavoriteHandler {
+	h := &FavoriteHandler{favoriteSvc: svc}
+
+	e.POST("/api/adverts/:id/favorite", h.AddFavorite)
+	e.DELETE("/api/adverts/:id/favorite", h.RemoveFavorite)
+	e.GET("/api/me/favorites", h.ListFavorites)
+
+	return h
+}
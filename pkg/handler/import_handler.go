@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ImportHandler is responsible for HTTP endpoints under /api/adverts/import.
+type ImportHandler struct {
+	importSvc service.ImportService
+	maxSize   int64
+}
+
+// ImportAdverts godoc
+// @Summary     Bulk import advertisements
+// @Description Import adverts from a CSV (name,description,price,photos with "|"-separated URLs and an optional locale) or NDJSON body
+// @Description (the fields of POST /adverts, including locale and translations). Other columns or fields are rejected.
+// @Description The adverts are owned by the importing user.
+// @Description Small files are processed inline (200), large ones run as a background job (202) to be polled.
+// @Tags        adverts
+// @Accept      text/csv,application/x-ndjson
+// @Produce     json
+// @Param       X-User-ID header   string false "Owner of the imported adverts (anonymous if omitted)"
+// @Param       format    query    string false "File format: csv or ndjson (defaults to Content-Type)"
+// @Param       dry_run   query    bool   false "Only validate rows, do not create adverts"
+// @Success     200       {object} service.ImportJob
+// @Success     202       {object} service.ImportJob
+// @Failure     400       {object} handler.Problem
+// @Failure     413       {object} handler.Problem
+// @Failure     500       {object} handler.Problem
+// @Router      /adverts/import [post]
+func (h *ImportHandler) ImportAdverts(c echo.Context) error {
+	format := importFormat(c)
+
+	dryRun := false
+	if dryRunParam := c.QueryParam("dry_run"); dryRunParam != "" {
+		d, err := strconv.ParseBool(dryRunParam)
+		if err != nil {
+			return error_message.ErrBadRequestBody
+		}
+		dryRun = d
+	}
+
+	// The rows are parsed in memory, so the body must be bounded
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, h.maxSize)
+	job, err := h.importSvc.Import(c.Request().Context(), c.Request().Header.Get(UserIDHeader), format, body, dryRun)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return error_message.ErrImportTooLarge
+		}
+		return err
+	}
+
+	if job.Status != service.ImportJobDone {
+		c.Response().Header().Set(echo.HeaderLocation, "/api/adverts/import/"+job.ID)
+		return c.JSON(http.StatusAccepted, job)
+	}
+	return c.JSON(http.StatusOK, job)
+}
+
+// GetImportJob godoc
+// @Summary     Get import job status
+// @Description Poll the status and per-row report of a bulk import job.
+// @Description Jobs are kept in memory for an hour after finishing and do not survive a restart.
+// @Tags        adverts
+// @Produce     json
+// @Param       jobID path     string true "Import job ID"
+// @Success     200   {object} service.ImportJob
+// @Failure     404   {object} handler.Problem
+// @Router      /adverts/import/{jobID} [get]
+func (h *ImportHandler) GetImportJob(c echo.Context) error {
+	job, err := h.importSvc.GetJob(c.Request().Context(), c.Param("jobID"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, job)
+}
+
+// importFormat takes the format from the query string and falls back to Content-Type.
+func importFormat(c echo.Context) service.ImportFormat {
+	if format := c.QueryParam("format"); format != "" {
+		return service.ImportFormat(strings.ToLower(format))
+	}
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return service.ImportFormatCSV
+	case strings.HasPrefix(contentType, "application/x-ndjson"),
+		strings.HasPrefix(contentType, "application/ndjson"):
+		return service.ImportFormatNDJSON
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,30 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/handler"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportHandler_TooLarge(t *testing.T) {
+	e := echo.New()
+	handler.RegisterErrorHandler(e)
+	handler.NewImportHandler(e, service.NewImportService(new(mocks.MockAdvertService), 100, 1000), 16)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/adverts/import?dry_run=true",
+		strings.NewReader("name,description,price,photos\nBike,Red bike,150,\n"))
+	req.Header.Set(echo.HeaderContentType, "text/csv")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), "import_too_large")
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/labstack/echo/v4"
+)
+
+// NewImportHandler registers bulk import routes with Swagger annotations
+func NewImportHandler(e *echo.Echo, svc service.ImportService, maxSize int64) *ImportHandler {
+	if maxSize <= 0 {
+		maxSize = 10 << 20
+	}
+	h := &ImportHandler{importSvc: svc, maxSize: maxSize}
+
+	// Import group
+	g := e.Group("/api/adverts/import")
+
+	g.POST("", h.ImportAdverts)
+	g.GET("/:jobID", h.GetImportJob)
+
+	return h
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+)
+
+// preferredLocales returns the locales the client wants advert texts in, most preferred
+// first: the lang query param if set, the Accept-Language header otherwise.
+// A malformed header is ignored, so the adverts are served in their default locales.
+func preferredLocales(c echo.Context) ([]string, error) {
+	if lang := c.QueryParam("lang"); lang != "" {
+		locale, err := service.NormalizeLocale(lang)
+		if err != nil {
+			return nil, error_message.ErrWrongLang
+		}
+		return []string{locale}, nil
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(c.Request().Header.Get(acceptLanguageHeader))
+	if err != nil {
+		return nil, nil
+	}
+	var locales []string
+	for _, tag := range tags {
+		if tag != language.Und {
+			locales = append(locales, tag.String())
+		}
+	}
+	return locales, nil
+}
+
+// setContentLanguage reports the locale advert texts are served in; locale is empty
+// for lists, where every advert reports its own.
+func setContentLanguage(c echo.Context, locale string) {
+	header := c.Response().Header()
+	header.Add(echo.HeaderVary, acceptLanguageHeader)
+	if locale != "" {
+		header.Set(contentLanguageHeader, locale)
+	}
+}
+
+// advertTexts converts the translations of a create request for the service.
+func advertTexts(translations map[string]AdvertTextRequest) map[string]service.AdvertText {
+	if translations == nil {
+		return nil
+	}
+	texts := make(map[string]service.AdvertText, len(translations))
+	for locale, t := range translations {
+		texts[locale] = service.AdvertText{Name: t.Name, Description: t.Description}
+	}
+	return texts
+}
+
+// advertTextUpdates converts the translations of an update request for the service,
+// keeping nil texts that remove a locale.
+func advertTextUpdates(translations map[string]*AdvertTextRequest) map[string]*service.AdvertText {
+	if translations == nil {
+		return nil
+	}
+	texts := make(map[string]*service.AdvertText, len(translations))
+	for locale, t := range translations {
+		if t == nil {
+			texts[locale] = nil
+			continue
+		}
+		texts[locale] = &service.AdvertText{Name: t.Name, Description: t.Description}
+	}
+	return texts
+}
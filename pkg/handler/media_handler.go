@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/storage"
+
+	"github.com/labstack/echo/v4"
+)
+
+// multipartOverhead is the allowance for multipart boundaries and form fields on top of the file itself.
+const multipartOverhead = 64 << 10
+
+// MediaHandler is responsible for photo uploads and serving stored media under /media.
+type MediaHandler struct {
+	uploadSvc     service.UploadService
+	store         storage.BlobStore
+	maxUploadSize int64
+}
+
+// UploadPhoto godoc
+// @Summary     Upload a photo of an advertisement
+// @Description Upload a jpeg, png, gif or webp image; it is stored in the blob storage and added at the given position
+// @Tags        photos
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       id       path     int  true  "Advert ID"
+// @Param       file     formData file true  "Image file"
+// @Param       position formData int  false "Position of the new photo (0 or omitted = append)"
+// @Success     201 {object} model.Photo
+// @Failure     400 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Failure     413 {object} handler.Problem
+// @Failure     415 {object} handler.Problem
+// @Failure     500 {object} handler.Problem
+// @Router      /adverts/{id}/photos/upload [post]
+func (h *MediaHandler) UploadPhoto(c echo.Context) error {
+	advertID, err := advertIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	// Stop reading oversized bodies before they are spooled to disk
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, h.maxUploadSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return error_message.ErrPhotoTooLarge
+		}
+		return error_message.ErrMissingPhotoFile
+	}
+
+	position := 0
+	if positionParam := c.FormValue("position"); positionParam != "" {
+		p, err := strconv.Atoi(positionParam)
+		if err != nil {
+			return error_message.ErrWrongPhotoPosition
+		}
+		position = p
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	photo, err := h.uploadSvc.Upload(req.Context(), advertID, file, fileHeader.Size, position)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, photo)
+}
+
+// ServeMedia streams a stored object back to the client.
+func (h *MediaHandler) ServeMedia(c echo.Context) error {
+	body, contentType, err := h.store.Get(c.Request().Context(), c.Param("*"))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			return error_message.ErrMediaNotFound
+		}
+		return err
+	}
+	defer body.Close()
+
+	// Keys are random and never overwritten, so objects can be cached forever
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=31536000, immutable")
+	c.Response().Header().Set(echo.HeaderXContentTypeOptions, "nosniff")
+	return c.Stream(http.StatusOK, contentType, body)
+}
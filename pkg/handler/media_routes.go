@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/storage"
+	"github.com/labstack/echo/v4"
+)
+
+// NewMediaHandler registers photo upload and media routes with Swagger annotations
+func NewMediaHandler(e *echo.Echo, uploadSvc service.UploadService, store storage.BlobStore, maxUploadSize int64) *MediaHandler {
+	h := &MediaHandler{
+		uploadSvc:     uploadSvc,
+		store:         store,
+		maxUploadSize: maxUploadSize,
+	}
+
+	e.POST("/api/adverts/:id/photos/upload", h.UploadPhoto)
+	// Stored objects are served under /media/<key>
+	e.GET(service.MediaPathPrefix+"*", h.ServeMedia)
+
+	return h
+}
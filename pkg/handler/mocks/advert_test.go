@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/handler"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	servicemocks "github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service/mocks"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -14,59 +16,18 @@ import (
 	"testing"
 )
 
-// MockAdvertService implements the AdvertService interface with testify/mock
-type MockAdvertService struct {
-	mock.Mock
-}
-
-func (h *MockAdvertService) Create(
-	ctx context.Context,
-	input service.CreateAdvertInput,
-) (int, error) {
-	args := h.Called(ctx, input)
-	return args.Int(0), args.Error(1)
-}
-
-func (h *MockAdvertService) GetByID(
-	ctx context.Context,
-	id int,
-	fields bool,
-) (service.AdvertDetail, error) {
-	args := h.Called(ctx, id, fields)
-	return args.Get(0).(service.AdvertDetail), args.Error(1)
-}
+// nopViewService ignores views, counting is covered by the service tests
+type nopViewService struct{}
 
-func (h *MockAdvertService) List(
-	ctx context.Context,
-	page int,
-	sortField, sortOrder string,
-) ([]service.AdvertSummary, error) {
-	args := h.Called(ctx, page, sortField, sortOrder)
-	return args.Get(0).([]service.AdvertSummary), args.Error(1)
-}
-
-func (h *MockAdvertService) Update(
-	ctx context.Context,
-	id int,
-	input service.UpdateAdvertInput,
-) error {
-	args := h.Called(ctx, id, input)
-	return args.Error(0)
-}
-
-func (h *MockAdvertService) Delete(
-	ctx context.Context,
-	id int,
-) error {
-	args := h.Called(ctx, id)
-	return args.Error(0)
-}
+func (nopViewService) Record(int, string)              {}
+func (nopViewService) Flush(ctx context.Context) error { return nil }
+func (nopViewService) Run(ctx context.Context)         {}
 
 func TestCreate_Success(t *testing.T) {
 	// 1. Set up Echo and mock service
 	e := echo.New()
-	svc := new(MockAdvertService)
-	h := handler.NewAdvertHandler(e, svc)
+	svc := new(servicemocks.MockAdvertService)
+	h := handler.NewAdvertHandler(e, svc, nopViewService{})
 
 	// 2. Prepare input data and mock expectations
 	input := handler.CreateAdvertRequest{
@@ -75,7 +36,12 @@ func TestCreate_Success(t *testing.T) {
 		Photos:      []string{"http://a"},
 		Price:       100,
 	}
-	svc.On("Create", mock.Anything, input).Return(1, nil).Once()
+	svc.On("Create", mock.Anything, service.CreateAdvertInput{
+		Name:        input.Name,
+		Description: input.Description,
+		Photos:      input.Photos,
+		Price:       input.Price,
+	}).Return(1, nil).Once()
 
 	// 3. Form the HTTP request with JSON body
 	body, _ := json.Marshal(input)
@@ -102,8 +68,8 @@ func TestCreate_Success(t *testing.T) {
 
 func TestGetByID_Success(t *testing.T) {
 	e := echo.New()
-	svc := new(MockAdvertService)
-	h := handler.NewAdvertHandler(e, svc)
+	svc := new(servicemocks.MockAdvertService)
+	h := handler.NewAdvertHandler(e, svc, nopViewService{})
 
 	expected := service.AdvertDetail{
 		AdvertSummary: service.AdvertSummary{
@@ -115,9 +81,9 @@ func TestGetByID_Success(t *testing.T) {
 		Description:   "Some desc",
 		AllPhotosURLs: []string{"http://a", "http://b"},
 	}
-	svc.On("GetByID", mock.Anything, 42, true).Return(expected, nil).Once()
+	svc.On("GetByID", mock.Anything, 42, service.AllAdvertFields, []string(nil)).Return(expected, nil).Once()
 
-	req := httptest.NewRequest(http.MethodGet, "/api/adverts/42", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/adverts/42?fields=true", nil)
 	rec := httptest.NewRecorder()
 	ctx := e.NewContext(req, rec)
 	ctx.SetParamNames("id")
@@ -135,8 +101,8 @@ func TestGetByID_Success(t *testing.T) {
 
 func TestList_Success(t *testing.T) {
 	e := echo.New()
-	svc := new(MockAdvertService)
-	h := handler.NewAdvertHandler(e, svc)
+	svc := new(servicemocks.MockAdvertService)
+	h := handler.NewAdvertHandler(e, svc, nopViewService{})
 
 	expected := []service.AdvertSummary{
 		{
@@ -152,7 +118,10 @@ func TestList_Success(t *testing.T) {
 			Price:        200,
 		},
 	}
-	svc.On("List", mock.Anything, 1, "price", "asc").Return(expected, nil)
+	svc.On("List", mock.Anything, model.AdvertFilter{}, 1, "price", "asc", service.SummaryFields, []string(nil)).Return([]service.AdvertDetail{
+		{AdvertSummary: expected[0]},
+		{AdvertSummary: expected[1]},
+	}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/adverts?page=1&sort=price_asc", nil)
 	rec := httptest.NewRecorder()
@@ -172,15 +141,16 @@ func TestList_Success(t *testing.T) {
 
 func TestUpdate_Success(t *testing.T) {
 	e := echo.New()
-	svc := new(MockAdvertService)
-	h := handler.NewAdvertHandler(e, svc)
+	svc := new(servicemocks.MockAdvertService)
+	h := handler.NewAdvertHandler(e, svc, nopViewService{})
 
 	// 2. Prepare the request input data and for the mock
+	name, description, photos, price := "Updated Name", "Updated Desc", []string{"http://new-photo"}, 250.0
 	reqBody := handler.UpdateAdvertRequest{
-		Name:        "Updated Name",
-		Description: "Updated Desc",
-		Photos:      []string{"http://new-photo"},
-		Price:       250,
+		Name:        &name,
+		Description: &description,
+		Photos:      &photos,
+		Price:       &price,
 	}
 	// Assume that the handler converts UpdateAdvertRequest to service.UpdateAdvertInput
 	svcInput := service.UpdateAdvertInput{
@@ -220,8 +190,8 @@ func TestUpdate_Success(t *testing.T) {
 func TestDeleteAdvert_Success(t *testing.T) {
 	// 1. Set up Echo and mock service
 	e := echo.New()
-	svc := new(MockAdvertService)
-	h := handler.NewAdvertHandler(e, svc)
+	svc := new(servicemocks.MockAdvertService)
+	h := handler.NewAdvertHandler(e, svc, nopViewService{})
 
 	// 2. Set up the mock: for any context and id=7 return nil (successful deletion)
 	svc.
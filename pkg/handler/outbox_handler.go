@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// OutboxHandler is responsible for HTTP endpoints under /api/admin/outbox.
+type OutboxHandler struct {
+	relay service.OutboxRelay
+}
+
+// GetOutboxMetrics godoc
+// @Summary     Outbox relay metrics
+// @Description Number and age of unpublished advert events, publish counters since the start of the process
+// @Tags        admin
+// @Produce     json
+// @Param       X-Admin-Token header string true "Admin token"
+// @Success     200 {object} service.OutboxMetrics
+// @Failure     401 {object} handler.Problem
+// @Failure     500 {object} handler.Problem
+// @Router      /admin/outbox [get]
+func (h *OutboxHandler) GetOutboxMetrics(c echo.Context) error {
+	metrics, err := h.relay.Metrics(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, metrics)
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/labstack/echo/v4"
+)
+
+// NewOutboxHandler registers outbox routes with Swagger annotations.
+// Like other admin routes they require the X-Admin-Token header to match token.
+func NewOutboxHandler(e *echo.Echo, relay service.OutboxRelay, token string) *OutboxHandler {
+	h := &OutboxHandler{relay: relay}
+
+	// Outbox group
+	g := e.Group("/api/admin/outbox", requireAdminToken(token))
+
+	g.GET("", h.GetOutboxMetrics)
+
+	return h
+}
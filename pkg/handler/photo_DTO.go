@@ -0,0 +1,13 @@
+package handler
+
+// AddPhotoRequest — payload для POST /api/adverts/:id/photos
+type AddPhotoRequest struct {
+	URL string `json:"url" validate:"required"`
+	// Position — позиция новой фотографии (1 = главная); 0 — добавить в конец
+	Position int `json:"position"`
+}
+
+// ReorderPhotosRequest — payload для PUT /api/adverts/:id/photos/order
+type ReorderPhotosRequest struct {
+	PhotoIDs []int `json:"photo_ids" validate:"required"`
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PhotoHandler is responsible for HTTP endpoints under /api/adverts/:id/photos.
+type PhotoHandler struct {
+	photoSvc service.PhotoService
+}
+
+// ListPhotos godoc
+// @Summary     List photos of an advertisement
+// @Description Get all photos of the advert ordered by position (1 = main photo),
+// @Description including the URL verification status; status=broken lists only failed photos
+// @Tags        photos
+// @Produce     json
+// @Param       id     path     int    true  "Advert ID"
+// @Param       status query    string false "Filter by status: unchecked, ok or broken"
+// @Success     200 {array}  model.Photo
+// @Failure     400 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Router      /adverts/{id}/photos [get]
+func (h *PhotoHandler) ListPhotos(c echo.Context) error {
+	advertID, err := advertIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	photos, err := h.photoSvc.List(c.Request().Context(), advertID)
+	if err != nil {
+		return err
+	}
+
+	if status := c.QueryParam("status"); status != "" {
+		filtered := make([]model.Photo, 0, len(photos))
+		for _, p := range photos {
+			if p.Status == status {
+				filtered = append(filtered, p)
+			}
+		}
+		photos = filtered
+	}
+	return c.JSON(http.StatusOK, photos)
+}
+
+// AddPhoto godoc
+// @Summary     Add a photo to an advertisement
+// @Description Insert a photo at the given position (0 or omitted = append), shifting the following photos
+// @Tags        photos
+// @Accept      json
+// @Produce     json
+// @Param       id    path     int                     true "Advert ID"
+// @Param       photo body     handler.AddPhotoRequest true "Photo payload"
+// @Success     201   {object} model.Photo
+// @Failure     400   {object} handler.Problem
+// @Failure     404   {object} handler.Problem
+// @Failure     500   {object} handler.Problem
+// @Router      /adverts/{id}/photos [post]
+func (h *PhotoHandler) AddPhoto(c echo.Context) error {
+	advertID, err := advertIDParam(c)
+	if err != nil {
+		return err
+	}
+	var req AddPhotoRequest
+	if err := c.Bind(&req); err != nil {
+		return error_message.ErrBadRequestBody
+	}
+
+	photo, err := h.photoSvc.Add(c.Request().Context(), advertID, req.URL, req.Position)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, photo)
+}
+
+// DeletePhoto godoc
+// @Summary     Delete a photo of an advertisement
+// @Description Delete a single photo by ID; positions of the remaining photos are compacted
+// @Tags        photos
+// @Produce     json
+// @Param       id      path int true "Advert ID"
+// @Param       photoID path int true "Photo ID"
+// @Success     204 {string} string "No content"
+// @Failure     400 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Router      /adverts/{id}/photos/{photoID} [delete]
+func (h *PhotoHandler) DeletePhoto(c echo.Context) error {
+	advertID, err := advertIDParam(c)
+	if err != nil {
+		return err
+	}
+	photoID, err := photoIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.photoSvc.Remove(c.Request().Context(), advertID, photoID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ReorderPhotos godoc
+// @Summary     Reorder photos of an advertisement
+// @Description Set a new order of photos; the first ID becomes the main photo
+// @Tags        photos
+// @Accept      json
+// @Produce     json
+// @Param       id    path int                          true "Advert ID"
+// @Param       order body handler.ReorderPhotosRequest true "New order of photo IDs"
+// @Success     204 {string} string "No content"
+// @Failure     400 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Router      /adverts/{id}/photos/order [put]
+func (h *PhotoHandler) ReorderPhotos(c echo.Context) error {
+	advertID, err := advertIDParam(c)
+	if err != nil {
+		return err
+	}
+	var req ReorderPhotosRequest
+	if err := c.Bind(&req); err != nil {
+		return error_message.ErrBadRequestBody
+	}
+
+	if err := h.photoSvc.Reorder(c.Request().Context(), advertID, req.PhotoIDs); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SetMainPhoto godoc
+// @Summary     Set the main photo of an advertisement
+// @Description Move the photo to position 1 keeping the order of the others
+// @Tags        photos
+// @Produce     json
+// @Param       id      path int true "Advert ID"
+// @Param       photoID path int true "Photo ID"
+// @Success     204 {string} string "No content"
+// @Failure     400 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Router      /adverts/{id}/photos/{photoID}/main [put]
+func (h *PhotoHandler) SetMainPhoto(c echo.Context) error {
+	advertID, err := advertIDParam(c)
+	if err != nil {
+		return err
+	}
+	photoID, err := photoIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.photoSvc.SetMain(c.Request().Context(), advertID, photoID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func advertIDParam(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		return 0, error_message.ErrWrongAdvertID
+	}
+	return id, nil
+}
+
+func photoIDParam(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("photoID"))
+	if err != nil || id < 1 {
+		return 0, error_message.ErrWrongPhotoID
+	}
+	return id, nil
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/labstack/echo/v4"
+)
+
+// NewPhotoHandler registers photo sub-resource routes with Swagger annotations
+func NewPhotoHandler(e *echo.Echo, svc service.PhotoService) *PhotoHandler {
+	h := &PhotoHandler{photoSvc: svc}
+
+	// Photo group
+	g := e.Group("/api/adverts/:id/photos")
+
+	g.GET("", h.ListPhotos)
+	g.POST("", h.AddPhoto)
+	g.PUT("/order", h.ReorderPhotos)
+	g.DELETE("/:photoID", h.DeletePhoto)
+	g.PUT("/:photoID/main", h.SetMainPhoto)
+
+	return h
+}
@@ -0,0 +1,15 @@
+package handler
+
+// SaveSearchRequest — payload для POST /api/me/saved-searches
+type SaveSearchRequest struct {
+	Name string `json:"name" validate:"required"`
+	// Email — адрес для уведомлений о новых объявлениях (необязательный)
+	Email    string   `json:"email"`
+	MinPrice *float64 `json:"min_price"`
+	MaxPrice *float64 `json:"max_price"`
+	// From, To — диапазон дат создания в формате YYYY-MM-DD (включительно)
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Sort — сортировка в формате списка, например price_asc
+	Sort string `json:"sort"`
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SavedSearchHandler is responsible for HTTP endpoints under /api/me/saved-searches.
+type SavedSearchHandler struct {
+	searchSvc service.SavedSearchService
+}
+
+// CreateSavedSearch godoc
+// @Summary     Save a list query
+// @Description Store filters and sort of the advert list; the user is notified about new matching adverts
+// @Tags        saved-searches
+// @Accept      json
+// @Produce     json
+// @Param       X-User-ID header string                    true "Current user"
+// @Param       search    body   handler.SaveSearchRequest true "Search payload"
+// @Success     201 {object} model.SavedSearch
+// @Failure     400 {object} handler.Problem
+// @Failure     401 {object} handler.Problem
+// @Failure     500 {object} handler.Problem
+// @Router      /me/saved-searches [post]
+func (h *SavedSearchHandler) CreateSavedSearch(c echo.Context) error {
+	var req SaveSearchRequest
+	if err := c.Bind(&req); err != nil {
+		return error_message.ErrBadRequestBody
+	}
+
+	values := map[string]string{"from": req.From, "to": req.To}
+	if req.MinPrice != nil {
+		values["min_price"] = strconv.FormatFloat(*req.MinPrice, 'f', -1, 64)
+	}
+	if req.MaxPrice != nil {
+		values["max_price"] = strconv.FormatFloat(*req.MaxPrice, 'f', -1, 64)
+	}
+	filter, err := parseFilterValues(func(name string) string { return values[name] })
+	if err != nil {
+		return err
+	}
+
+	search, err := h.searchSvc.Create(c.Request().Context(), c.Request().Header.Get(UserIDHeader), service.SavedSearchInput{
+		Name:   req.Name,
+		Email:  req.Email,
+		Filter: filter,
+		Sort:   req.Sort,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, search)
+}
+
+// ListSavedSearches godoc
+// @Summary     List saved searches
+// @Description All saved searches of the current user, newest first
+// @Tags        saved-searches
+// @Produce     json
+// @Param       X-User-ID header string true "Current user"
+// @Success     200 {array}  model.SavedSearch
+// @Failure     401 {object} handler.Problem
+// @Failure     500 {object} handler.Problem
+// @Router      /me/saved-searches [get]
+func (h *SavedSearchHandler) ListSavedSearches(c echo.Context) error {
+	searches, err := h.searchSvc.List(c.Request().Context(), c.Request().Header.Get(UserIDHeader))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, searches)
+}
+
+// DeleteSavedSearch godoc
+// @Summary     Delete a saved search
+// @Tags        saved-searches
+// @Produce     json
+// @Param       X-User-ID header string true "Current user"
+// @Param       searchID  path   int    true "Saved search ID"
+// @Success     204 {string} string "No content"
+// @Failure     400 {object} handler.Problem
+// @Failure     401 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Router      /me/saved-searches/{searchID} [delete]
+func (h *SavedSearchHandler) DeleteSavedSearch(c echo.Context) error {
+	id, err := searchIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.searchSvc.Delete(c.Request().Context(), c.Request().Header.Get(UserIDHeader), id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetSavedSearchResults godoc
+// @Summary     Run a saved search
+// @Description A page of adverts matching the saved filters and sort, like GET /adverts
+// @Tags        saved-searches
+// @Produce     json
+// @Param       X-User-ID header string true  "Current user"
+// @Param       searchID  path   int    true  "Saved search ID"
+// @Param       page      query  int    false "Page number"
+// @Success     200 {array}  service.AdvertSummary
+// @Failure     400 {object} handler.Problem
+// @Failure     401 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Router      /me/saved-searches/{searchID}/adverts [get]
+func (h *SavedSearchHandler) GetSavedSearchResults(c echo.Context) error {
+	id, err := searchIDParam(c)
+	if err != nil {
+		return err
+	}
+	page := parsePageParam(c.QueryParam("page"))
+
+	adverts, err := h.searchSvc.Results(c.Request().Context(), c.Request().Header.Get(UserIDHeader), id, page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, adverts)
+}
+
+func searchIDParam(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("searchID"))
+	if err != nil || id < 1 {
+		return 0, error_message.ErrWrongSearchID
+	}
+	return id, nil
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/labstack/echo/v4"
+)
+
+// NewSavedSearchHandler registers saved search routes with Swagger annotations
+func NewSavedSearchHandler(e *echo.Echo, svc service.SavedSearchService) *SavedSearchHandler {
+	h := &SavedSearchHandler{searchSvc: svc}
+
+	// Saved search group
+	g := e.Group("/api/me/saved-searches")
+
+	g.POST("", h.CreateSavedSearch)
+	g.GET("", h.ListSavedSearches)
+	g.DELETE("/:searchID", h.DeleteSavedSearch)
+	g.GET("/:searchID/adverts", h.GetSavedSearchResults)
+
+	return h
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// StatsHandler is responsible for HTTP endpoints under /api/stats.
+type StatsHandler struct {
+	statsSvc service.StatsService
+}
+
+// GetAdvertStats godoc
+// @Summary     Catalogue statistics
+// @Description Total count, min/max/avg/median price, a price histogram and adverts created per day.
+// @Description Accepts the same filters as GET /adverts; per-day counts cover from..to (last 30 days by default).
+// @Tags        stats
+// @Produce     json
+// @Param       min_price query number false "Minimum price"
+// @Param       max_price query number false "Maximum price"
+// @Param       from      query string false "Created on or after the date (YYYY-MM-DD)"
+// @Param       to        query string false "Created on or before the date (YYYY-MM-DD)"
+// @Param       buckets   query string false "Ascending histogram bounds, e.g. 0,100,1000"
+// @Success     200 {object} service.AdvertStats
+// @Failure     400 {object} handler.Problem
+// @Failure     500 {object} handler.Problem
+// @Router      /stats/adverts [get]
+func (h *StatsHandler) GetAdvertStats(c echo.Context) error {
+	filter, err := parseAdvertFilter(c)
+	if err != nil {
+		return err
+	}
+	buckets, err := parseBucketsParam(c.QueryParam("buckets"))
+	if err != nil {
+		return err
+	}
+
+	stats, err := h.statsSvc.AdvertStats(c.Request().Context(), filter, buckets)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, stats)
+}
+
+// parseBucketsParam parses "0,100,1000"; an empty param yields nil (default buckets).
+func parseBucketsParam(raw string) ([]float64, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	parts := strings.Split(raw, ",")
+	buckets := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		b, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, error_message.ErrWrongBuckets
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, nil
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/labstack/echo/v4"
+)
+
+// NewStatsHandler registers statistics routes with Swagger annotations
+func NewStatsHandler(e *echo.Echo, svc service.StatsService) *StatsHandler {
+	h := &StatsHandler{statsSvc: svc}
+
+	// Stats group
+	g := e.Group("/api/stats")
+
+	g.GET("/adverts", h.GetAdvertStats)
+
+	return h
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// streamRetryMillis is the reconnection delay suggested to EventSource clients
+const streamRetryMillis = 3000
+
+// StreamHandler is responsible for the advert change stream.
+type StreamHandler struct {
+	stream    service.AdvertStream
+	keepAlive time.Duration
+}
+
+// StreamAdverts godoc
+// @Summary     Stream advert changes
+// @Description Server-Sent Events with advert.created, advert.updated (data is the advert summary) and advert.deleted (data is {"id": ...}).
+// @Description Send Last-Event-ID (or last_event_id) to resume; an "advert.reset" event means some changes were missed and the list should be reloaded.
+// @Description Adverts have no category, so only the price range filters the stream.
+// @Tags        adverts
+// @Produce     text/event-stream
+// @Param       Last-Event-ID header string false "ID of the last received event"
+// @Param       last_event_id query  string false "Same as Last-Event-ID, for clients that cannot set headers"
+// @Param       min_price     query  number false "Minimum price"
+// @Param       max_price     query  number false "Maximum price"
+// @Success     200 {string} string "Event stream"
+// @Failure     400 {object} handler.Problem
+// @Router      /adverts/stream [get]
+func (h *StreamHandler) StreamAdverts(c echo.Context) error {
+	// Only the price range applies to single changes
+	filter, err := parseFilterValues(func(name string) string {
+		if name == "min_price" || name == "max_price" {
+			return c.QueryParam(name)
+		}
+		return ""
+	})
+	if err != nil {
+		return err
+	}
+	lastID := c.Request().Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.QueryParam("last_event_id")
+	}
+	sub, err := h.stream.Subscribe(lastID, filter)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(res, "retry: %d\n\n", streamRetryMillis); err != nil {
+		return nil
+	}
+	if sub.Gap {
+		if _, err := fmt.Fprint(res, "event: advert.reset\ndata: {}\n\n"); err != nil {
+			return nil
+		}
+	}
+	for _, change := range sub.Replay {
+		if err := writeChange(res, change); err != nil {
+			return nil
+		}
+	}
+	res.Flush()
+
+	keepAlive := time.NewTicker(h.keepAlive)
+	defer keepAlive.Stop()
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change, ok := <-sub.C:
+			if !ok {
+				// Dropped as too slow, the client reconnects with Last-Event-ID
+				return nil
+			}
+			if err := writeChange(res, change); err != nil {
+				return nil
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
+
+func writeChange(res *echo.Response, change service.AdvertChange) error {
+	_, err := fmt.Fprintf(res, "id: %s\nevent: %s\ndata: %s\n\n", change.EventID, change.Event, change.Data)
+	return err
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/labstack/echo/v4"
+)
+
+// NewStreamHandler registers the advert change stream with Swagger annotations;
+// idle connections receive a comment every keepAlive
+func NewStreamHandler(e *echo.Echo, stream service.AdvertStream, keepAlive time.Duration) *StreamHandler {
+	if keepAlive <= 0 {
+		keepAlive = 15 * time.Second
+	}
+	h := &StreamHandler{stream: stream, keepAlive: keepAlive}
+
+	e.GET("/api/adverts/stream", h.StreamAdverts)
+
+	return h
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// API version prefixes. v1 is also served under the unversioned /api prefix.
+const (
+	APIv1Prefix = "/api/v1"
+	APIv2Prefix = "/api/v2"
+)
+
+// v1DeprecatedAt is when v2 of the advert endpoints superseded v1.
+var v1DeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// ServeV1Aliases makes every route registered under /api available under /api/v1 as well,
+// so that clients can switch to versioned URLs without waiting for v2 of every endpoint.
+func ServeV1Aliases(e *echo.Echo) {
+	e.Pre(middleware.Rewrite(map[string]string{APIv1Prefix + "/*": "/api/$1"}))
+}
+
+// deprecatedBy marks responses as deprecated (RFC 9745) and links the endpoint replacing them.
+func deprecatedBy(successor string) echo.MiddlewareFunc {
+	deprecation := fmt.Sprintf("@%d", v1DeprecatedAt.Unix())
+	link := fmt.Sprintf(`<%s>; rel="successor-version"`, successor)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			header.Set("Deprecation", deprecation)
+			header.Set("Link", link)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package handler
+
+// WebhookRequest — payload для POST /api/admin/webhooks
+type WebhookRequest struct {
+	// URL — адрес http(s), на который отправляются события
+	URL string `json:"url" validate:"required"`
+	// Events — список событий: advert.created, advert.updated, advert.deleted
+	Events []string `json:"events" validate:"required"`
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/labstack/echo/v4"
+)
+
+// WebhookHandler is responsible for HTTP endpoints under /api/admin/webhooks.
+type WebhookHandler struct {
+	webhookSvc service.WebhookService
+}
+
+// CreateWebhook godoc
+// @Summary     Subscribe to advert events
+// @Description Register an endpoint for advert events. The response contains the signing secret, it is not shown again.
+// @Description Requests carry X-Webhook-Signature "t=<unix>,v1=<hex HMAC-SHA256 of "<t>.<body>">".
+// @Tags        webhooks
+// @Accept      json
+// @Produce     json
+// @Param       X-Admin-Token header string                 true "Admin token"
+// @Param       webhook       body   handler.WebhookRequest true "Subscription payload"
+// @Success     201 {object} model.WebhookSubscription
+// @Failure     400 {object} handler.Problem
+// @Failure     401 {object} handler.Problem
+// @Failure     500 {object} handler.Problem
+// @Router      /admin/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	var req WebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return error_message.ErrBadRequestBody
+	}
+
+	sub, err := h.webhookSvc.Subscribe(c.Request().Context(), req.URL, req.Events)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, sub)
+}
+
+// ListWebhooks godoc
+// @Summary     List webhook subscriptions
+// @Tags        webhooks
+// @Produce     json
+// @Param       X-Admin-Token header string true "Admin token"
+// @Success     200 {array}  model.WebhookSubscription
+// @Failure     401 {object} handler.Problem
+// @Failure     500 {object} handler.Problem
+// @Router      /admin/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c echo.Context) error {
+	subs, err := h.webhookSvc.List(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, subs)
+}
+
+// DeleteWebhook godoc
+// @Summary     Delete a webhook subscription
+// @Description The subscription and its delivery log are removed
+// @Tags        webhooks
+// @Produce     json
+// @Param       X-Admin-Token header string true "Admin token"
+// @Param       webhookID     path   int    true "Subscription ID"
+// @Success     204 {string} string "No content"
+// @Failure     400 {object} handler.Problem
+// @Failure     401 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Router      /admin/webhooks/{webhookID} [delete]
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	id, err := webhookIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.webhookSvc.Unsubscribe(c.Request().Context(), id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListDeliveries godoc
+// @Summary     Webhook delivery log
+// @Description The latest 100 deliveries of a subscription, newest first
+// @Tags        webhooks
+// @Produce     json
+// @Param       X-Admin-Token header string true  "Admin token"
+// @Param       webhookID     path   int    true  "Subscription ID"
+// @Param       status        query  string false "pending, delivered or dead"
+// @Success     200 {array}  model.WebhookDelivery
+// @Failure     400 {object} handler.Problem
+// @Failure     401 {object} handler.Problem
+// @Failure     500 {object} handler.Problem
+// @Router      /admin/webhooks/{webhookID}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	id, err := webhookIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := h.webhookSvc.Deliveries(c.Request().Context(), id, c.QueryParam("status"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+// RetryDelivery godoc
+// @Summary     Retry a webhook delivery
+// @Description Schedule a pending or dead delivery for an immediate attempt with a fresh retry budget
+// @Tags        webhooks
+// @Produce     json
+// @Param       X-Admin-Token header string true "Admin token"
+// @Param       webhookID     path   int    true "Subscription ID"
+// @Param       deliveryID    path   int    true "Delivery ID"
+// @Success     202 {string} string "Accepted"
+// @Failure     400 {object} handler.Problem
+// @Failure     401 {object} handler.Problem
+// @Failure     404 {object} handler.Problem
+// @Router      /admin/webhooks/{webhookID}/deliveries/{deliveryID}/retry [post]
+func (h *WebhookHandler) RetryDelivery(c echo.Context) error {
+	id, err := webhookIDParam(c)
+	if err != nil {
+		return err
+	}
+	deliveryID, err := strconv.ParseInt(c.Param("deliveryID"), 10, 64)
+	if err != nil || deliveryID < 1 {
+		return error_message.ErrWrongDeliveryID
+	}
+
+	if err := h.webhookSvc.Retry(c.Request().Context(), id, deliveryID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusAccepted)
+}
+
+func webhookIDParam(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("webhookID"))
+	if err != nil || id < 1 {
+		return 0, error_message.ErrWrongWebhookID
+	}
+	return id, nil
+}
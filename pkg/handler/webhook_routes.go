@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/labstack/echo/v4"
+)
+
+// NewWebhookHandler registers webhook management routes with Swagger annotations.
+// Like other admin routes they require the X-Admin-Token header to match token.
+func NewWebhookHandler(e *echo.Echo, svc service.WebhookService, token string) *WebhookHandler {
+	h := &WebhookHandler{webhookSvc: svc}
+
+	// Webhook group
+	g := e.Group("/api/admin/webhooks", requireAdminToken(token))
+
+	g.POST("", h.CreateWebhook)
+	g.GET("", h.ListWebhooks)
+	g.DELETE("/:webhookID", h.DeleteWebhook)
+	g.GET("/:webhookID/deliveries", h.ListDeliveries)
+	g.POST("/:webhookID/deliveries/:deliveryID/retry", h.RetryDelivery)
+
+	return h
+}
@@ -0,0 +1,57 @@
+package imaging
+
+import (
+	"image"
+	"math/bits"
+)
+
+// AHash computes a 64-bit average hash: the image is reduced to 8x8 grey pixels
+// and every bit tells whether a pixel is brighter than the mean.
+func AHash(img image.Image) uint64 {
+	small := resizeArea(toRGBA(img), 8, 8)
+
+	var sum uint32
+	var grey [64]uint32
+	for i := range grey {
+		grey[i] = luma(small, i%8, i/8)
+		sum += grey[i]
+	}
+	mean := sum / 64
+
+	var hash uint64
+	for i, g := range grey {
+		if g > mean {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// DHash computes a 64-bit difference hash: the image is reduced to 9x8 grey pixels
+// and every bit tells whether a pixel is brighter than its right neighbour.
+// It is robust to rescaling, recompression and small colour changes.
+func DHash(img image.Image) uint64 {
+	small := resizeArea(toRGBA(img), 9, 8)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if luma(small, x, y) > luma(small, x+1, y) {
+				hash |= 1 << uint(y*8+x)
+			}
+		}
+	}
+	return hash
+}
+
+// Distance is the number of differing bits between two hashes;
+// 0 means the images are visually identical.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// luma returns the ITU-R BT.601 brightness of a pixel scaled by 1000.
+func luma(img *image.RGBA, x, y int) uint32 {
+	off := y*img.Stride + x*4
+	return 299*uint32(img.Pix[off]) + 587*uint32(img.Pix[off+1]) + 114*uint32(img.Pix[off+2])
+}
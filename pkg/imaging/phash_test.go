@@ -0,0 +1,39 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gradient draws a horizontal gradient, optionally mirrored
+func gradient(w, h int, mirrored bool) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(x * 255 / (w - 1))
+			if mirrored {
+				v = 255 - v
+			}
+			img.Set(x, y, color.RGBA{R: v, G: v / 2, B: 255 - v, A: 255})
+		}
+	}
+	return img
+}
+
+func TestDHash_SurvivesRescaling(t *testing.T) {
+	original := gradient(640, 480, false)
+	rescaled := Fit(original, 160, 160)
+
+	assert.Equal(t, 0, Distance(DHash(original), DHash(rescaled)))
+	assert.Equal(t, 0, Distance(AHash(original), AHash(rescaled)))
+}
+
+func TestDHash_DistinguishesImages(t *testing.T) {
+	a := DHash(gradient(640, 480, false))
+	b := DHash(gradient(640, 480, true))
+
+	assert.Greater(t, Distance(a, b), 32)
+}
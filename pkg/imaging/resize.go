@@ -0,0 +1,79 @@
+// Package imaging contains small image helpers built on the standard library only.
+package imaging
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Fit scales src down to fit into maxW x maxH keeping the aspect ratio.
+// Images that already fit are returned unchanged; images are never upscaled.
+func Fit(src image.Image, maxW, maxH int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxW && h <= maxH || w == 0 || h == 0 {
+		return src
+	}
+
+	dw, dh := maxW, h*maxW/w
+	if dh > maxH {
+		dw, dh = w*maxH/h, maxH
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+	return resizeArea(toRGBA(src), dw, dh)
+}
+
+// resizeArea downscales src to dw x dh averaging every source pixel
+// covered by a destination pixel (box filter), which avoids aliasing.
+func resizeArea(src *image.RGBA, dw, dh int) *image.RGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		sy0, sy1 := y*sh/dh, (y+1)*sh/dh
+		if sy1 == sy0 {
+			sy1 = sy0 + 1
+		}
+		for x := 0; x < dw; x++ {
+			sx0, sx1 := x*sw/dw, (x+1)*sw/dw
+			if sx1 == sx0 {
+				sx1 = sx0 + 1
+			}
+
+			var r, g, bl, a, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				row := src.Pix[sy*src.Stride+sx0*4 : sy*src.Stride+sx1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += uint32(row[i])
+					g += uint32(row[i+1])
+					bl += uint32(row[i+2])
+					a += uint32(row[i+3])
+					n++
+				}
+			}
+
+			off := y*dst.Stride + x*4
+			dst.Pix[off] = uint8(r / n)
+			dst.Pix[off+1] = uint8(g / n)
+			dst.Pix[off+2] = uint8(bl / n)
+			dst.Pix[off+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// toRGBA converts src to *image.RGBA with bounds starting at (0, 0).
+func toRGBA(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	if rgba, ok := src.(*image.RGBA); ok && b.Min == (image.Point{}) {
+		return rgba
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	return dst
+}
@@ -0,0 +1,42 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFit_KeepsAspectRatio(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+
+	dst := Fit(src, 100, 100)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), dst.Bounds())
+
+	dst = Fit(image.NewRGBA(image.Rect(0, 0, 200, 400)), 100, 100)
+	assert.Equal(t, image.Rect(0, 0, 50, 100), dst.Bounds())
+}
+
+func TestFit_NeverUpscales(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 50, 40))
+	assert.Same(t, src, Fit(src, 100, 100))
+}
+
+func TestFit_AveragesPixels(t *testing.T) {
+	// Alternating black and white columns become uniform grey
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			if x%2 == 0 {
+				src.Set(x, y, color.White)
+			} else {
+				src.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	dst := Fit(src, 2, 1).(*image.RGBA)
+	assert.Equal(t, color.RGBA{R: 127, G: 127, B: 127, A: 255}, dst.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{R: 127, G: 127, B: 127, A: 255}, dst.RGBAAt(1, 0))
+}
@@ -2,10 +2,24 @@ package model
 
 import "time"
 
+// Advert is a single classified ad.
+// OwnerID identifies the seller (empty for anonymous adverts),
+// FlagReason is set when an automatic rule marks the advert as suspicious.
+// ViewCount lags behind by up to one flush of the view counter.
+// Name and Description are in DefaultLocale; Translations holds the texts of every
+// locale (including the default one) and PhotoURLs the photos in position order when
+// the advert is written, reads leave both nil.
 type Advert struct {
 	ID          int       `db:"id" json:"id"`
+	OwnerID     string    `db:"owner_id" json:"owner_id"`
 	Name        string    `db:"name" json:"name"`
 	Description string    `db:"description" json:"description"`
 	Price       float64   `db:"price" json:"price"`
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	FlagReason  string    `db:"flag_reason" json:"flag_reason,omitempty"`
+	ViewCount   int64     `db:"view_count" json:"view_count"`
+
+	DefaultLocale string              `db:"default_locale" json:"default_locale"`
+	Translations  []AdvertTranslation `db:"-" json:"translations,omitempty"`
+	PhotoURLs     []string            `db:"-" json:"photo_urls,omitempty"`
 }
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// AdvertFilter narrows down the adverts of a list or statistics query.
+// Nil fields are not applied; CreatedTo is exclusive.
+type AdvertFilter struct {
+	MinPrice    *float64
+	MaxPrice    *float64
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// PriceStats holds price aggregates over a set of adverts;
+// the price fields are nil when the set is empty.
+type PriceStats struct {
+	Count  int64    `db:"count"`
+	Min    *float64 `db:"min"`
+	Max    *float64 `db:"max"`
+	Avg    *float64 `db:"avg"`
+	Median *float64 `db:"median"`
+}
+
+// DayCount is the number of adverts created on a single day.
+type DayCount struct {
+	Day   time.Time `db:"day"`
+	Count int64     `db:"count"`
+}
@@ -0,0 +1,10 @@
+package model
+
+// AdvertTranslation is the name and description of an advert in one locale
+// (a BCP 47 tag such as "en" or "ru").
+type AdvertTranslation struct {
+	AdvertID    int    `db:"advert_id" json:"-"`
+	Locale      string `db:"locale" json:"locale"`
+	Name        string `db:"name" json:"name"`
+	Description string `db:"description" json:"description"`
+}
@@ -0,0 +1,12 @@
+package model
+
+// DuplicatePhoto is a photo whose perceptual hash is shared with
+// photos of adverts belonging to other owners.
+type DuplicatePhoto struct {
+	PHash      int64  `db:"phash" json:"-"`
+	PhotoID    int    `db:"photo_id" json:"photo_id"`
+	URL        string `db:"url" json:"url"`
+	AdvertID   int    `db:"advert_id" json:"advert_id"`
+	OwnerID    string `db:"owner_id" json:"owner_id"`
+	FlagReason string `db:"flag_reason" json:"flag_reason,omitempty"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Favorite statuses reported to the buyer.
+const (
+	FavoriteStatusAvailable    = "available"
+	FavoriteStatusPriceChanged = "price_changed"
+	FavoriteStatusDeleted      = "deleted"
+)
+
+// Favorite is an advert bookmarked by a user. Name and PriceAtAdd are
+// a snapshot taken when the advert was favorited.
+type Favorite struct {
+	UserID     string    `db:"user_id" json:"-"`
+	AdvertID   int       `db:"advert_id" json:"advert_id"`
+	Name       string    `db:"name" json:"name"`
+	PriceAtAdd float64   `db:"price_at_add" json:"price_at_add"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
+
+// FavoriteAdvert is a Favorite joined with the current state of its advert;
+// CurrentPrice is nil when the advert has been deleted.
+type FavoriteAdvert struct {
+	Favorite
+	CurrentPrice *float64 `db:"current_price" json:"current_price"`
+	MainPhotoURL string   `db:"main_photo_url" json:"main_photo_url"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Advert domain events, recorded in the outbox together with the change.
+const (
+	EventAdvertCreated = "advert.created"
+	EventAdvertUpdated = "advert.updated"
+	EventAdvertDeleted = "advert.deleted"
+)
+
+// OutboxEvent is a domain event waiting to be published.
+// AggregateID is the advert the event belongs to; events of one advert
+// are published in ID order. Payload is JSON. DeliveredTo lists the names of
+// the publishers that already took the event, comma-separated, so a retry
+// only goes to the ones that failed.
+type OutboxEvent struct {
+	ID            int64      `db:"id" json:"id"`
+	AggregateID   int        `db:"aggregate_id" json:"aggregate_id"`
+	Event         string     `db:"event" json:"event"`
+	Payload       string     `db:"payload" json:"payload"`
+	Attempts      int        `db:"attempts" json:"attempts"`
+	NextAttemptAt time.Time  `db:"next_attempt_at" json:"next_attempt_at"`
+	LastError     string     `db:"last_error" json:"last_error,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	PublishedAt   *time.Time `db:"published_at" json:"published_at,omitempty"`
+	DeliveredTo   string     `db:"delivered_to" json:"delivered_to,omitempty"`
+}
+
+// OutboxLag describes the unpublished part of the outbox.
+type OutboxLag struct {
+	Pending int64 `db:"pending"`
+	// OldestAge is the age of the oldest unpublished event in seconds
+	OldestAge float64 `db:"oldest_age"`
+}
@@ -1,10 +1,28 @@
 package model
 
+import "time"
+
+// Photo verification statuses.
+const (
+	PhotoStatusUnchecked = "unchecked"
+	PhotoStatusOK        = "ok"
+	PhotoStatusBroken    = "broken"
+)
+
 // Photo represents a single image belonging to an Advert.
 // Position defines ordering: 1 = main photo, 2+ = gallery order.
+// Variants maps a variant name (thumb, medium, ...) to its URL;
+// it is filled only for uploaded photos once the variants are generated.
+// Status is set by the background URL verifier, CheckError explains a broken photo.
+// PHash is the perceptual (difference) hash of the image, nil until computed.
 type Photo struct {
-	ID       int    `db:"id" json:"id"`
-	AdvertID int    `db:"advert_id" json:"advert_id"`
-	URL      string `db:"url" json:"url"`
-	Position int    `db:"position" json:"position"`
+	ID         int               `db:"id" json:"id"`
+	AdvertID   int               `db:"advert_id" json:"advert_id"`
+	URL        string            `db:"url" json:"url"`
+	Position   int               `db:"position" json:"position"`
+	Status     string            `db:"status" json:"status"`
+	CheckError string            `db:"check_error" json:"check_error,omitempty"`
+	CheckedAt  *time.Time        `db:"checked_at" json:"checked_at,omitempty"`
+	PHash      *int64            `db:"phash" json:"-"`
+	Variants   map[string]string `db:"-" json:"variants,omitempty"`
 }
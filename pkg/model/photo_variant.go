@@ -0,0 +1,10 @@
+package model
+
+// PhotoVariant is a resized copy of an uploaded Photo.
+type PhotoVariant struct {
+	PhotoID int    `db:"photo_id" json:"photo_id"`
+	Name    string `db:"name" json:"name"`
+	URL     string `db:"url" json:"url"`
+	Width   int    `db:"width" json:"width"`
+	Height  int    `db:"height" json:"height"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// SavedSearch is a stored list query (filters plus sort) of a user,
+// who is notified about new adverts matching it.
+type SavedSearch struct {
+	ID          int        `db:"id" json:"id"`
+	UserID      string     `db:"user_id" json:"-"`
+	Email       string     `db:"email" json:"email,omitempty"`
+	Name        string     `db:"name" json:"name"`
+	MinPrice    *float64   `db:"min_price" json:"min_price,omitempty"`
+	MaxPrice    *float64   `db:"max_price" json:"max_price,omitempty"`
+	CreatedFrom *time.Time `db:"created_from" json:"created_from,omitempty"`
+	CreatedTo   *time.Time `db:"created_to" json:"created_to,omitempty"`
+	Sort        string     `db:"sort" json:"sort,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+}
+
+// Filter returns the list filter of the search.
+func (s SavedSearch) Filter() AdvertFilter {
+	return AdvertFilter{
+		MinPrice:    s.MinPrice,
+		MaxPrice:    s.MaxPrice,
+		CreatedFrom: s.CreatedFrom,
+		CreatedTo:   s.CreatedTo,
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Webhook delivery statuses; a dead delivery exhausted its retries.
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusDead      = "dead"
+)
+
+// WebhookSubscription is an endpoint receiving the listed advert events.
+// Secret is only shown when the subscription is created.
+type WebhookSubscription struct {
+	ID        int            `db:"id" json:"id"`
+	URL       string         `db:"url" json:"url"`
+	Secret    string         `db:"secret" json:"secret,omitempty"`
+	Events    pq.StringArray `db:"events" json:"events" swaggertype:"array,string"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+}
+
+// WebhookDelivery is a single event sent (or to be sent) to a subscription.
+type WebhookDelivery struct {
+	ID             int64      `db:"id" json:"id"`
+	SubscriptionID int        `db:"subscription_id" json:"subscription_id"`
+	Event          string     `db:"event" json:"event"`
+	Payload        string     `db:"payload" json:"payload"`
+	Status         string     `db:"status" json:"status"`
+	Attempts       int        `db:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at" json:"next_attempt_at"`
+	LastError      string     `db:"last_error" json:"last_error,omitempty"`
+	ResponseCode   int        `db:"response_code" json:"response_code,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	DeliveredAt    *time.Time `db:"delivered_at" json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogNotifier writes every notification as a JSON line, e.g. to stdout or a file.
+type LogNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewLogNotifier(w io.Writer) *LogNotifier {
+	return &LogNotifier{w: w}
+}
+
+// NewFileNotifier appends notifications to the file at path.
+func NewFileNotifier(path string) (*LogNotifier, error) {
+	if path == "" {
+		return nil, errors.New("notify file is not set")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open notify file %s: %w", path, err)
+	}
+	return NewLogNotifier(f), nil
+}
+
+func (n *LogNotifier) Notify(_ context.Context, msg Notification) error {
+	line, err := json.Marshal(struct {
+		Time time.Time `json:"time"`
+		Notification
+	}{Time: time.Now().UTC(), Notification: msg})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err = n.w.Write(append(line, '\n'))
+	return err
+}
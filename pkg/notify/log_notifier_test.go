@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogNotifier_Notify(t *testing.T) {
+	var buf bytes.Buffer
+	n := NewLogNotifier(&buf)
+
+	assert.NoError(t, n.Notify(context.Background(), Notification{UserID: "alice", Subject: "s", Body: "b"}))
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "alice", line["user_id"])
+	assert.Equal(t, "s", line["subject"])
+	assert.Contains(t, line, "time")
+}
+
+func TestFileNotifier_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		n, err := NewFileNotifier(path)
+		assert.NoError(t, err)
+		assert.NoError(t, n.Notify(ctx, Notification{UserID: "alice"}))
+	}
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 2)
+}
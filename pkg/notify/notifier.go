@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/configs"
+)
+
+// Notification is a message for a single user.
+type Notification struct {
+	UserID string `json:"user_id"`
+	// To is the e-mail address, required by SMTPNotifier only
+	To      string `json:"to,omitempty"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Notifier delivers notifications to users.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// NewNotifier builds the Notifier selected by cfg.Notify.Driver ("log", "file" or "smtp")
+func NewNotifier(cfg *configs.Config) (Notifier, error) {
+	switch cfg.Notify.Driver {
+	case "", "log":
+		return NewLogNotifier(os.Stdout), nil
+	case "file":
+		return NewFileNotifier(cfg.Notify.File)
+	case "smtp":
+		smtp := cfg.Notify.SMTP
+		return NewSMTPNotifier(smtp.Host, smtp.Port, smtp.From, smtp.Username, smtp.Password), nil
+	default:
+		return nil, fmt.Errorf("unknown notify driver %q", cfg.Notify.Driver)
+	}
+}
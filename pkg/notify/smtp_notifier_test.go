@@ -0,0 +1,126 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// receivedMail is what the fake SMTP server got in one session
+type receivedMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer accepts a single plain SMTP session and reports the received mail
+func fakeSMTPServer(t *testing.T) (string, int, <-chan receivedMail) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	mails := make(chan receivedMail, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+		var mail receivedMail
+		reply("220 fake ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimRight(line, "\r\n")
+			switch upper := strings.ToUpper(cmd); {
+			case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+				reply("250 fake")
+			case strings.HasPrefix(upper, "MAIL FROM:"):
+				mail.from = strings.Trim(cmd[len("MAIL FROM:"):], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(upper, "RCPT TO:"):
+				mail.to = append(mail.to, strings.Trim(cmd[len("RCPT TO:"):], "<>"))
+				reply("250 OK")
+			case upper == "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				mail.data = data.String()
+				reply("250 queued")
+			case upper == "QUIT":
+				reply("221 bye")
+				mails <- mail
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return host, p, mails
+}
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	host, port, mails := fakeSMTPServer(t)
+	n := NewSMTPNotifier(host, port, "adverts@example.com", "", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := n.Notify(ctx, Notification{
+		UserID:  "alice",
+		To:      "alice@example.com",
+		Subject: "Новое объявление",
+		Body:    "Bike for 100\n.\nsee /api/adverts/1",
+	})
+	assert.NoError(t, err)
+
+	select {
+	case mail := <-mails:
+		assert.Equal(t, "adverts@example.com", mail.from)
+		assert.Equal(t, []string{"alice@example.com"}, mail.to)
+		assert.Contains(t, mail.data, "To: alice@example.com\r\n")
+		assert.Contains(t, mail.data, "Subject: =?utf-8?q?")
+		// A lone dot is escaped by the client
+		assert.Contains(t, mail.data, "Bike for 100\r\n..\r\nsee /api/adverts/1\r\n")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+	}
+}
+
+func TestSMTPNotifier_Validation(t *testing.T) {
+	n := NewSMTPNotifier("127.0.0.1", 1, "adverts@example.com", "", "")
+	ctx := context.Background()
+
+	assert.Error(t, n.Notify(ctx, Notification{Subject: "no recipient"}))
+	assert.Error(t, n.Notify(ctx, Notification{To: "a@example.com\r\nBcc: b@example.com"}))
+}
+
+func TestSMTPNotifier_AuthRequiresServerSupport(t *testing.T) {
+	host, port, _ := fakeSMTPServer(t)
+	n := NewSMTPNotifier(host, port, "adverts@example.com", "user", "secret")
+
+	err := n.Notify(context.Background(), Notification{To: "alice@example.com"})
+	assert.ErrorContains(t, err, "authentication")
+}
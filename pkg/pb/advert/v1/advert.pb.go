@@ -0,0 +1,980 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: advert/v1/advert.proto
+
+package advertv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Photos      []string               `protobuf:"bytes,3,rep,name=photos,proto3" json:"photos,omitempty"`
+	Price       float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	// locale of name and description (BCP 47), "en" if empty
+	Locale string `protobuf:"bytes,5,opt,name=locale,proto3" json:"locale,omitempty"`
+	// translations adds texts in other locales, keyed by locale
+	Translations  map[string]*AdvertText `protobuf:"bytes,6,rep,name=translations,proto3" json:"translations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
+	mi := &file_advert_v1_advert_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequest) ProtoMessage() {}
+
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetPhotos() []string {
+	if x != nil {
+		return x.Photos
+	}
+	return nil
+}
+
+func (x *CreateRequest) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CreateRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetTranslations() map[string]*AdvertText {
+	if x != nil {
+		return x.Translations
+	}
+	return nil
+}
+
+type AdvertText struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdvertText) Reset() {
+	*x = AdvertText{}
+	mi := &file_advert_v1_advert_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdvertText) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdvertText) ProtoMessage() {}
+
+func (x *AdvertText) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdvertText.ProtoReflect.Descriptor instead.
+func (*AdvertText) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AdvertText) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AdvertText) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type CreateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateResponse) Reset() {
+	*x = CreateResponse{}
+	mi := &file_advert_v1_advert_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResponse) ProtoMessage() {}
+
+func (x *CreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResponse.ProtoReflect.Descriptor instead.
+func (*CreateResponse) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetByIDRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// fields adds description, all photos, favorite and view counts
+	Fields        bool `protobuf:"varint,2,opt,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetByIDRequest) Reset() {
+	*x = GetByIDRequest{}
+	mi := &file_advert_v1_advert_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetByIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetByIDRequest) ProtoMessage() {}
+
+func (x *GetByIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetByIDRequest.ProtoReflect.Descriptor instead.
+func (*GetByIDRequest) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetByIDRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetByIDRequest) GetFields() bool {
+	if x != nil {
+		return x.Fields
+	}
+	return false
+}
+
+type AdvertSummary struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	MainPhotoUrl      string                 `protobuf:"bytes,3,opt,name=main_photo_url,json=mainPhotoUrl,proto3" json:"main_photo_url,omitempty"`
+	MainPhotoThumbUrl string                 `protobuf:"bytes,4,opt,name=main_photo_thumb_url,json=mainPhotoThumbUrl,proto3" json:"main_photo_thumb_url,omitempty"`
+	Price             float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AdvertSummary) Reset() {
+	*x = AdvertSummary{}
+	mi := &file_advert_v1_advert_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdvertSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdvertSummary) ProtoMessage() {}
+
+func (x *AdvertSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdvertSummary.ProtoReflect.Descriptor instead.
+func (*AdvertSummary) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AdvertSummary) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AdvertSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AdvertSummary) GetMainPhotoUrl() string {
+	if x != nil {
+		return x.MainPhotoUrl
+	}
+	return ""
+}
+
+func (x *AdvertSummary) GetMainPhotoThumbUrl() string {
+	if x != nil {
+		return x.MainPhotoThumbUrl
+	}
+	return ""
+}
+
+func (x *AdvertSummary) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+type Advert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Summary       *AdvertSummary         `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	AllPhotosUrls []string               `protobuf:"bytes,3,rep,name=all_photos_urls,json=allPhotosUrls,proto3" json:"all_photos_urls,omitempty"`
+	FavoriteCount int64                  `protobuf:"varint,4,opt,name=favorite_count,json=favoriteCount,proto3" json:"favorite_count,omitempty"`
+	ViewCount     int64                  `protobuf:"varint,5,opt,name=view_count,json=viewCount,proto3" json:"view_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Advert) Reset() {
+	*x = Advert{}
+	mi := &file_advert_v1_advert_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Advert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Advert) ProtoMessage() {}
+
+func (x *Advert) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Advert.ProtoReflect.Descriptor instead.
+func (*Advert) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Advert) GetSummary() *AdvertSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *Advert) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Advert) GetAllPhotosUrls() []string {
+	if x != nil {
+		return x.AllPhotosUrls
+	}
+	return nil
+}
+
+func (x *Advert) GetFavoriteCount() int64 {
+	if x != nil {
+		return x.FavoriteCount
+	}
+	return 0
+}
+
+func (x *Advert) GetViewCount() int64 {
+	if x != nil {
+		return x.ViewCount
+	}
+	return 0
+}
+
+type ListRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// page is 1-based, 0 means the first page
+	Page int32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	// sort_field is "price", "date" or "popular", sort_order "asc" or "desc"
+	SortField   string                 `protobuf:"bytes,2,opt,name=sort_field,json=sortField,proto3" json:"sort_field,omitempty"`
+	SortOrder   string                 `protobuf:"bytes,3,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	MinPrice    *float64               `protobuf:"fixed64,4,opt,name=min_price,json=minPrice,proto3,oneof" json:"min_price,omitempty"`
+	MaxPrice    *float64               `protobuf:"fixed64,5,opt,name=max_price,json=maxPrice,proto3,oneof" json:"max_price,omitempty"`
+	CreatedFrom *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	// created_to is exclusive
+	CreatedTo     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_advert_v1_advert_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListRequest) GetSortField() string {
+	if x != nil {
+		return x.SortField
+	}
+	return ""
+}
+
+func (x *ListRequest) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+func (x *ListRequest) GetMinPrice() float64 {
+	if x != nil && x.MinPrice != nil {
+		return *x.MinPrice
+	}
+	return 0
+}
+
+func (x *ListRequest) GetMaxPrice() float64 {
+	if x != nil && x.MaxPrice != nil {
+		return *x.MaxPrice
+	}
+	return 0
+}
+
+func (x *ListRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *ListRequest) GetCreatedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return nil
+}
+
+type ListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Adverts       []*AdvertSummary       `protobuf:"bytes,1,rep,name=adverts,proto3" json:"adverts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_advert_v1_advert_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListResponse) GetAdverts() []*AdvertSummary {
+	if x != nil {
+		return x.Adverts
+	}
+	return nil
+}
+
+// PhotoList distinguishes "replace photos" from "keep photos" in updates
+type PhotoList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Urls          []string               `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PhotoList) Reset() {
+	*x = PhotoList{}
+	mi := &file_advert_v1_advert_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PhotoList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PhotoList) ProtoMessage() {}
+
+func (x *PhotoList) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PhotoList.ProtoReflect.Descriptor instead.
+func (*PhotoList) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PhotoList) GetUrls() []string {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+type UpdateRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Description *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Photos      *PhotoList             `protobuf:"bytes,4,opt,name=photos,proto3" json:"photos,omitempty"`
+	Price       *float64               `protobuf:"fixed64,5,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	// locale of name and description (BCP 47), the default locale of the advert if not set
+	Locale *string `protobuf:"bytes,6,opt,name=locale,proto3,oneof" json:"locale,omitempty"`
+	// translations adds or replaces texts in other locales, keyed by locale
+	Translations map[string]*AdvertText `protobuf:"bytes,7,rep,name=translations,proto3" json:"translations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// remove_translations removes the texts of these locales
+	RemoveTranslations []string `protobuf:"bytes,8,rep,name=remove_translations,json=removeTranslations,proto3" json:"remove_translations,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UpdateRequest) Reset() {
+	*x = UpdateRequest{}
+	mi := &file_advert_v1_advert_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRequest) ProtoMessage() {}
+
+func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRequest) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetPhotos() *PhotoList {
+	if x != nil {
+		return x.Photos
+	}
+	return nil
+}
+
+func (x *UpdateRequest) GetPrice() float64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
+	}
+	return 0
+}
+
+func (x *UpdateRequest) GetLocale() string {
+	if x != nil && x.Locale != nil {
+		return *x.Locale
+	}
+	return ""
+}
+
+func (x *UpdateRequest) GetTranslations() map[string]*AdvertText {
+	if x != nil {
+		return x.Translations
+	}
+	return nil
+}
+
+func (x *UpdateRequest) GetRemoveTranslations() []string {
+	if x != nil {
+		return x.RemoveTranslations
+	}
+	return nil
+}
+
+type UpdateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateResponse) Reset() {
+	*x = UpdateResponse{}
+	mi := &file_advert_v1_advert_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateResponse) ProtoMessage() {}
+
+func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateResponse.ProtoReflect.Descriptor instead.
+func (*UpdateResponse) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{10}
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_advert_v1_advert_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_advert_v1_advert_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_advert_v1_advert_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_advert_v1_advert_proto_rawDescGZIP(), []int{12}
+}
+
+var File_advert_v1_advert_proto protoreflect.FileDescriptor
+
+const file_advert_v1_advert_proto_rawDesc = "" +
+	"\n" +
+	"\x16advert/v1/advert.proto\x12\tadvert.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb3\x02\n" +
+	"\rCreateRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06photos\x18\x03 \x03(\tR\x06photos\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x16\n" +
+	"\x06locale\x18\x05 \x01(\tR\x06locale\x12N\n" +
+	"\ftranslations\x18\x06 \x03(\v2*.advert.v1.CreateRequest.TranslationsEntryR\ftranslations\x1aV\n" +
+	"\x11TranslationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.advert.v1.AdvertTextR\x05value:\x028\x01\"B\n" +
+	"\n" +
+	"AdvertText\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\" \n" +
+	"\x0eCreateResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"8\n" +
+	"\x0eGetByIDRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06fields\x18\x02 \x01(\bR\x06fields\"\xa0\x01\n" +
+	"\rAdvertSummary\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12$\n" +
+	"\x0emain_photo_url\x18\x03 \x01(\tR\fmainPhotoUrl\x12/\n" +
+	"\x14main_photo_thumb_url\x18\x04 \x01(\tR\x11mainPhotoThumbUrl\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\"\xcc\x01\n" +
+	"\x06Advert\x122\n" +
+	"\asummary\x18\x01 \x01(\v2\x18.advert.v1.AdvertSummaryR\asummary\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12&\n" +
+	"\x0fall_photos_urls\x18\x03 \x03(\tR\rallPhotosUrls\x12%\n" +
+	"\x0efavorite_count\x18\x04 \x01(\x03R\rfavoriteCount\x12\x1d\n" +
+	"\n" +
+	"view_count\x18\x05 \x01(\x03R\tviewCount\"\xb9\x02\n" +
+	"\vListRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1d\n" +
+	"\n" +
+	"sort_field\x18\x02 \x01(\tR\tsortField\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\x03 \x01(\tR\tsortOrder\x12 \n" +
+	"\tmin_price\x18\x04 \x01(\x01H\x00R\bminPrice\x88\x01\x01\x12 \n" +
+	"\tmax_price\x18\x05 \x01(\x01H\x01R\bmaxPrice\x88\x01\x01\x12=\n" +
+	"\fcreated_from\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x129\n" +
+	"\n" +
+	"created_to\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedToB\f\n" +
+	"\n" +
+	"_min_priceB\f\n" +
+	"\n" +
+	"_max_price\"B\n" +
+	"\fListResponse\x122\n" +
+	"\aadverts\x18\x01 \x03(\v2\x18.advert.v1.AdvertSummaryR\aadverts\"\x1f\n" +
+	"\tPhotoList\x12\x12\n" +
+	"\x04urls\x18\x01 \x03(\tR\x04urls\"\xcc\x03\n" +
+	"\rUpdateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\x03 \x01(\tH\x01R\vdescription\x88\x01\x01\x12,\n" +
+	"\x06photos\x18\x04 \x01(\v2\x14.advert.v1.PhotoListR\x06photos\x12\x19\n" +
+	"\x05price\x18\x05 \x01(\x01H\x02R\x05price\x88\x01\x01\x12\x1b\n" +
+	"\x06locale\x18\x06 \x01(\tH\x03R\x06locale\x88\x01\x01\x12N\n" +
+	"\ftranslations\x18\a \x03(\v2*.advert.v1.UpdateRequest.TranslationsEntryR\ftranslations\x12/\n" +
+	"\x13remove_translations\x18\b \x03(\tR\x12removeTranslations\x1aV\n" +
+	"\x11TranslationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.advert.v1.AdvertTextR\x05value:\x028\x01B\a\n" +
+	"\x05_nameB\x0e\n" +
+	"\f_descriptionB\b\n" +
+	"\x06_priceB\t\n" +
+	"\a_locale\"\x10\n" +
+	"\x0eUpdateResponse\"\x1f\n" +
+	"\rDeleteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x10\n" +
+	"\x0eDeleteResponse2\xbe\x02\n" +
+	"\rAdvertService\x12=\n" +
+	"\x06Create\x12\x18.advert.v1.CreateRequest\x1a\x19.advert.v1.CreateResponse\x127\n" +
+	"\aGetByID\x12\x19.advert.v1.GetByIDRequest\x1a\x11.advert.v1.Advert\x127\n" +
+	"\x04List\x12\x16.advert.v1.ListRequest\x1a\x17.advert.v1.ListResponse\x12=\n" +
+	"\x06Update\x12\x18.advert.v1.UpdateRequest\x1a\x19.advert.v1.UpdateResponse\x12=\n" +
+	"\x06Delete\x12\x18.advert.v1.DeleteRequest\x1a\x19.advert.v1.DeleteResponseBUZSgithub.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/pb/advert/v1;advertv1b\x06proto3"
+
+var (
+	file_advert_v1_advert_proto_rawDescOnce sync.Once
+	file_advert_v1_advert_proto_rawDescData []byte
+)
+
+func file_advert_v1_advert_proto_rawDescGZIP() []byte {
+	file_advert_v1_advert_proto_rawDescOnce.Do(func() {
+		file_advert_v1_advert_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_advert_v1_advert_proto_rawDesc), len(file_advert_v1_advert_proto_rawDesc)))
+	})
+	return file_advert_v1_advert_proto_rawDescData
+}
+
+var file_advert_v1_advert_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_advert_v1_advert_proto_goTypes = []any{
+	(*CreateRequest)(nil),         // 0: advert.v1.CreateRequest
+	(*AdvertText)(nil),            // 1: advert.v1.AdvertText
+	(*CreateResponse)(nil),        // 2: advert.v1.CreateResponse
+	(*GetByIDRequest)(nil),        // 3: advert.v1.GetByIDRequest
+	(*AdvertSummary)(nil),         // 4: advert.v1.AdvertSummary
+	(*Advert)(nil),                // 5: advert.v1.Advert
+	(*ListRequest)(nil),           // 6: advert.v1.ListRequest
+	(*ListResponse)(nil),          // 7: advert.v1.ListResponse
+	(*PhotoList)(nil),             // 8: advert.v1.PhotoList
+	(*UpdateRequest)(nil),         // 9: advert.v1.UpdateRequest
+	(*UpdateResponse)(nil),        // 10: advert.v1.UpdateResponse
+	(*DeleteRequest)(nil),         // 11: advert.v1.DeleteRequest
+	(*DeleteResponse)(nil),        // 12: advert.v1.DeleteResponse
+	nil,                           // 13: advert.v1.CreateRequest.TranslationsEntry
+	nil,                           // 14: advert.v1.UpdateRequest.TranslationsEntry
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+}
+var file_advert_v1_advert_proto_depIdxs = []int32{
+	13, // 0: advert.v1.CreateRequest.translations:type_name -> advert.v1.CreateRequest.TranslationsEntry
+	4,  // 1: advert.v1.Advert.summary:type_name -> advert.v1.AdvertSummary
+	15, // 2: advert.v1.ListRequest.created_from:type_name -> google.protobuf.Timestamp
+	15, // 3: advert.v1.ListRequest.created_to:type_name -> google.protobuf.Timestamp
+	4,  // 4: advert.v1.ListResponse.adverts:type_name -> advert.v1.AdvertSummary
+	8,  // 5: advert.v1.UpdateRequest.photos:type_name -> advert.v1.PhotoList
+	14, // 6: advert.v1.UpdateRequest.translations:type_name -> advert.v1.UpdateRequest.TranslationsEntry
+	1,  // 7: advert.v1.CreateRequest.TranslationsEntry.value:type_name -> advert.v1.AdvertText
+	1,  // 8: advert.v1.UpdateRequest.TranslationsEntry.value:type_name -> advert.v1.AdvertText
+	0,  // 9: advert.v1.AdvertService.Create:input_type -> advert.v1.CreateRequest
+	3,  // 10: advert.v1.AdvertService.GetByID:input_type -> advert.v1.GetByIDRequest
+	6,  // 11: advert.v1.AdvertService.List:input_type -> advert.v1.ListRequest
+	9,  // 12: advert.v1.AdvertService.Update:input_type -> advert.v1.UpdateRequest
+	11, // 13: advert.v1.AdvertService.Delete:input_type -> advert.v1.DeleteRequest
+	2,  // 14: advert.v1.AdvertService.Create:output_type -> advert.v1.CreateResponse
+	5,  // 15: advert.v1.AdvertService.GetByID:output_type -> advert.v1.Advert
+	7,  // 16: advert.v1.AdvertService.List:output_type -> advert.v1.ListResponse
+	10, // 17: advert.v1.AdvertService.Update:output_type -> advert.v1.UpdateResponse
+	12, // 18: advert.v1.AdvertService.Delete:output_type -> advert.v1.DeleteResponse
+	14, // [14:19] is the sub-list for method output_type
+	9,  // [9:14] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_advert_v1_advert_proto_init() }
+func file_advert_v1_advert_proto_init() {
+	if File_advert_v1_advert_proto != nil {
+		return
+	}
+	file_advert_v1_advert_proto_msgTypes[6].OneofWrappers = []any{}
+	file_advert_v1_advert_proto_msgTypes[9].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_advert_v1_advert_proto_rawDesc), len(file_advert_v1_advert_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_advert_v1_advert_proto_goTypes,
+		DependencyIndexes: file_advert_v1_advert_proto_depIdxs,
+		MessageInfos:      file_advert_v1_advert_proto_msgTypes,
+	}.Build()
+	File_advert_v1_advert_proto = out.File
+	file_advert_v1_advert_proto_goTypes = nil
+	file_advert_v1_advert_proto_depIdxs = nil
+}
@@ -5,15 +5,31 @@ import (
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
 )
 
+// AdvertRepo stores adverts. Create, Update and Delete record the matching
+// advert event in the outbox within the same transaction, Create and Update
+// also store ad.Translations and ad.PhotoURLs there.
 type AdvertRepo interface {
 	// Create a new advert and return its ID
 	Create(ctx context.Context, ad model.Advert) (int, error)
-	// Retrieve list of adverts with pagination & sorting
-	List(ctx context.Context, limit, offset int, sortField, sortOrder string) ([]model.Advert, error)
+	// Retrieve list of filtered adverts with pagination & sorting
+	List(ctx context.Context, filter model.AdvertFilter, limit, offset int, sortField, sortOrder string) ([]model.Advert, error)
 	// Get single advert by ID
 	GetByID(ctx context.Context, id int) (model.Advert, error)
-	// Update an existing advert
+	// ListByIDs returns several adverts at once ordered by ID; unknown IDs are skipped
+	ListByIDs(ctx context.Context, ids []int) ([]model.Advert, error)
+	// Update an existing advert; nil ad.Translations or ad.PhotoURLs keep the
+	// stored ones, otherwise they replace them. A missing advert is sql.ErrNoRows
 	Update(ctx context.Context, ad model.Advert) error
-	// Delete advert by ID (cascade removes photos)
+	// ListTranslations returns the translations of several adverts at once, keyed by
+	// advert ID and ordered by locale; adverts without translations are missing from the map
+	ListTranslations(ctx context.Context, advertIDs []int) (map[int][]model.AdvertTranslation, error)
+	// Delete advert by ID (cascade removes photos); a missing advert is sql.ErrNoRows
 	Delete(ctx context.Context, id int) error
+	// SetFlag marks the advert as suspicious with a human-readable reason ("" clears the flag)
+	SetFlag(ctx context.Context, id int, reason string) error
+	// AddViews increments view counters by advert ID; unknown IDs are ignored
+	AddViews(ctx context.Context, views map[int]int64) error
+	// Stream walks over the filtered adverts with sorting, fetching batchSize rows at a time,
+	// and calls fn for each advert together with its photo URLs ordered by position
+	Stream(ctx context.Context, filter model.AdvertFilter, sortField, sortOrder string, batchSize int, fn func(ad model.Advert, photoURLs []string) error) error
 }
@@ -0,0 +1,12 @@
+package repository
+
+import "context"
+
+// ChangeFeed broadcasts short messages about advert changes to every replica.
+type ChangeFeed interface {
+	// Publish sends msg to all listeners, including the ones of other replicas
+	Publish(ctx context.Context, msg string) error
+	// Listen calls fn for every published message until ctx is cancelled.
+	// Messages published while the connection is being re-established are lost.
+	Listen(ctx context.Context, fn func(msg string)) error
+}
@@ -1,18 +1,56 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/configs"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
 )
 
-// NewDb builds DSN from cfg and returns a connected *sqlx.DB
+// NewDb builds DSN from cfg and returns a connected *sqlx.DB of cfg.DB.Driver,
+// "postgres" (the default) or "sqlite"
 func NewDb(cfg *configs.Config) (*sqlx.DB, error) {
-	dsn := fmt.Sprintf(
+	switch cfg.DB.Driver {
+	case "", "postgres":
+		return sqlx.Connect("postgres", DSN(cfg))
+	case "sqlite":
+		return sqlx.Connect("sqlite", DSN(cfg))
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.DB.Driver)
+	}
+}
+
+// DSN returns the connection string for cfg: the Postgres one, or the SQLite
+// database file with foreign keys on, WAL, a busy timeout, transactions taking
+// the write lock up front and timestamps in the SQLite text format
+func DSN(cfg *configs.Config) string {
+	if cfg.DB.Driver == "sqlite" {
+		return fmt.Sprintf(
+			"file:%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate&_time_format=sqlite",
+			cfg.DB.Path,
+		)
+	}
+	return fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 		cfg.DB.Host, cfg.DB.Port, cfg.DB.User, cfg.DB.Password, cfg.DB.Name,
 	)
-	return sqlx.Connect("postgres", dsn)
+}
+
+// InTx runs fn in a transaction started with opts and commits it when fn succeeds
+func InTx(ctx context.Context, db *sqlx.DB, opts *sql.TxOptions, fn func(tx *sqlx.Tx) error) error {
+	tx, err := db.BeginTxx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	// Rollback after a successful Commit is a no-op
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
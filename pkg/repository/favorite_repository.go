@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+type FavoriteRepo interface {
+	// Add bookmarks an advert; adding it twice keeps the original snapshot
+	Add(ctx context.Context, fav model.Favorite) error
+	// Remove deletes a bookmark, returns sql.ErrNoRows if there is none
+	Remove(ctx context.Context, userID string, advertID int) error
+	// List returns the user's favorites with the current state of the adverts,
+	// including deleted ones. sortField is "id", "price", "created_at" (favorited at) or "view_count"
+	List(ctx context.Context, userID string, limit, offset int, sortField, sortOrder string) ([]model.FavoriteAdvert, error)
+	// CountByAdvertIDs returns how many users favorited each of the adverts at once;
+	// adverts nobody favorited are missing from the map
+	CountByAdvertIDs(ctx context.Context, advertIDs []int) (map[int]int, error)
+}
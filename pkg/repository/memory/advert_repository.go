@@ -0,0 +1,257 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+type MemoryAdvertRepo struct {
+	store *Store
+}
+
+func NewMemoryAdvertRepo(store *Store) repository.AdvertRepo {
+	return &MemoryAdvertRepo{store: store}
+}
+
+func (r *MemoryAdvertRepo) Create(ctx context.Context, ad model.Advert) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ad.ID = s.advertID + 1
+	event, err := outboxEvent(model.EventAdvertCreated, ad.ID, ad)
+	if err != nil {
+		return 0, err
+	}
+	s.advertID = ad.ID
+	s.putTranslations(ad.ID, ad.Translations)
+	s.putPhotos(ad.ID, ad.PhotoURLs)
+	s.adverts[ad.ID] = model.Advert{
+		ID:            ad.ID,
+		OwnerID:       ad.OwnerID,
+		Name:          ad.Name,
+		Description:   ad.Description,
+		Price:         roundPrice(ad.Price),
+		CreatedAt:     ad.CreatedAt,
+		DefaultLocale: ad.DefaultLocale,
+	}
+	s.addOutbox(event)
+	return ad.ID, nil
+}
+
+func (r *MemoryAdvertRepo) List(
+	ctx context.Context,
+	filter model.AdvertFilter,
+	limit, offset int,
+	sortField, sortOrder string,
+) ([]model.Advert, error) {
+	order, err := advertOrder(sortField, sortOrder)
+	if err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var ads []model.Advert
+	for _, ad := range r.store.adverts {
+		if matchesFilter(filter, ad) {
+			ads = append(ads, ad)
+		}
+	}
+	slices.SortFunc(ads, order)
+	return page(ads, limit, offset)
+}
+
+func (r *MemoryAdvertRepo) GetByID(ctx context.Context, id int) (model.Advert, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ad, ok := r.store.adverts[id]
+	if !ok {
+		return model.Advert{}, sql.ErrNoRows
+	}
+	return ad, nil
+}
+
+func (r *MemoryAdvertRepo) ListByIDs(ctx context.Context, ids []int) ([]model.Advert, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ads := []model.Advert{}
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(ids))) {
+		if ad, ok := r.store.adverts[id]; ok {
+			ads = append(ads, ad)
+		}
+	}
+	return ads, nil
+}
+
+func (r *MemoryAdvertRepo) Update(ctx context.Context, ad model.Advert) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.adverts[ad.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	event, err := outboxEvent(model.EventAdvertUpdated, ad.ID, ad)
+	if err != nil {
+		return err
+	}
+	stored.Name = ad.Name
+	stored.Description = ad.Description
+	stored.Price = roundPrice(ad.Price)
+	stored.DefaultLocale = ad.DefaultLocale
+	s.adverts[ad.ID] = stored
+	if ad.Translations != nil {
+		s.putTranslations(ad.ID, ad.Translations)
+	}
+	if ad.PhotoURLs != nil {
+		s.putPhotos(ad.ID, ad.PhotoURLs)
+	}
+	s.addOutbox(event)
+	return nil
+}
+
+func (r *MemoryAdvertRepo) ListTranslations(ctx context.Context, advertIDs []int) (map[int][]model.AdvertTranslation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byAdvert := make(map[int][]model.AdvertTranslation)
+	for _, id := range advertIDs {
+		if translations := r.store.translations[id]; len(translations) > 0 {
+			byAdvert[id] = slices.Clone(translations)
+		}
+	}
+	return byAdvert, nil
+}
+
+// putPhotos replaces the photos of an advert with urls at positions 1, 2, ...; the caller holds mu.
+func (s *Store) putPhotos(advertID int, urls []string) {
+	for id, p := range s.photos {
+		if p.AdvertID == advertID {
+			s.deletePhoto(id)
+		}
+	}
+	for i, url := range urls {
+		s.addPhoto(model.Photo{AdvertID: advertID, URL: url, Position: i + 1})
+	}
+}
+
+// putTranslations replaces the texts of an advert, ordered by locale; the caller holds mu.
+func (s *Store) putTranslations(advertID int, translations []model.AdvertTranslation) {
+	if len(translations) == 0 {
+		delete(s.translations, advertID)
+		return
+	}
+	stored := make([]model.AdvertTranslation, len(translations))
+	for i, t := range translations {
+		t.AdvertID = advertID
+		stored[i] = t
+	}
+	slices.SortFunc(stored, func(a, b model.AdvertTranslation) int {
+		return strings.Compare(a.Locale, b.Locale)
+	})
+	s.translations[advertID] = stored
+}
+
+// Delete removes the advert together with its photos and translations,
+// as ON DELETE CASCADE does; favorites keep their snapshot.
+func (r *MemoryAdvertRepo) Delete(ctx context.Context, id int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.adverts[id]; !ok {
+		return sql.ErrNoRows
+	}
+	event, err := outboxEvent(model.EventAdvertDeleted, id, map[string]int{"id": id})
+	if err != nil {
+		return err
+	}
+	delete(s.adverts, id)
+	delete(s.translations, id)
+	for photoID, p := range s.photos {
+		if p.AdvertID == id {
+			s.deletePhoto(photoID)
+		}
+	}
+	s.addOutbox(event)
+	return nil
+}
+
+func (r *MemoryAdvertRepo) SetFlag(ctx context.Context, id int, reason string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if ad, ok := r.store.adverts[id]; ok {
+		ad.FlagReason = reason
+		r.store.adverts[id] = ad
+	}
+	return nil
+}
+
+func (r *MemoryAdvertRepo) AddViews(ctx context.Context, views map[int]int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, n := range views {
+		if ad, ok := r.store.adverts[id]; ok {
+			ad.ViewCount += n
+			r.store.adverts[id] = ad
+		}
+	}
+	return nil
+}
+
+// Stream walks over a snapshot taken at the start, like the read-only transaction
+// of the postgres cursor; fn is called without holding the lock.
+func (r *MemoryAdvertRepo) Stream(
+	ctx context.Context,
+	filter model.AdvertFilter,
+	sortField, sortOrder string,
+	batchSize int,
+	fn func(ad model.Advert, photoURLs []string) error,
+) error {
+	order, err := advertOrder(sortField, sortOrder)
+	if err != nil {
+		return err
+	}
+
+	type row struct {
+		ad        model.Advert
+		photoURLs []string
+	}
+	r.store.mu.RLock()
+	byAdvert := r.store.groupPhotos(func(model.Photo) bool { return true })
+	rows := make([]row, 0, len(r.store.adverts))
+	for _, ad := range r.store.adverts {
+		if !matchesFilter(filter, ad) {
+			continue
+		}
+		photos := byAdvert[ad.ID]
+		urls := make([]string, len(photos))
+		for i, p := range photos {
+			urls[i] = p.URL
+		}
+		rows = append(rows, row{ad: ad, photoURLs: urls})
+	}
+	r.store.mu.RUnlock()
+
+	slices.SortFunc(rows, func(a, b row) int { return order(a.ad, b.ad) })
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(row.ad, row.photoURLs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createAdverts(t *testing.T, repo *MemoryAdvertRepo, prices ...float64) []int {
+	t.Helper()
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ids := make([]int, len(prices))
+	for i, price := range prices {
+		id, err := repo.Create(context.Background(), model.Advert{
+			Name:      "Advert",
+			Price:     price,
+			CreatedAt: created.Add(time.Duration(i) * time.Hour),
+		})
+		require.NoError(t, err)
+		ids[i] = id
+	}
+	return ids
+}
+
+func advertIDs(ads []model.Advert) []int {
+	ids := []int{}
+	for _, ad := range ads {
+		ids = append(ids, ad.ID)
+	}
+	return ids
+}
+
+func TestMemoryAdvertRepo_List(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryAdvertRepo(NewStore()).(*MemoryAdvertRepo)
+	createAdverts(t, repo, 300, 100, 200, 100)
+
+	cases := []struct {
+		name          string
+		filter        model.AdvertFilter
+		limit, offset int
+		field, order  string
+		want          []int
+	}{
+		{"ByIDAsc", model.AdvertFilter{}, 10, 0, "id", "ASC", []int{1, 2, 3, 4}},
+		// Equal prices are ordered by ID
+		{"ByPriceAsc", model.AdvertFilter{}, 10, 0, "price", "ASC", []int{2, 4, 3, 1}},
+		{"ByPriceDesc", model.AdvertFilter{}, 10, 0, "price", "DESC", []int{1, 3, 2, 4}},
+		{"ByCreatedAtDesc", model.AdvertFilter{}, 10, 0, "created_at", "DESC", []int{4, 3, 2, 1}},
+		{"Page", model.AdvertFilter{}, 2, 2, "id", "ASC", []int{3, 4}},
+		{"PastTheEnd", model.AdvertFilter{}, 2, 4, "id", "ASC", []int{}},
+		{"Filter", model.AdvertFilter{MinPrice: floatPtr(150)}, 10, 0, "id", "ASC", []int{1, 3}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ads, err := repo.List(ctx, tc.filter, tc.limit, tc.offset, tc.field, tc.order)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, advertIDs(ads))
+		})
+	}
+
+	_, err := repo.List(ctx, model.AdvertFilter{}, 10, 0, "name; DROP TABLE adverts", "ASC")
+	assert.Error(t, err)
+}
+
+func TestMemoryAdvertRepo_NotFound(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewMemoryAdvertRepo(store)
+
+	_, err := repo.GetByID(ctx, 42)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Like the postgres repo, changing a missing advert fails without an event
+	assert.ErrorIs(t, repo.Update(ctx, model.Advert{ID: 42, Name: "Ghost"}), sql.ErrNoRows)
+	assert.ErrorIs(t, repo.Delete(ctx, 42), sql.ErrNoRows)
+	assert.Empty(t, store.outbox)
+}
+
+func TestMemoryAdvertRepo_DeleteCascades(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	adverts, photos := NewMemoryAdvertRepo(store), NewMemoryPhotoRepo(store)
+
+	id, err := adverts.Create(ctx, model.Advert{
+		Name: "Bike", Price: 10, DefaultLocale: "en",
+		Translations: []model.AdvertTranslation{{Locale: "en", Name: "Bike"}, {Locale: "ru", Name: "Велосипед"}},
+	})
+	require.NoError(t, err)
+	photo, err := photos.Insert(ctx, model.Photo{AdvertID: id, URL: "https://example.com/1.jpg"}, 3)
+	require.NoError(t, err)
+	require.NoError(t, photos.SaveVariant(ctx, model.PhotoVariant{PhotoID: photo.ID, Name: "thumb", URL: "t.jpg"}))
+
+	require.NoError(t, adverts.Delete(ctx, id))
+
+	_, err = adverts.GetByID(ctx, id)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	remaining, err := photos.ListByAdvertID(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+	translations, err := adverts.ListTranslations(ctx, []int{id})
+	require.NoError(t, err)
+	assert.Empty(t, translations)
+	assert.Empty(t, store.variants)
+
+	var events []string
+	for i := int64(1); i <= store.outboxID; i++ {
+		events = append(events, store.outbox[i].Event)
+	}
+	assert.Equal(t, []string{model.EventAdvertCreated, model.EventAdvertUpdated, model.EventAdvertDeleted}, events)
+}
+
+func TestMemoryAdvertRepo_Translations(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryAdvertRepo(NewStore())
+
+	id, err := repo.Create(ctx, model.Advert{
+		Name: "Bike", Price: 10, DefaultLocale: "en",
+		Translations: []model.AdvertTranslation{{Locale: "ru", Name: "Велосипед"}, {Locale: "en", Name: "Bike"}},
+	})
+	require.NoError(t, err)
+
+	// Nil translations keep the stored ones
+	require.NoError(t, repo.Update(ctx, model.Advert{ID: id, Name: "Bicycle", Price: 12.345, DefaultLocale: "en"}))
+	translations, err := repo.ListTranslations(ctx, []int{id, 99})
+	require.NoError(t, err)
+	assert.Equal(t, map[int][]model.AdvertTranslation{id: {
+		{AdvertID: id, Locale: "en", Name: "Bike"},
+		{AdvertID: id, Locale: "ru", Name: "Велосипед"},
+	}}, translations)
+
+	ad, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Bicycle", ad.Name)
+	assert.Equal(t, 12.35, ad.Price)
+}
+
+func TestMemoryAdvertRepo_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	adverts, photos := NewMemoryAdvertRepo(store), NewMemoryPhotoRepo(store)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := adverts.Create(ctx, model.Advert{Name: "Advert", Price: 1})
+			assert.NoError(t, err)
+			for j := 0; j < 3; j++ {
+				_, err := photos.Insert(ctx, model.Photo{AdvertID: id, URL: "https://example.com/photo.jpg", Position: 1}, 3)
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, adverts.AddViews(ctx, map[int]int64{id: 1}))
+			_, err = adverts.List(ctx, model.AdvertFilter{}, 10, 0, "view_count", "DESC")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	ads, err := adverts.List(ctx, model.AdvertFilter{}, 100, 0, "id", "ASC")
+	require.NoError(t, err)
+	require.Len(t, ads, 20)
+	for i, ad := range ads {
+		assert.Equal(t, i+1, ad.ID)
+		assert.Equal(t, int64(1), ad.ViewCount)
+		list, err := photos.ListByAdvertID(ctx, ad.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+		for j, p := range list {
+			assert.Equal(t, j+1, p.Position)
+		}
+	}
+}
+
+func floatPtr(f float64) *float64 { return &f }
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+// MemoryChangeFeed implements repository.ChangeFeed within a single process,
+// there are no other replicas to reach.
+type MemoryChangeFeed struct {
+	mu        sync.Mutex
+	listeners map[*feedListener]struct{}
+}
+
+type feedListener struct {
+	messages chan string
+	// done is closed when Listen returns
+	done chan struct{}
+}
+
+func NewMemoryChangeFeed() repository.ChangeFeed {
+	return &MemoryChangeFeed{listeners: make(map[*feedListener]struct{})}
+}
+
+// Publish waits until every listener has taken msg, or ctx is cancelled.
+func (f *MemoryChangeFeed) Publish(ctx context.Context, msg string) error {
+	f.mu.Lock()
+	listeners := make([]*feedListener, 0, len(f.listeners))
+	for l := range f.listeners {
+		listeners = append(listeners, l)
+	}
+	f.mu.Unlock()
+
+	for _, l := range listeners {
+		select {
+		case l.messages <- msg:
+		case <-l.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (f *MemoryChangeFeed) Listen(ctx context.Context, fn func(msg string)) error {
+	l := &feedListener{messages: make(chan string, 64), done: make(chan struct{})}
+	f.mu.Lock()
+	f.listeners[l] = struct{}{}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		delete(f.listeners, l)
+		f.mu.Unlock()
+		close(l.done)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg := <-l.messages:
+			fn(msg)
+		}
+	}
+}
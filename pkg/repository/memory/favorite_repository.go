@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+type MemoryFavoriteRepo struct {
+	store *Store
+}
+
+func NewMemoryFavoriteRepo(store *Store) repository.FavoriteRepo {
+	return &MemoryFavoriteRepo{store: store}
+}
+
+func (r *MemoryFavoriteRepo) Add(ctx context.Context, fav model.Favorite) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := favoriteKey{userID: fav.UserID, advertID: fav.AdvertID}
+	if _, ok := r.store.favorites[key]; !ok {
+		fav.PriceAtAdd = roundPrice(fav.PriceAtAdd)
+		r.store.favorites[key] = fav
+	}
+	return nil
+}
+
+func (r *MemoryFavoriteRepo) Remove(ctx context.Context, userID string, advertID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := favoriteKey{userID: userID, advertID: advertID}
+	if _, ok := r.store.favorites[key]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.favorites, key)
+	return nil
+}
+
+// List joins the favorites with their adverts; a deleted advert is sorted
+// by the price it had when it was favorited.
+func (r *MemoryFavoriteRepo) List(
+	ctx context.Context,
+	userID string,
+	limit, offset int,
+	sortField, sortOrder string,
+) ([]model.FavoriteAdvert, error) {
+	if sortOrder != "ASC" && sortOrder != "DESC" {
+		return nil, fmt.Errorf("unsupported favorites sort %q %q", sortField, sortOrder)
+	}
+
+	r.store.mu.RLock()
+	views := make(map[int]int64)
+	favs := []model.FavoriteAdvert{}
+	for key, fav := range r.store.favorites {
+		if key.userID != userID {
+			continue
+		}
+		item := model.FavoriteAdvert{Favorite: fav}
+		if ad, ok := r.store.adverts[fav.AdvertID]; ok {
+			item.Name = ad.Name
+			item.CurrentPrice = &ad.Price
+			views[ad.ID] = ad.ViewCount
+		}
+		if photo, ok := r.store.mainPhoto(fav.AdvertID); ok {
+			item.MainPhotoURL = photo.URL
+		}
+		favs = append(favs, item)
+	}
+	r.store.mu.RUnlock()
+
+	var compare func(a, b model.FavoriteAdvert) int
+	switch sortField {
+	case "id":
+		compare = func(a, b model.FavoriteAdvert) int { return cmp.Compare(a.AdvertID, b.AdvertID) }
+	case "price":
+		price := func(f model.FavoriteAdvert) float64 {
+			if f.CurrentPrice != nil {
+				return *f.CurrentPrice
+			}
+			return f.PriceAtAdd
+		}
+		compare = func(a, b model.FavoriteAdvert) int { return cmp.Compare(price(a), price(b)) }
+	case "created_at":
+		compare = func(a, b model.FavoriteAdvert) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	case "view_count":
+		compare = func(a, b model.FavoriteAdvert) int { return cmp.Compare(views[a.AdvertID], views[b.AdvertID]) }
+	default:
+		return nil, fmt.Errorf("unsupported favorites sort %q %q", sortField, sortOrder)
+	}
+	slices.SortFunc(favs, func(a, b model.FavoriteAdvert) int {
+		c := compare(a, b)
+		if sortOrder == "DESC" {
+			c = -c
+		}
+		return cmp.Or(c, cmp.Compare(a.AdvertID, b.AdvertID))
+	})
+	favs, err := page(favs, limit, offset)
+	if favs == nil && err == nil {
+		favs = []model.FavoriteAdvert{}
+	}
+	return favs, err
+}
+
+func (r *MemoryFavoriteRepo) CountByAdvertIDs(ctx context.Context, advertIDs []int) (map[int]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[int]int)
+	for key := range r.store.favorites {
+		if slices.Contains(advertIDs, key.advertID) {
+			counts[key.advertID]++
+		}
+	}
+	return counts, nil
+}
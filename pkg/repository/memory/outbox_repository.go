@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+type MemoryOutboxRepo struct {
+	store *Store
+}
+
+func NewMemoryOutboxRepo(store *Store) repository.OutboxRepo {
+	return &MemoryOutboxRepo{store: store}
+}
+
+// Relay passes the due events to fn without holding the store lock, because
+// publishers read and write the store themselves; concurrent relays are
+// serialized instead of skipping each other's locked events.
+func (r *MemoryOutboxRepo) Relay(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	fn func(e model.OutboxEvent) model.OutboxEvent,
+) (int, error) {
+	s := r.store
+	s.relayMu.Lock()
+	defer s.relayMu.Unlock()
+
+	// Only the head of every advert's queue is eligible: while it is failing,
+	// later events of the same advert wait, which keeps them in order.
+	s.mu.RLock()
+	heads := make(map[int]model.OutboxEvent)
+	for _, e := range s.outbox {
+		if e.PublishedAt != nil {
+			continue
+		}
+		if head, ok := heads[e.AggregateID]; !ok || e.ID < head.ID {
+			heads[e.AggregateID] = e
+		}
+	}
+	s.mu.RUnlock()
+
+	var events []model.OutboxEvent
+	for _, e := range heads {
+		if !e.NextAttemptAt.After(now) {
+			events = append(events, e)
+		}
+	}
+	slices.SortFunc(events, func(a, b model.OutboxEvent) int { return cmp.Compare(a.ID, b.ID) })
+	events, err := page(events, limit, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range events {
+		e = fn(e)
+		s.mu.Lock()
+		if stored, ok := s.outbox[e.ID]; ok {
+			stored.Attempts = e.Attempts
+			stored.NextAttemptAt = e.NextAttemptAt
+			stored.LastError = e.LastError
+			stored.PublishedAt = e.PublishedAt
+			stored.DeliveredTo = e.DeliveredTo
+			s.outbox[e.ID] = stored
+		}
+		s.mu.Unlock()
+	}
+	return len(events), nil
+}
+
+func (r *MemoryOutboxRepo) Lag(ctx context.Context) (model.OutboxLag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var lag model.OutboxLag
+	var oldest time.Time
+	for _, e := range r.store.outbox {
+		if e.PublishedAt != nil {
+			continue
+		}
+		lag.Pending++
+		if oldest.IsZero() || e.CreatedAt.Before(oldest) {
+			oldest = e.CreatedAt
+		}
+	}
+	if lag.Pending > 0 {
+		lag.OldestAge = time.Since(oldest).Seconds()
+	}
+	return lag, nil
+}
+
+func (r *MemoryOutboxRepo) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var n int64
+	for id, e := range r.store.outbox {
+		if e.PublishedAt != nil && e.PublishedAt.Before(before) {
+			delete(r.store.outbox, id)
+			n++
+		}
+	}
+	return n, nil
+}
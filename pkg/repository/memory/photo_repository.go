@@ -0,0 +1,398 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+type MemoryPhotoRepo struct {
+	store *Store
+}
+
+func NewMemoryPhotoRepo(store *Store) repository.PhotoRepo {
+	return &MemoryPhotoRepo{store: store}
+}
+
+func (r *MemoryPhotoRepo) Create(ctx context.Context, photo model.Photo) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.adverts[photo.AdvertID]; !ok {
+		return fmt.Errorf("advert %d does not exist", photo.AdvertID)
+	}
+	for _, p := range s.photos {
+		if p.AdvertID == photo.AdvertID && p.Position == photo.Position {
+			return fmt.Errorf("advert %d already has a photo at position %d", photo.AdvertID, photo.Position)
+		}
+	}
+	s.addPhoto(photo)
+	return nil
+}
+
+// addPhoto stores a new unchecked photo under the next photo ID; the caller holds mu.
+func (s *Store) addPhoto(photo model.Photo) model.Photo {
+	s.photoID++
+	s.photos[s.photoID] = model.Photo{
+		ID:       s.photoID,
+		AdvertID: photo.AdvertID,
+		URL:      photo.URL,
+		Position: photo.Position,
+		Status:   model.PhotoStatusUnchecked,
+	}
+	photo.ID, photo.Status = s.photoID, model.PhotoStatusUnchecked
+	return photo
+}
+
+func (r *MemoryPhotoRepo) GetMainPhotoURL(ctx context.Context, advertID int) (string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	photo, ok := r.store.mainPhoto(advertID)
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return photo.URL, nil
+}
+
+func (r *MemoryPhotoRepo) GetAllPhotoURLs(ctx context.Context, advertID int) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var urls []string
+	for _, p := range r.store.advertPhotos(advertID) {
+		urls = append(urls, p.URL)
+	}
+	return urls, nil
+}
+
+func (r *MemoryPhotoRepo) DeleteByAdvertID(ctx context.Context, advertID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, p := range r.store.photos {
+		if p.AdvertID == advertID {
+			r.store.deletePhoto(id)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryPhotoRepo) ListByAdvertID(ctx context.Context, advertID int) ([]model.Photo, error) {
+	byAdvert, err := r.ListByAdvertIDs(ctx, []int{advertID})
+	if err != nil {
+		return nil, err
+	}
+	if photos := byAdvert[advertID]; photos != nil {
+		return photos, nil
+	}
+	return []model.Photo{}, nil
+}
+
+func (r *MemoryPhotoRepo) ListByAdvertIDs(ctx context.Context, advertIDs []int) (map[int][]model.Photo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byAdvert := r.store.groupPhotos(func(p model.Photo) bool { return slices.Contains(advertIDs, p.AdvertID) })
+	for _, photos := range byAdvert {
+		for i, p := range photos {
+			photos[i].Variants = r.store.variantURLs(p.ID)
+		}
+	}
+	return byAdvert, nil
+}
+
+// variantURLs maps the variant names of the photo to their URLs, nil if there are none;
+// the caller holds mu.
+func (s *Store) variantURLs(photoID int) map[string]string {
+	if len(s.variants[photoID]) == 0 {
+		return nil
+	}
+	urls := make(map[string]string, len(s.variants[photoID]))
+	for name, v := range s.variants[photoID] {
+		urls[name] = v.URL
+	}
+	return urls
+}
+
+func (r *MemoryPhotoRepo) Insert(ctx context.Context, photo model.Photo, maxPhotos int) (model.Photo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.adverts[photo.AdvertID]; !ok {
+		return model.Photo{}, fmt.Errorf("failed to insert photo for advert %d: %w", photo.AdvertID, sql.ErrNoRows)
+	}
+	photos := s.advertPhotos(photo.AdvertID)
+	if len(photos) >= maxPhotos {
+		return model.Photo{}, fmt.Errorf("failed to insert photo for advert %d: %w", photo.AdvertID, repository.ErrPhotoCount)
+	}
+	if photo.Position < 1 || photo.Position > len(photos)+1 {
+		photo.Position = len(photos) + 1
+	}
+	for _, p := range photos {
+		if p.Position >= photo.Position {
+			p.Position++
+			s.photos[p.ID] = p
+		}
+	}
+	photo = s.addPhoto(photo)
+	return photo, s.addPhotosUpdated(photo.AdvertID)
+}
+
+func (r *MemoryPhotoRepo) Delete(ctx context.Context, advertID, photoID int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.adverts[advertID]; !ok {
+		return fmt.Errorf("failed to delete photo %d of advert %d: %w", photoID, advertID, sql.ErrNoRows)
+	}
+	if p, ok := s.photos[photoID]; !ok || p.AdvertID != advertID {
+		return fmt.Errorf("failed to delete photo %d of advert %d: %w", photoID, advertID, sql.ErrNoRows)
+	}
+	if len(s.advertPhotos(advertID)) == 1 {
+		return fmt.Errorf("failed to delete photo %d of advert %d: %w", photoID, advertID, repository.ErrPhotoCount)
+	}
+	s.deletePhoto(photoID)
+	for i, p := range s.advertPhotos(advertID) {
+		p.Position = i + 1
+		s.photos[p.ID] = p
+	}
+	return s.addPhotosUpdated(advertID)
+}
+
+func (r *MemoryPhotoRepo) Reorder(ctx context.Context, advertID int, photoIDs []int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.adverts[advertID]; !ok {
+		return fmt.Errorf("failed to reorder photos of advert %d: %w", advertID, sql.ErrNoRows)
+	}
+	photos := s.advertPhotos(advertID)
+	seen := make(map[int]bool, len(photoIDs))
+	for _, id := range photoIDs {
+		if p, ok := s.photos[id]; !ok || p.AdvertID != advertID || seen[id] {
+			break
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(photos) || len(photoIDs) != len(photos) {
+		return fmt.Errorf("failed to reorder photos of advert %d: photo ids do not match the %d photos of the advert",
+			advertID, len(photos))
+	}
+	for i, id := range photoIDs {
+		p := s.photos[id]
+		p.Position = i + 1
+		s.photos[id] = p
+	}
+	return s.addPhotosUpdated(advertID)
+}
+
+// addPhotosUpdated records the change of the advert's photos in the outbox,
+// like the transactions of the postgres repository; the caller holds mu.
+func (s *Store) addPhotosUpdated(advertID int) error {
+	ad := s.adverts[advertID]
+	ad.PhotoURLs = []string{}
+	for _, p := range s.advertPhotos(advertID) {
+		ad.PhotoURLs = append(ad.PhotoURLs, p.URL)
+	}
+	event, err := outboxEvent(model.EventAdvertUpdated, advertID, ad)
+	if err != nil {
+		return err
+	}
+	s.addOutbox(event)
+	return nil
+}
+
+func (r *MemoryPhotoRepo) SaveVariant(ctx context.Context, variant model.PhotoVariant) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.photos[variant.PhotoID]; !ok {
+		return fmt.Errorf("failed to save %s variant of photo %d: photo does not exist", variant.Name, variant.PhotoID)
+	}
+	if s.variants[variant.PhotoID] == nil {
+		s.variants[variant.PhotoID] = make(map[string]model.PhotoVariant)
+	}
+	s.variants[variant.PhotoID][variant.Name] = variant
+	return nil
+}
+
+func (r *MemoryPhotoRepo) GetMainPhotoVariantURL(ctx context.Context, advertID int, name string) (string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	photo, ok := r.store.mainPhoto(advertID)
+	if !ok {
+		return "", nil
+	}
+	return r.store.variants[photo.ID][name].URL, nil
+}
+
+func (r *MemoryPhotoRepo) ListMissingVariants(ctx context.Context, urlPrefix string, names []string, afterID, limit int) ([]model.Photo, error) {
+	r.store.mu.RLock()
+	photos := slices.Collect(maps.Values(r.store.photos))
+	photos = slices.DeleteFunc(photos, func(p model.Photo) bool {
+		if p.ID <= afterID || !strings.HasPrefix(p.URL, urlPrefix) {
+			return true
+		}
+		for _, name := range names {
+			if _, ok := r.store.variants[p.ID][name]; !ok {
+				return false
+			}
+		}
+		return true
+	})
+	r.store.mu.RUnlock()
+
+	slices.SortFunc(photos, func(a, b model.Photo) int { return cmp.Compare(a.ID, b.ID) })
+	return firstPhotos(photos, limit)
+}
+
+func (r *MemoryPhotoRepo) ListDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]model.Photo, error) {
+	r.store.mu.RLock()
+	photos := slices.Collect(maps.Values(r.store.photos))
+	r.store.mu.RUnlock()
+
+	photos = slices.DeleteFunc(photos, func(p model.Photo) bool {
+		return p.CheckedAt != nil && !p.CheckedAt.Before(checkedBefore)
+	})
+	// Never checked photos go first
+	slices.SortFunc(photos, func(a, b model.Photo) int {
+		switch {
+		case a.CheckedAt == nil && b.CheckedAt != nil:
+			return -1
+		case a.CheckedAt != nil && b.CheckedAt == nil:
+			return 1
+		case a.CheckedAt != nil && b.CheckedAt != nil:
+			if c := a.CheckedAt.Compare(*b.CheckedAt); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return firstPhotos(photos, limit)
+}
+
+func (r *MemoryPhotoRepo) SetCheckResult(ctx context.Context, photoID int, status, checkError string, checkedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if p, ok := r.store.photos[photoID]; ok {
+		p.Status, p.CheckError, p.CheckedAt = status, checkError, &checkedAt
+		r.store.photos[photoID] = p
+	}
+	return nil
+}
+
+func (r *MemoryPhotoRepo) ListUnhashed(ctx context.Context, limit int) ([]model.Photo, error) {
+	r.store.mu.RLock()
+	photos := slices.Collect(maps.Values(r.store.photos))
+	r.store.mu.RUnlock()
+
+	photos = slices.DeleteFunc(photos, func(p model.Photo) bool {
+		return p.PHash != nil || p.Status != model.PhotoStatusOK
+	})
+	slices.SortFunc(photos, func(a, b model.Photo) int { return cmp.Compare(a.ID, b.ID) })
+	return firstPhotos(photos, limit)
+}
+
+// firstPhotos applies LIMIT to photos, an empty result is an empty slice.
+func firstPhotos(photos []model.Photo, limit int) ([]model.Photo, error) {
+	photos, err := page(photos, limit, 0)
+	if photos == nil && err == nil {
+		photos = []model.Photo{}
+	}
+	return photos, err
+}
+
+func (r *MemoryPhotoRepo) SetHash(ctx context.Context, photoID int, hash int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if p, ok := r.store.photos[photoID]; ok {
+		p.PHash = &hash
+		r.store.photos[photoID] = p
+	}
+	return nil
+}
+
+func (r *MemoryPhotoRepo) ListHashDuplicates(ctx context.Context, limit int) ([]model.DuplicatePhoto, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// Anonymous adverts count as separate owners
+	owners := make(map[int64]map[string]bool)
+	for _, p := range r.store.photos {
+		if p.PHash == nil || *p.PHash == 0 {
+			continue
+		}
+		ad := r.store.adverts[p.AdvertID]
+		owner := ad.OwnerID
+		if owner == "" {
+			owner = fmt.Sprintf("advert:%d", ad.ID)
+		}
+		if owners[*p.PHash] == nil {
+			owners[*p.PHash] = make(map[string]bool)
+		}
+		owners[*p.PHash][owner] = true
+	}
+	var shared []int64
+	for hash, byOwner := range owners {
+		if len(byOwner) > 1 {
+			shared = append(shared, hash)
+		}
+	}
+	slices.Sort(shared)
+	shared, err := page(shared, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	photos := []model.DuplicatePhoto{}
+	for _, p := range r.store.photos {
+		if p.PHash == nil || !slices.Contains(shared, *p.PHash) {
+			continue
+		}
+		ad := r.store.adverts[p.AdvertID]
+		photos = append(photos, model.DuplicatePhoto{
+			PHash:      *p.PHash,
+			PhotoID:    p.ID,
+			URL:        p.URL,
+			AdvertID:   ad.ID,
+			OwnerID:    ad.OwnerID,
+			FlagReason: ad.FlagReason,
+		})
+	}
+	slices.SortFunc(photos, func(a, b model.DuplicatePhoto) int {
+		return cmp.Or(cmp.Compare(a.PHash, b.PHash), cmp.Compare(a.AdvertID, b.AdvertID), cmp.Compare(a.PhotoID, b.PhotoID))
+	})
+	return photos, nil
+}
+
+func (r *MemoryPhotoRepo) HashUsedByOtherOwner(ctx context.Context, hash int64, ownerID string, advertID int) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, p := range r.store.photos {
+		if p.PHash == nil || *p.PHash != hash || p.AdvertID == advertID {
+			continue
+		}
+		if owner := r.store.adverts[p.AdvertID].OwnerID; ownerID == "" || owner == "" || owner != ownerID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPhotoRepo returns a photo repo with one advert and photos a, b, c at positions 1..3.
+func newPhotoRepo(t *testing.T) (*MemoryPhotoRepo, int) {
+	t.Helper()
+	ctx := context.Background()
+	store := NewStore()
+	id, err := NewMemoryAdvertRepo(store).Create(ctx, model.Advert{Name: "Advert", Price: 1})
+	require.NoError(t, err)
+	repo := NewMemoryPhotoRepo(store).(*MemoryPhotoRepo)
+	for i, url := range []string{"a", "b", "c"} {
+		require.NoError(t, repo.Create(ctx, model.Photo{AdvertID: id, URL: url, Position: i + 1}))
+	}
+	return repo, id
+}
+
+func photoURLs(t *testing.T, repo *MemoryPhotoRepo, advertID int) []string {
+	t.Helper()
+	urls, err := repo.GetAllPhotoURLs(context.Background(), advertID)
+	require.NoError(t, err)
+	return urls
+}
+
+func TestMemoryPhotoRepo_Insert(t *testing.T) {
+	ctx := context.Background()
+	repo, id := newPhotoRepo(t)
+
+	photo, err := repo.Insert(ctx, model.Photo{AdvertID: id, URL: "first", Position: 1}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, photo.Position)
+	assert.Equal(t, model.PhotoStatusUnchecked, photo.Status)
+
+	// Positions past the end append the photo
+	photo, err = repo.Insert(ctx, model.Photo{AdvertID: id, URL: "last", Position: 42}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 5, photo.Position)
+	assert.Equal(t, []string{"first", "a", "b", "c", "last"}, photoURLs(t, repo, id))
+
+	_, err = repo.Insert(ctx, model.Photo{AdvertID: 99, URL: "x"}, 10)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestMemoryPhotoRepo_Delete(t *testing.T) {
+	ctx := context.Background()
+	repo, id := newPhotoRepo(t)
+
+	require.NoError(t, repo.Delete(ctx, id, 2))
+	photos, err := repo.ListByAdvertID(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, photos, 2)
+	assert.Equal(t, []int{1, 2}, []int{photos[0].Position, photos[1].Position})
+	assert.Equal(t, []string{"a", "c"}, photoURLs(t, repo, id))
+
+	assert.ErrorIs(t, repo.Delete(ctx, id, 2), sql.ErrNoRows)
+	assert.ErrorIs(t, repo.Delete(ctx, 99, 1), sql.ErrNoRows)
+}
+
+func TestMemoryPhotoRepo_Reorder(t *testing.T) {
+	ctx := context.Background()
+	repo, id := newPhotoRepo(t)
+
+	require.NoError(t, repo.Reorder(ctx, id, []int{3, 1, 2}))
+	assert.Equal(t, []string{"c", "a", "b"}, photoURLs(t, repo, id))
+
+	for name, ids := range map[string][]int{
+		"Missing":   {3, 1},
+		"Duplicate": {3, 3, 1},
+		"Unknown":   {3, 1, 7},
+	} {
+		assert.Error(t, repo.Reorder(ctx, id, ids), name)
+	}
+	assert.ErrorIs(t, repo.Reorder(ctx, 99, nil), sql.ErrNoRows)
+	assert.Equal(t, []string{"c", "a", "b"}, photoURLs(t, repo, id))
+}
+
+func TestMemoryPhotoRepo_Outbox(t *testing.T) {
+	ctx := context.Background()
+	repo, id := newPhotoRepo(t)
+	store := repo.store
+	// The advert itself was created with an event
+	created := len(store.outbox)
+
+	_, err := repo.Insert(ctx, model.Photo{AdvertID: id, URL: "d"}, 4)
+	require.NoError(t, err)
+	require.NoError(t, repo.Reorder(ctx, id, []int{4, 1, 2, 3}))
+	require.NoError(t, repo.Delete(ctx, id, 1))
+	// Rejected changes record nothing
+	_, err = repo.Insert(ctx, model.Photo{AdvertID: id, URL: "e"}, 3)
+	assert.ErrorIs(t, err, repository.ErrPhotoCount)
+
+	require.Len(t, store.outbox, created+3)
+	last := store.outbox[store.outboxID]
+	assert.Equal(t, model.EventAdvertUpdated, last.Event)
+	assert.Equal(t, id, last.AggregateID)
+	assert.Contains(t, last.Payload, `"photo_urls":["d","b","c"]`)
+}
+
+func TestMemoryPhotoRepo_MainPhoto(t *testing.T) {
+	ctx := context.Background()
+	repo, id := newPhotoRepo(t)
+	require.NoError(t, repo.SaveVariant(ctx, model.PhotoVariant{PhotoID: 2, Name: "thumb", URL: "b-thumb"}))
+
+	url, err := repo.GetMainPhotoURL(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "a", url)
+
+	// Broken photos are skipped
+	require.NoError(t, repo.SetCheckResult(ctx, 1, model.PhotoStatusBroken, "404", time.Now()))
+	url, err = repo.GetMainPhotoURL(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "b", url)
+	url, err = repo.GetMainPhotoVariantURL(ctx, id, "thumb")
+	require.NoError(t, err)
+	assert.Equal(t, "b-thumb", url)
+
+	_, err = repo.GetMainPhotoURL(ctx, 99)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	url, err = repo.GetMainPhotoVariantURL(ctx, 99, "thumb")
+	require.NoError(t, err)
+	assert.Empty(t, url)
+}
+
+func TestMemoryPhotoRepo_ListDueForCheck(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newPhotoRepo(t)
+	now := time.Now()
+	require.NoError(t, repo.SetCheckResult(ctx, 1, model.PhotoStatusOK, "", now.Add(-2*time.Hour)))
+	require.NoError(t, repo.SetCheckResult(ctx, 2, model.PhotoStatusOK, "", now))
+
+	photos, err := repo.ListDueForCheck(ctx, now.Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, photos, 2)
+	// Never checked photos go first
+	assert.Equal(t, []int{3, 1}, []int{photos[0].ID, photos[1].ID})
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+type MemorySavedSearchRepo struct {
+	store *Store
+}
+
+func NewMemorySavedSearchRepo(store *Store) repository.SavedSearchRepo {
+	return &MemorySavedSearchRepo{store: store}
+}
+
+func (r *MemorySavedSearchRepo) Create(ctx context.Context, s model.SavedSearch) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.savedSearchID++
+	s.ID = r.store.savedSearchID
+	s.MinPrice = roundPricePtr(s.MinPrice)
+	s.MaxPrice = roundPricePtr(s.MaxPrice)
+	r.store.savedSearches[s.ID] = s
+	return s.ID, nil
+}
+
+func roundPricePtr(price *float64) *float64 {
+	if price == nil {
+		return nil
+	}
+	rounded := roundPrice(*price)
+	return &rounded
+}
+
+func (r *MemorySavedSearchRepo) ListByUser(ctx context.Context, userID string) ([]model.SavedSearch, error) {
+	searches := r.list(func(s model.SavedSearch) bool { return s.UserID == userID })
+	slices.Reverse(searches)
+	return searches, nil
+}
+
+func (r *MemorySavedSearchRepo) GetByID(ctx context.Context, userID string, id int) (model.SavedSearch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s, ok := r.store.savedSearches[id]
+	if !ok || s.UserID != userID {
+		return model.SavedSearch{}, sql.ErrNoRows
+	}
+	return s, nil
+}
+
+func (r *MemorySavedSearchRepo) Delete(ctx context.Context, userID string, id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if s, ok := r.store.savedSearches[id]; !ok || s.UserID != userID {
+		return sql.ErrNoRows
+	}
+	delete(r.store.savedSearches, id)
+	return nil
+}
+
+func (r *MemorySavedSearchRepo) ListMatching(ctx context.Context, ad model.Advert) ([]model.SavedSearch, error) {
+	return r.list(func(s model.SavedSearch) bool {
+		return (ad.OwnerID == "" || s.UserID != ad.OwnerID) && matchesFilter(s.Filter(), ad)
+	}), nil
+}
+
+// list returns the saved searches passing keep ordered by ID.
+func (r *MemorySavedSearchRepo) list(keep func(s model.SavedSearch) bool) []model.SavedSearch {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	searches := []model.SavedSearch{}
+	for _, s := range r.store.savedSearches {
+		if keep(s) {
+			searches = append(searches, s)
+		}
+	}
+	slices.SortFunc(searches, func(a, b model.SavedSearch) int { return cmp.Compare(a.ID, b.ID) })
+	return searches
+}
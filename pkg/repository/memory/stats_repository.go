@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+type MemoryStatsRepo struct {
+	store *Store
+}
+
+func NewMemoryStatsRepo(store *Store) repository.StatsRepo {
+	return &MemoryStatsRepo{store: store}
+}
+
+// filtered returns the adverts passing the filter.
+func (r *MemoryStatsRepo) filtered(filter model.AdvertFilter) []model.Advert {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var ads []model.Advert
+	for _, ad := range r.store.adverts {
+		if matchesFilter(filter, ad) {
+			ads = append(ads, ad)
+		}
+	}
+	return ads
+}
+
+// PriceStats computes the median like PERCENTILE_CONT(0.5), interpolating
+// between the two middle prices of an even count.
+func (r *MemoryStatsRepo) PriceStats(ctx context.Context, filter model.AdvertFilter) (model.PriceStats, error) {
+	ads := r.filtered(filter)
+	stats := model.PriceStats{Count: int64(len(ads))}
+	if len(ads) == 0 {
+		return stats, nil
+	}
+
+	prices := make([]float64, len(ads))
+	sum := 0.0
+	for i, ad := range ads {
+		prices[i] = ad.Price
+		sum += ad.Price
+	}
+	slices.Sort(prices)
+	lo, hi := prices[0], prices[len(prices)-1]
+	avg := sum / float64(len(prices))
+	median := prices[len(prices)/2]
+	if len(prices)%2 == 0 {
+		median = (prices[len(prices)/2-1] + median) / 2
+	}
+	stats.Min, stats.Max, stats.Avg, stats.Median = &lo, &hi, &avg, &median
+	return stats, nil
+}
+
+// PriceHistogram counts like WIDTH_BUCKET: 0 below the first bound and i for [bounds[i-1], bounds[i]).
+func (r *MemoryStatsRepo) PriceHistogram(ctx context.Context, filter model.AdvertFilter, bounds []float64) ([]int64, error) {
+	counts := make([]int64, len(bounds)+1)
+	for _, ad := range r.filtered(filter) {
+		counts[sort.Search(len(bounds), func(i int) bool { return bounds[i] > ad.Price })]++
+	}
+	return counts, nil
+}
+
+func (r *MemoryStatsRepo) CreatedPerDay(ctx context.Context, filter model.AdvertFilter) ([]model.DayCount, error) {
+	perDay := make(map[time.Time]int64)
+	for _, ad := range r.filtered(filter) {
+		y, m, d := ad.CreatedAt.Date()
+		perDay[time.Date(y, m, d, 0, 0, 0, 0, ad.CreatedAt.Location())]++
+	}
+
+	days := []model.DayCount{}
+	for day, n := range perDay {
+		days = append(days, model.DayCount{Day: day, Count: n})
+	}
+	slices.SortFunc(days, func(a, b model.DayCount) int { return a.Day.Compare(b.Day) })
+	return days, nil
+}
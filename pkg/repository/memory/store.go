@@ -0,0 +1,179 @@
+// Package memory implements the repositories on in-process maps, with the same
+// semantics as the postgres ones. Nothing survives a restart: it is meant for
+// development, demos and tests.
+package memory
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// Store holds the data of all memory repositories. A single lock guards it,
+// so every repository call is atomic, like a transaction of the postgres ones.
+type Store struct {
+	mu sync.RWMutex
+
+	adverts      map[int]model.Advert
+	translations map[int][]model.AdvertTranslation
+	photos       map[int]model.Photo
+	// variants are keyed by photo ID and variant name
+	variants      map[int]map[string]model.PhotoVariant
+	favorites     map[favoriteKey]model.Favorite
+	savedSearches map[int]model.SavedSearch
+	subscriptions map[int]model.WebhookSubscription
+	deliveries    map[int64]model.WebhookDelivery
+	outbox        map[int64]model.OutboxEvent
+
+	// Last issued IDs, the memory counterpart of the SERIAL columns
+	advertID, photoID, savedSearchID, subscriptionID int
+	deliveryID, outboxID                             int64
+
+	// relayMu serializes outbox relays, which publish without holding mu
+	relayMu sync.Mutex
+}
+
+type favoriteKey struct {
+	userID   string
+	advertID int
+}
+
+// NewStore returns an empty store to be shared by the memory repositories.
+func NewStore() *Store {
+	return &Store{
+		adverts:       make(map[int]model.Advert),
+		translations:  make(map[int][]model.AdvertTranslation),
+		photos:        make(map[int]model.Photo),
+		variants:      make(map[int]map[string]model.PhotoVariant),
+		favorites:     make(map[favoriteKey]model.Favorite),
+		savedSearches: make(map[int]model.SavedSearch),
+		subscriptions: make(map[int]model.WebhookSubscription),
+		deliveries:    make(map[int64]model.WebhookDelivery),
+		outbox:        make(map[int64]model.OutboxEvent),
+	}
+}
+
+// roundPrice keeps two decimal places, like the NUMERIC(12, 2) price columns.
+func roundPrice(price float64) float64 {
+	return math.Round(price*100) / 100
+}
+
+// outboxEvent builds an advert event for the outbox; the caller stores it
+// together with the change.
+func outboxEvent(event string, advertID int, payload interface{}) (model.OutboxEvent, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+	now := time.Now()
+	return model.OutboxEvent{
+		AggregateID:   advertID,
+		Event:         event,
+		Payload:       string(body),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// addOutbox stores e under the next outbox ID; the caller holds mu.
+func (s *Store) addOutbox(e model.OutboxEvent) {
+	s.outboxID++
+	e.ID = s.outboxID
+	s.outbox[e.ID] = e
+}
+
+// matchesFilter reports whether the advert passes the filter, see model.AdvertFilter.
+func matchesFilter(filter model.AdvertFilter, ad model.Advert) bool {
+	return (filter.MinPrice == nil || ad.Price >= *filter.MinPrice) &&
+		(filter.MaxPrice == nil || ad.Price <= *filter.MaxPrice) &&
+		(filter.CreatedFrom == nil || !ad.CreatedAt.Before(*filter.CreatedFrom)) &&
+		(filter.CreatedTo == nil || ad.CreatedAt.Before(*filter.CreatedTo))
+}
+
+// advertOrder returns the comparison of adverts by one of the sort fields of
+// the service (id, price, created_at, view_count) in sortOrder (ASC or DESC).
+// Ties are broken by ID, so pages never overlap.
+func advertOrder(sortField, sortOrder string) (func(a, b model.Advert) int, error) {
+	var compare func(a, b model.Advert) int
+	switch sortField {
+	case "id":
+		compare = func(a, b model.Advert) int { return cmp.Compare(a.ID, b.ID) }
+	case "price":
+		compare = func(a, b model.Advert) int { return cmp.Compare(a.Price, b.Price) }
+	case "created_at":
+		compare = func(a, b model.Advert) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	case "view_count":
+		compare = func(a, b model.Advert) int { return cmp.Compare(a.ViewCount, b.ViewCount) }
+	default:
+		return nil, fmt.Errorf("unsupported advert sort field %q", sortField)
+	}
+	switch sortOrder {
+	case "ASC":
+	case "DESC":
+		asc := compare
+		compare = func(a, b model.Advert) int { return -asc(a, b) }
+	default:
+		return nil, fmt.Errorf("unsupported advert sort order %q", sortOrder)
+	}
+	return func(a, b model.Advert) int {
+		if c := compare(a, b); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	}, nil
+}
+
+// page applies LIMIT and OFFSET to items.
+func page[T any](items []T, limit, offset int) ([]T, error) {
+	if limit < 0 || offset < 0 {
+		return nil, fmt.Errorf("negative limit %d or offset %d", limit, offset)
+	}
+	if offset >= len(items) {
+		return nil, nil
+	}
+	return items[offset:min(offset+limit, len(items))], nil
+}
+
+// advertPhotos returns the photos of the advert ordered by position; the caller holds mu.
+func (s *Store) advertPhotos(advertID int) []model.Photo {
+	return s.groupPhotos(func(p model.Photo) bool { return p.AdvertID == advertID })[advertID]
+}
+
+// groupPhotos returns the photos passing keep by advert ID, ordered by position;
+// the caller holds mu.
+func (s *Store) groupPhotos(keep func(p model.Photo) bool) map[int][]model.Photo {
+	byAdvert := make(map[int][]model.Photo)
+	for _, p := range s.photos {
+		if keep(p) {
+			byAdvert[p.AdvertID] = append(byAdvert[p.AdvertID], p)
+		}
+	}
+	for _, photos := range byAdvert {
+		slices.SortFunc(photos, func(a, b model.Photo) int {
+			return cmp.Or(cmp.Compare(a.Position, b.Position), cmp.Compare(a.ID, b.ID))
+		})
+	}
+	return byAdvert
+}
+
+// mainPhoto returns the first photo by position that is not broken; the caller holds mu.
+func (s *Store) mainPhoto(advertID int) (model.Photo, bool) {
+	for _, p := range s.advertPhotos(advertID) {
+		if p.Status != model.PhotoStatusBroken {
+			return p, true
+		}
+	}
+	return model.Photo{}, false
+}
+
+// deletePhoto removes the photo and its variants; the caller holds mu.
+func (s *Store) deletePhoto(id int) {
+	delete(s.photos, id)
+	delete(s.variants, id)
+}
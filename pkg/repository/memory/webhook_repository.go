@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+type MemoryWebhookRepo struct {
+	store *Store
+}
+
+func NewMemoryWebhookRepo(store *Store) repository.WebhookRepo {
+	return &MemoryWebhookRepo{store: store}
+}
+
+func (r *MemoryWebhookRepo) CreateSubscription(ctx context.Context, sub model.WebhookSubscription) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.subscriptionID++
+	sub.ID = r.store.subscriptionID
+	sub.Events = slices.Clone(sub.Events)
+	r.store.subscriptions[sub.ID] = sub
+	return sub.ID, nil
+}
+
+// ListSubscriptions leaves out the secrets, like the postgres repo.
+func (r *MemoryWebhookRepo) ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subs := []model.WebhookSubscription{}
+	for _, sub := range r.store.subscriptions {
+		sub.Secret = ""
+		sub.Events = slices.Clone(sub.Events)
+		subs = append(subs, sub)
+	}
+	slices.SortFunc(subs, func(a, b model.WebhookSubscription) int { return cmp.Compare(a.ID, b.ID) })
+	return subs, nil
+}
+
+// DeleteSubscription also removes its deliveries.
+func (r *MemoryWebhookRepo) DeleteSubscription(ctx context.Context, id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.subscriptions[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.subscriptions, id)
+	for deliveryID, d := range r.store.deliveries {
+		if d.SubscriptionID == id {
+			delete(r.store.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryWebhookRepo) EnqueueEvent(ctx context.Context, event, payload string) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	ids := make([]int, 0, len(r.store.subscriptions))
+	for id, sub := range r.store.subscriptions {
+		if slices.Contains(sub.Events, event) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	now := time.Now()
+	for _, id := range ids {
+		r.store.deliveryID++
+		r.store.deliveries[r.store.deliveryID] = model.WebhookDelivery{
+			ID:             r.store.deliveryID,
+			SubscriptionID: id,
+			Event:          event,
+			Payload:        payload,
+			Status:         model.DeliveryStatusPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		}
+	}
+	return len(ids), nil
+}
+
+func (r *MemoryWebhookRepo) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]model.WebhookDelivery, map[int]model.WebhookSubscription, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	deliveries := []model.WebhookDelivery{}
+	for _, d := range r.store.deliveries {
+		if d.Status == model.DeliveryStatusPending && !d.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, d)
+		}
+	}
+	slices.SortFunc(deliveries, func(a, b model.WebhookDelivery) int {
+		return cmp.Or(a.NextAttemptAt.Compare(b.NextAttemptAt), cmp.Compare(a.ID, b.ID))
+	})
+	deliveries, err := page(deliveries, limit, 0)
+	if err != nil || len(deliveries) == 0 {
+		return []model.WebhookDelivery{}, nil, err
+	}
+
+	// Claimed deliveries are not due again until the lease expires
+	subs := make(map[int]model.WebhookSubscription)
+	for i, d := range deliveries {
+		d.NextAttemptAt = now.Add(lease)
+		r.store.deliveries[d.ID] = d
+		deliveries[i] = d
+		if sub, ok := r.store.subscriptions[d.SubscriptionID]; ok {
+			sub.Events = slices.Clone(sub.Events)
+			subs[sub.ID] = sub
+		}
+	}
+	return deliveries, subs, nil
+}
+
+func (r *MemoryWebhookRepo) UpdateDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.deliveries[d.ID]
+	if !ok {
+		return nil
+	}
+	stored.Status = d.Status
+	stored.Attempts = d.Attempts
+	stored.NextAttemptAt = d.NextAttemptAt
+	stored.LastError = d.LastError
+	stored.ResponseCode = d.ResponseCode
+	stored.DeliveredAt = d.DeliveredAt
+	r.store.deliveries[d.ID] = stored
+	return nil
+}
+
+func (r *MemoryWebhookRepo) ListDeliveries(ctx context.Context, subscriptionID int, status string, limit int) ([]model.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	deliveries := []model.WebhookDelivery{}
+	for _, d := range r.store.deliveries {
+		if d.SubscriptionID == subscriptionID && (status == "" || d.Status == status) {
+			deliveries = append(deliveries, d)
+		}
+	}
+	r.store.mu.RUnlock()
+
+	slices.SortFunc(deliveries, func(a, b model.WebhookDelivery) int { return cmp.Compare(b.ID, a.ID) })
+	deliveries, err := page(deliveries, limit, 0)
+	if deliveries == nil && err == nil {
+		deliveries = []model.WebhookDelivery{}
+	}
+	return deliveries, err
+}
+
+func (r *MemoryWebhookRepo) RetryDelivery(ctx context.Context, subscriptionID int, deliveryID int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	d, ok := r.store.deliveries[deliveryID]
+	if !ok || d.SubscriptionID != subscriptionID || d.Status == model.DeliveryStatusDelivered {
+		return sql.ErrNoRows
+	}
+	d.Status = model.DeliveryStatusPending
+	d.Attempts = 0
+	d.NextAttemptAt = time.Now()
+	r.store.deliveries[deliveryID] = d
+	return nil
+}
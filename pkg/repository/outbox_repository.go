@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// OutboxRepo reads the outbox. Events are written by AdvertRepo in the
+// same transaction as the advert change.
+type OutboxRepo interface {
+	// Relay claims up to limit due events that are the oldest unpublished event of their
+	// advert, then calls fn for each of them in ID order and saves the event fn returns
+	// (published_at, attempts, next attempt, error). fn runs outside of any transaction,
+	// so a slow publisher holds no locks. It returns how many events were passed to fn.
+	Relay(ctx context.Context, now time.Time, limit int, fn func(e model.OutboxEvent) model.OutboxEvent) (int, error)
+	// Lag returns the number and the age of unpublished events
+	Lag(ctx context.Context) (model.OutboxLag, error)
+	// DeletePublished removes events published before the given time
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+}
@@ -2,12 +2,66 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
+
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
 )
 
+// ErrPhotoCount is returned when a photo change would leave the advert
+// without photos or with more photos than allowed.
+var ErrPhotoCount = errors.New("photo count out of range")
+
 type PhotoRepo interface {
+	// GetMainPhotoURL returns the URL of the first photo by position that is not broken
 	GetMainPhotoURL(ctx context.Context, advertID int) (string, error)
 	GetAllPhotoURLs(ctx context.Context, advertID int) ([]string, error)
 	Create(ctx context.Context, photo model.Photo) error
 	DeleteByAdvertID(ctx context.Context, advertID int) error
+
+	// ListByAdvertID returns all photos of the advert ordered by position, with their variants
+	ListByAdvertID(ctx context.Context, advertID int) ([]model.Photo, error)
+	// ListByAdvertIDs returns the photos of several adverts at once, keyed by advert ID;
+	// adverts without photos are missing from the map
+	ListByAdvertIDs(ctx context.Context, advertIDs []int) (map[int][]model.Photo, error)
+	// Insert, Delete and Reorder record an advert.updated event with the resulting
+	// photos in the outbox, in the same transaction as the change.
+
+	// Insert puts the photo at photo.Position, shifting the following photos down.
+	// Positions outside 1..n+1 append the photo to the end. Returns the stored photo,
+	// or ErrPhotoCount if the advert already has maxPhotos photos.
+	Insert(ctx context.Context, photo model.Photo, maxPhotos int) (model.Photo, error)
+	// Delete removes a single photo and compacts the remaining positions to 1..n.
+	// Returns sql.ErrNoRows if the advert has no such photo and ErrPhotoCount
+	// if it is the last one.
+	Delete(ctx context.Context, advertID, photoID int) error
+	// Reorder assigns positions 1..n following photoIDs, which must list
+	// every photo of the advert exactly once
+	Reorder(ctx context.Context, advertID int, photoIDs []int) error
+
+	// SaveVariant creates or replaces a resized variant of a photo
+	SaveVariant(ctx context.Context, variant model.PhotoVariant) error
+	// GetMainPhotoVariantURL returns the URL of the named variant of the main photo,
+	// or "" if the advert has no photos or the variant is not generated (yet)
+	GetMainPhotoVariantURL(ctx context.Context, advertID int, name string) (string, error)
+	// ListMissingVariants returns up to limit photos with an ID above afterID whose URL
+	// starts with urlPrefix and that lack any of the named variants, ordered by ID
+	ListMissingVariants(ctx context.Context, urlPrefix string, names []string, afterID, limit int) ([]model.Photo, error)
+
+	// ListDueForCheck returns up to limit photos never verified or last verified before checkedBefore
+	ListDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]model.Photo, error)
+	// SetCheckResult records the outcome of a URL verification
+	SetCheckResult(ctx context.Context, photoID int, status, checkError string, checkedAt time.Time) error
+
+	// ListUnhashed returns up to limit verified photos without a perceptual hash
+	ListUnhashed(ctx context.Context, limit int) ([]model.Photo, error)
+	// SetHash stores the perceptual hash of a photo (0 = could not be computed)
+	SetHash(ctx context.Context, photoID int, hash int64) error
+	// ListHashDuplicates returns photos whose non-zero hash is shared by adverts of
+	// at least two different owners (anonymous adverts count as separate owners),
+	// ordered by hash; limit bounds the number of distinct hashes
+	ListHashDuplicates(ctx context.Context, limit int) ([]model.DuplicatePhoto, error)
+	// HashUsedByOtherOwner reports whether a photo with the hash belongs to an advert
+	// other than advertID of an owner other than ownerID
+	HashUsedByOtherOwner(ctx context.Context, hash int64, ownerID string, advertID int) (bool, error)
 }
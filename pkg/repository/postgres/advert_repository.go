@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type AdvertRepo struct {
@@ -17,25 +19,46 @@ func NewPostgresAdvertRepo(db *sqlx.DB) repository.AdvertRepo {
 }
 
 func (r *AdvertRepo) Create(ctx context.Context, ad model.Advert) (int, error) {
-	var id int
-	err := r.db.QueryRowContext(
-		ctx,
-		`INSERT INTO adverts (name, description, price, created_at)
-         VALUES ($1, $2, $3, $4)
+	err := repository.InTx(ctx, r.db, nil, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO adverts (name, description, price, created_at, owner_id, default_locale)
+         VALUES ($1, $2, $3, $4, $5, $6)
          RETURNING id`,
-		ad.Name, ad.Description, ad.Price, ad.CreatedAt,
-	).Scan(&id)
-	return id, err
+			ad.Name, ad.Description, ad.Price, ad.CreatedAt, ad.OwnerID, ad.DefaultLocale,
+		).Scan(&ad.ID)
+		if err != nil {
+			return err
+		}
+		if err := insertTranslations(ctx, tx, ad.ID, ad.Translations); err != nil {
+			return err
+		}
+		if err := insertPhotos(ctx, tx, ad.ID, ad.PhotoURLs); err != nil {
+			return err
+		}
+		return insertOutbox(ctx, tx, model.EventAdvertCreated, ad.ID, ad)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ad.ID, nil
 }
 
-func (r *AdvertRepo) List(ctx context.Context, limit, offset int, sortField, sortOrder string) ([]model.Advert, error) {
+func (r *AdvertRepo) List(
+	ctx context.Context,
+	filter model.AdvertFilter,
+	limit, offset int,
+	sortField, sortOrder string,
+) ([]model.Advert, error) {
 	var ads []model.Advert
+	where, args := filterClause(filter, "", 3)
 	query := fmt.Sprintf(`
-        SELECT id, name, description, price, created_at
+        SELECT id, name, description, price, created_at, owner_id, flag_reason, view_count, default_locale
           FROM adverts
+         %s
          ORDER BY %s %s
-         LIMIT $1 OFFSET $2`, sortField, sortOrder)
-	if err := r.db.SelectContext(ctx, &ads, query, limit, offset); err != nil {
+         LIMIT $1 OFFSET $2`, where, sortField, sortOrder)
+	if err := r.db.SelectContext(ctx, &ads, query, append([]interface{}{limit, offset}, args...)...); err != nil {
 		return nil, err
 	}
 	return ads, nil
@@ -44,26 +67,222 @@ func (r *AdvertRepo) List(ctx context.Context, limit, offset int, sortField, sor
 func (r *AdvertRepo) GetByID(ctx context.Context, id int) (model.Advert, error) {
 	var ad model.Advert
 	err := r.db.GetContext(ctx, &ad, `
-        SELECT id, name, description, price, created_at
+        SELECT id, name, description, price, created_at, owner_id, flag_reason, view_count, default_locale
           FROM adverts
          WHERE id = $1`, id)
 	return ad, err
 }
 
+func (r *AdvertRepo) ListByIDs(ctx context.Context, ids []int) ([]model.Advert, error) {
+	list := make(pq.Int64Array, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, int64(id))
+	}
+	ads := []model.Advert{}
+	err := r.db.SelectContext(ctx, &ads, `
+        SELECT id, name, description, price, created_at, owner_id, flag_reason, view_count, default_locale
+          FROM adverts
+         WHERE id = ANY($1)
+      ORDER BY id`, list)
+	return ads, err
+}
+
 func (r *AdvertRepo) Update(ctx context.Context, ad model.Advert) error {
-	_, err := r.db.ExecContext(
-		ctx,
-		`UPDATE adverts
+	return repository.InTx(ctx, r.db, nil, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(
+			ctx,
+			`UPDATE adverts
             SET name = $1,
                 description = $2,
-                price = $3
-          WHERE id = $4`,
-		ad.Name, ad.Description, ad.Price, ad.ID,
-	)
+                price = $3,
+                default_locale = $4
+          WHERE id = $5`,
+			ad.Name, ad.Description, ad.Price, ad.DefaultLocale, ad.ID,
+		)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return sql.ErrNoRows
+		}
+		if ad.Translations != nil {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM advert_translations WHERE advert_id = $1`, ad.ID); err != nil {
+				return err
+			}
+			if err := insertTranslations(ctx, tx, ad.ID, ad.Translations); err != nil {
+				return err
+			}
+		}
+		if ad.PhotoURLs != nil {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM photos WHERE advert_id = $1`, ad.ID); err != nil {
+				return err
+			}
+			if err := insertPhotos(ctx, tx, ad.ID, ad.PhotoURLs); err != nil {
+				return err
+			}
+		}
+		return insertOutbox(ctx, tx, model.EventAdvertUpdated, ad.ID, ad)
+	})
+}
+
+func (r *AdvertRepo) ListTranslations(ctx context.Context, advertIDs []int) (map[int][]model.AdvertTranslation, error) {
+	ids := make(pq.Int64Array, 0, len(advertIDs))
+	for _, id := range advertIDs {
+		ids = append(ids, int64(id))
+	}
+
+	var translations []model.AdvertTranslation
+	err := r.db.SelectContext(ctx, &translations, `
+        SELECT advert_id, locale, name, description
+          FROM advert_translations
+         WHERE advert_id = ANY($1)
+      ORDER BY advert_id, locale`, ids)
+	if err != nil {
+		return nil, err
+	}
+	byAdvert := make(map[int][]model.AdvertTranslation)
+	for _, t := range translations {
+		byAdvert[t.AdvertID] = append(byAdvert[t.AdvertID], t)
+	}
+	return byAdvert, nil
+}
+
+// insertTranslations stores the texts of an advert in a single statement.
+func insertTranslations(ctx context.Context, tx *sqlx.Tx, advertID int, translations []model.AdvertTranslation) error {
+	if len(translations) == 0 {
+		return nil
+	}
+	locales := make(pq.StringArray, 0, len(translations))
+	names := make(pq.StringArray, 0, len(translations))
+	descriptions := make(pq.StringArray, 0, len(translations))
+	for _, t := range translations {
+		locales = append(locales, t.Locale)
+		names = append(names, t.Name)
+		descriptions = append(descriptions, t.Description)
+	}
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO advert_translations (advert_id, locale, name, description)
+        SELECT $1, t.locale, t.name, t.description
+          FROM UNNEST($2::text[], $3::text[], $4::text[]) AS t(locale, name, description)`,
+		advertID, locales, names, descriptions)
+	return err
+}
+
+// insertPhotos stores the photo URLs of an advert at positions 1, 2, ... in a single statement.
+func insertPhotos(ctx context.Context, tx *sqlx.Tx, advertID int, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO photos (advert_id, url, position)
+        SELECT $1, t.url, t.position
+          FROM UNNEST($2::text[]) WITH ORDINALITY AS t(url, position)`,
+		advertID, pq.StringArray(urls))
 	return err
 }
 
 func (r *AdvertRepo) Delete(ctx context.Context, id int) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM adverts WHERE id = $1`, id)
+	return repository.InTx(ctx, r.db, nil, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM adverts WHERE id = $1`, id)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return sql.ErrNoRows
+		}
+		return insertOutbox(ctx, tx, model.EventAdvertDeleted, id, map[string]int{"id": id})
+	})
+}
+
+func (r *AdvertRepo) SetFlag(ctx context.Context, id int, reason string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE adverts SET flag_reason = $1 WHERE id = $2`, reason, id)
 	return err
 }
+
+// AddViews applies all counters in a single statement.
+func (r *AdvertRepo) AddViews(ctx context.Context, views map[int]int64) error {
+	if len(views) == 0 {
+		return nil
+	}
+	ids := make(pq.Int64Array, 0, len(views))
+	counts := make(pq.Int64Array, 0, len(views))
+	for id, n := range views {
+		ids = append(ids, int64(id))
+		counts = append(counts, n)
+	}
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE adverts a
+           SET view_count = a.view_count + v.n
+          FROM UNNEST($1::bigint[], $2::bigint[]) AS v(id, n)
+         WHERE a.id = v.id`, ids, counts)
+	return err
+}
+
+// Stream uses a server-side cursor inside a read-only transaction,
+// so only batchSize rows are held in memory at any time.
+func (r *AdvertRepo) Stream(
+	ctx context.Context,
+	filter model.AdvertFilter,
+	sortField, sortOrder string,
+	batchSize int,
+	fn func(ad model.Advert, photoURLs []string) error,
+) error {
+	return repository.InTx(ctx, r.db, &sql.TxOptions{ReadOnly: true}, func(tx *sqlx.Tx) error {
+		where, args := filterClause(filter, "a", 1)
+		declare := fmt.Sprintf(`
+        DECLARE advert_export NO SCROLL CURSOR FOR
+        SELECT a.id, a.name, a.description, a.price, a.created_at, a.owner_id, a.flag_reason, a.view_count, a.default_locale,
+               ARRAY(SELECT p.url
+                       FROM photos p
+                      WHERE p.advert_id = a.id
+                   ORDER BY p.position) AS photo_urls
+          FROM adverts a
+         %s
+         ORDER BY a.%s %s`, where, sortField, sortOrder)
+		if _, err := tx.ExecContext(ctx, declare, args...); err != nil {
+			return fmt.Errorf("failed to declare export cursor: %w", err)
+		}
+
+		fetch := fmt.Sprintf(`FETCH FORWARD %d FROM advert_export`, batchSize)
+		for {
+			rows, err := tx.QueryxContext(ctx, fetch)
+			if err != nil {
+				return fmt.Errorf("failed to fetch from export cursor: %w", err)
+			}
+			fetched := 0
+			for rows.Next() {
+				var row struct {
+					model.Advert
+					PhotoURLs pq.StringArray `db:"photo_urls"`
+				}
+				if err := rows.StructScan(&row); err != nil {
+					rows.Close()
+					return err
+				}
+				fetched++
+				if err := fn(row.Advert, row.PhotoURLs); err != nil {
+					rows.Close()
+					return err
+				}
+			}
+			if err := rows.Close(); err != nil {
+				return err
+			}
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			if fetched < batchSize {
+				break
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `CLOSE advert_export`); err != nil {
+			return err
+		}
+		return nil
+	})
+}
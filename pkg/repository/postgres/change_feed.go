@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// changeFeedChannel is the LISTEN/NOTIFY channel of advert changes
+const changeFeedChannel = "advert_changes"
+
+// PostgresChangeFeed implements repository.ChangeFeed with LISTEN/NOTIFY.
+// Notifications are limited to 8000 bytes by Postgres.
+type PostgresChangeFeed struct {
+	db  *sqlx.DB
+	dsn string
+}
+
+// NewPostgresChangeFeed publishes through db; Listen opens its own
+// connection to dsn because LISTEN needs a dedicated session.
+func NewPostgresChangeFeed(db *sqlx.DB, dsn string) repository.ChangeFeed {
+	return &PostgresChangeFeed{db: db, dsn: dsn}
+}
+
+func (f *PostgresChangeFeed) Publish(ctx context.Context, msg string) error {
+	_, err := f.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, changeFeedChannel, msg)
+	return err
+}
+
+func (f *PostgresChangeFeed) Listen(ctx context.Context, fn func(msg string)) error {
+	listener := pq.NewListener(f.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("advert change feed: %v", err)
+		}
+	})
+	defer listener.Close()
+	if err := listener.Listen(changeFeedChannel); err != nil {
+		return err
+	}
+
+	ping := time.NewTicker(time.Minute)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			// nil means the connection was re-established
+			if n != nil {
+				fn(n.Extra)
+			}
+		case <-ping.C:
+			// Detects a dead connection that would otherwise stay silent
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// favoriteSortColumns maps the service sort fields to favorites list expressions.
+// A deleted advert is sorted by the price it had when it was favorited.
+var favoriteSortColumns = map[string]string{
+	"id":         "f.advert_id",
+	"price":      "COALESCE(a.price, f.price_at_add)",
+	"created_at": "f.created_at",
+	"view_count": "COALESCE(a.view_count, 0)",
+}
+
+type PostgresFavoriteRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresFavoriteRepo(db *sqlx.DB) repository.FavoriteRepo {
+	return &PostgresFavoriteRepo{db: db}
+}
+
+func (r *PostgresFavoriteRepo) Add(ctx context.Context, fav model.Favorite) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO favorites (user_id, advert_id, name, price_at_add, created_at)
+         VALUES ($1, $2, $3, $4, $5)
+         ON CONFLICT (user_id, advert_id) DO NOTHING`,
+		fav.UserID, fav.AdvertID, fav.Name, fav.PriceAtAdd, fav.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresFavoriteRepo) Remove(ctx context.Context, userID string, advertID int) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM favorites WHERE user_id = $1 AND advert_id = $2`, userID, advertID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *PostgresFavoriteRepo) List(
+	ctx context.Context,
+	userID string,
+	limit, offset int,
+	sortField, sortOrder string,
+) ([]model.FavoriteAdvert, error) {
+	column, ok := favoriteSortColumns[sortField]
+	if !ok || (sortOrder != "ASC" && sortOrder != "DESC") {
+		return nil, fmt.Errorf("unsupported favorites sort %q %q", sortField, sortOrder)
+	}
+
+	favs := []model.FavoriteAdvert{}
+	query := fmt.Sprintf(`
+        SELECT f.user_id, f.advert_id, COALESCE(a.name, f.name) AS name, f.price_at_add, f.created_at,
+               a.price AS current_price,
+               COALESCE((SELECT p.url
+                           FROM photos p
+                          WHERE p.advert_id = f.advert_id
+                            AND p.status <> 'broken'
+                       ORDER BY p.position
+                          LIMIT 1), '') AS main_photo_url
+          FROM favorites f
+     LEFT JOIN adverts a ON a.id = f.advert_id
+         WHERE f.user_id = $1
+      ORDER BY %s %s, f.advert_id
+         LIMIT $2 OFFSET $3`, column, sortOrder)
+	if err := r.db.SelectContext(ctx, &favs, query, userID, limit, offset); err != nil {
+		return nil, err
+	}
+	return favs, nil
+}
+
+func (r *PostgresFavoriteRepo) CountByAdvertIDs(ctx context.Context, advertIDs []int) (map[int]int, error) {
+	ids := make(pq.Int64Array, 0, len(advertIDs))
+	for _, id := range advertIDs {
+		ids = append(ids, int64(id))
+	}
+	var rows []struct {
+		AdvertID int `db:"advert_id"`
+		Count    int `db:"count"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `
+        SELECT advert_id, COUNT(*) AS count
+          FROM favorites
+         WHERE advert_id = ANY($1)
+      GROUP BY advert_id`, ids)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int]int, len(rows))
+	for _, row := range rows {
+		counts[row.AdvertID] = row.Count
+	}
+	return counts, nil
+}
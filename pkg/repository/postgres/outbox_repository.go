@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// relayLease is how long claimed events are hidden from other relays. Events of a
+// relay that dies before saving the outcome are published again after it.
+const relayLease = 5 * time.Minute
+
+type PostgresOutboxRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresOutboxRepo(db *sqlx.DB) repository.OutboxRepo {
+	return &PostgresOutboxRepo{db: db}
+}
+
+// insertOutbox records an advert event inside the transaction of the change.
+func insertOutbox(ctx context.Context, tx *sqlx.Tx, event string, advertID int, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO outbox (aggregate_id, event, payload) VALUES ($1, $2, $3)`,
+		advertID, event, string(body))
+	return err
+}
+
+func (r *PostgresOutboxRepo) Relay(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	fn func(e model.OutboxEvent) model.OutboxEvent,
+) (int, error) {
+	// Only the head of every advert's queue is eligible: while it is claimed or
+	// failing, later events of the same advert wait, which keeps them in order.
+	// The claim commits on its own, pushing the next attempt past the lease.
+	var events []model.OutboxEvent
+	if err := r.db.SelectContext(ctx, &events, `
+        UPDATE outbox
+           SET next_attempt_at = $2
+         WHERE id IN (SELECT id
+                        FROM outbox o
+                       WHERE published_at IS NULL
+                         AND next_attempt_at <= $1
+                         AND NOT EXISTS (SELECT 1
+                                           FROM outbox p
+                                          WHERE p.aggregate_id = o.aggregate_id
+                                            AND p.published_at IS NULL
+                                            AND p.id < o.id)
+                    ORDER BY id
+                       LIMIT $3
+                         FOR UPDATE SKIP LOCKED)
+     RETURNING id, aggregate_id, event, payload, attempts, next_attempt_at, last_error, created_at, published_at, delivered_to`,
+		now, now.Add(relayLease), limit); err != nil {
+		return 0, err
+	}
+	slices.SortFunc(events, func(a, b model.OutboxEvent) int { return cmp.Compare(a.ID, b.ID) })
+
+	for _, e := range events {
+		e = fn(e)
+		if _, err := r.db.ExecContext(ctx, `
+            UPDATE outbox
+               SET attempts = $1,
+                   next_attempt_at = $2,
+                   last_error = $3,
+                   published_at = $4,
+                   delivered_to = $5
+             WHERE id = $6`,
+			e.Attempts, e.NextAttemptAt, e.LastError, e.PublishedAt, e.DeliveredTo, e.ID); err != nil {
+			return 0, err
+		}
+	}
+	return len(events), nil
+}
+
+func (r *PostgresOutboxRepo) Lag(ctx context.Context) (model.OutboxLag, error) {
+	var lag model.OutboxLag
+	err := r.db.GetContext(ctx, &lag, `
+        SELECT COUNT(*) AS pending,
+               COALESCE(EXTRACT(EPOCH FROM NOW() - MIN(created_at)), 0) AS oldest_age
+          FROM outbox
+         WHERE published_at IS NULL`)
+	return lag, err
+}
+
+func (r *PostgresOutboxRepo) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM outbox WHERE published_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
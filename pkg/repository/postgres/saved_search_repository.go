@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// savedSearchColumns lists the columns scanned into model.SavedSearch
+const savedSearchColumns = `id, user_id, email, name, min_price::float8 AS min_price, max_price::float8 AS max_price,
+               created_from, created_to, sort, created_at`
+
+type PostgresSavedSearchRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresSavedSearchRepo(db *sqlx.DB) repository.SavedSearchRepo {
+	return &PostgresSavedSearchRepo{db: db}
+}
+
+func (r *PostgresSavedSearchRepo) Create(ctx context.Context, s model.SavedSearch) (int, error) {
+	var id int
+	err := r.db.QueryRowContext(
+		ctx,
+		`INSERT INTO saved_searches (user_id, email, name, min_price, max_price, created_from, created_to, sort, created_at)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+         RETURNING id`,
+		s.UserID, s.Email, s.Name, s.MinPrice, s.MaxPrice, s.CreatedFrom, s.CreatedTo, s.Sort, s.CreatedAt,
+	).Scan(&id)
+	return id, err
+}
+
+func (r *PostgresSavedSearchRepo) ListByUser(ctx context.Context, userID string) ([]model.SavedSearch, error) {
+	searches := []model.SavedSearch{}
+	err := r.db.SelectContext(ctx, &searches, `
+        SELECT `+savedSearchColumns+`
+          FROM saved_searches
+         WHERE user_id = $1
+      ORDER BY id DESC`, userID)
+	return searches, err
+}
+
+func (r *PostgresSavedSearchRepo) GetByID(ctx context.Context, userID string, id int) (model.SavedSearch, error) {
+	var search model.SavedSearch
+	err := r.db.GetContext(ctx, &search, `
+        SELECT `+savedSearchColumns+`
+          FROM saved_searches
+         WHERE user_id = $1 AND id = $2`, userID, id)
+	return search, err
+}
+
+func (r *PostgresSavedSearchRepo) Delete(ctx context.Context, userID string, id int) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM saved_searches WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *PostgresSavedSearchRepo) ListMatching(ctx context.Context, ad model.Advert) ([]model.SavedSearch, error) {
+	searches := []model.SavedSearch{}
+	err := r.db.SelectContext(ctx, &searches, `
+        SELECT `+savedSearchColumns+`
+          FROM saved_searches
+         WHERE ($1 = '' OR user_id <> $1)
+           AND (min_price IS NULL OR min_price <= $2)
+           AND (max_price IS NULL OR max_price >= $2)
+           AND (created_from IS NULL OR created_from <= $3)
+           AND (created_to IS NULL OR created_to > $3)
+      ORDER BY id`, ad.OwnerID, ad.Price, ad.CreatedAt)
+	return searches, err
+}
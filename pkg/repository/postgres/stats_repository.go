@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PostgresStatsRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresStatsRepo(db *sqlx.DB) repository.StatsRepo {
+	return &PostgresStatsRepo{db: db}
+}
+
+func (r *PostgresStatsRepo) PriceStats(ctx context.Context, filter model.AdvertFilter) (model.PriceStats, error) {
+	var stats model.PriceStats
+	where, args := filterClause(filter, "", 1)
+	query := fmt.Sprintf(`
+        SELECT COUNT(*) AS count,
+               MIN(price)::float8 AS min,
+               MAX(price)::float8 AS max,
+               AVG(price)::float8 AS avg,
+               PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY price)::float8 AS median
+          FROM adverts
+         %s`, where)
+	err := r.db.GetContext(ctx, &stats, query, args...)
+	return stats, err
+}
+
+func (r *PostgresStatsRepo) PriceHistogram(ctx context.Context, filter model.AdvertFilter, bounds []float64) ([]int64, error) {
+	where, args := filterClause(filter, "", 2)
+	// WIDTH_BUCKET returns 0 below the first bound and i for [bounds[i-1], bounds[i])
+	query := fmt.Sprintf(`
+        SELECT WIDTH_BUCKET(price::float8, $1::float8[]) AS bucket, COUNT(*) AS count
+          FROM adverts
+         %s
+      GROUP BY 1`, where)
+	rows, err := r.db.QueryxContext(ctx, query, append([]interface{}{pq.Float64Array(bounds)}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]int64, len(bounds)+1)
+	for rows.Next() {
+		var bucket int
+		var count int64
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, err
+		}
+		counts[bucket] = count
+	}
+	return counts, rows.Err()
+}
+
+func (r *PostgresStatsRepo) CreatedPerDay(ctx context.Context, filter model.AdvertFilter) ([]model.DayCount, error) {
+	days := []model.DayCount{}
+	where, args := filterClause(filter, "", 1)
+	query := fmt.Sprintf(`
+        SELECT DATE_TRUNC('day', created_at) AS day, COUNT(*) AS count
+          FROM adverts
+         %s
+      GROUP BY 1
+      ORDER BY 1`, where)
+	err := r.db.SelectContext(ctx, &days, query, args...)
+	return days, err
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// webhookDeliveryColumns lists the columns scanned into model.WebhookDelivery
+const webhookDeliveryColumns = `id, subscription_id, event, payload, status, attempts, next_attempt_at,
+               last_error, response_code, created_at, delivered_at`
+
+type PostgresWebhookRepo struct {
+	db *sqlx.DB
+}
+
+func NewPostgresWebhookRepo(db *sqlx.DB) repository.WebhookRepo {
+	return &PostgresWebhookRepo{db: db}
+}
+
+func (r *PostgresWebhookRepo) CreateSubscription(ctx context.Context, sub model.WebhookSubscription) (int, error) {
+	var id int
+	err := r.db.QueryRowContext(
+		ctx,
+		`INSERT INTO webhook_subscriptions (url, secret, events, created_at)
+         VALUES ($1, $2, $3, $4)
+         RETURNING id`,
+		sub.URL, sub.Secret, sub.Events, sub.CreatedAt,
+	).Scan(&id)
+	return id, err
+}
+
+func (r *PostgresWebhookRepo) ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	subs := []model.WebhookSubscription{}
+	err := r.db.SelectContext(ctx, &subs, `
+        SELECT id, url, events, created_at
+          FROM webhook_subscriptions
+      ORDER BY id`)
+	return subs, err
+}
+
+func (r *PostgresWebhookRepo) DeleteSubscription(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *PostgresWebhookRepo) EnqueueEvent(ctx context.Context, event, payload string) (int, error) {
+	res, err := r.db.ExecContext(ctx, `
+        INSERT INTO webhook_deliveries (subscription_id, event, payload)
+        SELECT id, $1, $2
+          FROM webhook_subscriptions
+         WHERE $1 = ANY(events)`, event, payload)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (r *PostgresWebhookRepo) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]model.WebhookDelivery, map[int]model.WebhookSubscription, error) {
+	deliveries := []model.WebhookDelivery{}
+	err := r.db.SelectContext(ctx, &deliveries, `
+        UPDATE webhook_deliveries
+           SET next_attempt_at = $2
+         WHERE id IN (SELECT id
+                        FROM webhook_deliveries
+                       WHERE status = 'pending'
+                         AND next_attempt_at <= $1
+                    ORDER BY next_attempt_at
+                       LIMIT $3
+                         FOR UPDATE SKIP LOCKED)
+     RETURNING `+webhookDeliveryColumns, now, now.Add(lease), limit)
+	if err != nil || len(deliveries) == 0 {
+		return deliveries, nil, err
+	}
+
+	ids := make(pq.Int64Array, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, int64(d.SubscriptionID))
+	}
+	var subs []model.WebhookSubscription
+	if err := r.db.SelectContext(ctx, &subs, `
+        SELECT id, url, secret, events, created_at
+          FROM webhook_subscriptions
+         WHERE id = ANY($1)`, ids); err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[int]model.WebhookSubscription, len(subs))
+	for _, s := range subs {
+		byID[s.ID] = s
+	}
+	return deliveries, byID, nil
+}
+
+func (r *PostgresWebhookRepo) UpdateDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE webhook_deliveries
+           SET status = $1,
+               attempts = $2,
+               next_attempt_at = $3,
+               last_error = $4,
+               response_code = $5,
+               delivered_at = $6
+         WHERE id = $7`,
+		d.Status, d.Attempts, d.NextAttemptAt, d.LastError, d.ResponseCode, d.DeliveredAt, d.ID)
+	return err
+}
+
+func (r *PostgresWebhookRepo) ListDeliveries(ctx context.Context, subscriptionID int, status string, limit int) ([]model.WebhookDelivery, error) {
+	deliveries := []model.WebhookDelivery{}
+	err := r.db.SelectContext(ctx, &deliveries, `
+        SELECT `+webhookDeliveryColumns+`
+          FROM webhook_deliveries
+         WHERE subscription_id = $1
+           AND ($2 = '' OR status = $2)
+      ORDER BY id DESC
+         LIMIT $3`, subscriptionID, status, limit)
+	return deliveries, err
+}
+
+func (r *PostgresWebhookRepo) RetryDelivery(ctx context.Context, subscriptionID int, deliveryID int64) error {
+	res, err := r.db.ExecContext(ctx, `
+        UPDATE webhook_deliveries
+           SET status = 'pending',
+               attempts = 0,
+               next_attempt_at = NOW()
+         WHERE id = $1
+           AND subscription_id = $2
+           AND status <> 'delivered'`, deliveryID, subscriptionID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,639 @@
+// Package repositorytest holds the contract tests of the repository interfaces.
+// Every implementation runs them, so they all behave like the postgres one:
+//
+//	func TestMyRepos(t *testing.T) {
+//		repositorytest.TestAdvertRepo(t, newMyRepos)
+//		repositorytest.TestPhotoRepo(t, newMyRepos)
+//	}
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory returns empty repositories over one storage; it is called by every test.
+type Factory func(t *testing.T) (repository.AdvertRepo, repository.PhotoRepo)
+
+// baseTime is the creation time of the first test advert. Timestamps are whole
+// seconds in UTC, which every storage keeps as is.
+var baseTime = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// TestAdvertRepo checks the AdvertRepo contract: round-trips, sorting,
+// pagination, filters, translations, cascade delete and missing adverts.
+func TestAdvertRepo(t *testing.T, newRepos Factory) {
+	t.Run("CreateGetRoundTrip", func(t *testing.T) { testCreateGet(t, newRepos) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, newRepos) })
+	t.Run("NotFound", func(t *testing.T) { testAdvertNotFound(t, newRepos) })
+	t.Run("ListByIDs", func(t *testing.T) { testListByIDs(t, newRepos) })
+	t.Run("Sort", func(t *testing.T) { testSort(t, newRepos) })
+	t.Run("Pagination", func(t *testing.T) { testPagination(t, newRepos) })
+	t.Run("Filter", func(t *testing.T) { testFilter(t, newRepos) })
+	t.Run("Translations", func(t *testing.T) { testTranslations(t, newRepos) })
+	t.Run("PhotoURLs", func(t *testing.T) { testAdvertPhotoURLs(t, newRepos) })
+	t.Run("FlagAndViews", func(t *testing.T) { testFlagAndViews(t, newRepos) })
+	t.Run("CascadeDelete", func(t *testing.T) { testCascadeDelete(t, newRepos) })
+	t.Run("Stream", func(t *testing.T) { testStream(t, newRepos) })
+}
+
+// TestPhotoRepo checks the PhotoRepo contract: ordering by position, inserting,
+// deleting and reordering photos, the main photo, photos missing variants and
+// missing photos and adverts.
+func TestPhotoRepo(t *testing.T, newRepos Factory) {
+	t.Run("OrderByPosition", func(t *testing.T) { testPhotoOrder(t, newRepos) })
+	t.Run("Insert", func(t *testing.T) { testPhotoInsert(t, newRepos) })
+	t.Run("Delete", func(t *testing.T) { testPhotoDelete(t, newRepos) })
+	t.Run("Reorder", func(t *testing.T) { testPhotoReorder(t, newRepos) })
+	t.Run("MainPhoto", func(t *testing.T) { testMainPhoto(t, newRepos) })
+	t.Run("MissingVariants", func(t *testing.T) { testMissingVariants(t, newRepos) })
+	t.Run("NotFound", func(t *testing.T) { testPhotoNotFound(t, newRepos) })
+}
+
+// createAdvert stores an advert named name with the given price, created i hours after baseTime.
+func createAdvert(t *testing.T, repo repository.AdvertRepo, i int, name string, price float64) int {
+	t.Helper()
+	id, err := repo.Create(context.Background(), model.Advert{
+		Name:          name,
+		Description:   name + " description",
+		Price:         price,
+		CreatedAt:     baseTime.Add(time.Duration(i) * time.Hour),
+		DefaultLocale: "en",
+	})
+	require.NoError(t, err)
+	require.Positive(t, id)
+	return id
+}
+
+// createPhotos stores photos with the given URLs at positions 1..n.
+func createPhotos(t *testing.T, repo repository.PhotoRepo, advertID int, urls ...string) []int {
+	t.Helper()
+	ctx := context.Background()
+	for i, url := range urls {
+		require.NoError(t, repo.Create(ctx, model.Photo{AdvertID: advertID, URL: url, Position: i + 1}))
+	}
+	photos, err := repo.ListByAdvertID(ctx, advertID)
+	require.NoError(t, err)
+	ids := make([]int, len(photos))
+	for i, p := range photos {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func names(ads []model.Advert) []string {
+	names := []string{}
+	for _, ad := range ads {
+		names = append(names, ad.Name)
+	}
+	return names
+}
+
+func photoURLs(t *testing.T, repo repository.PhotoRepo, advertID int) []string {
+	t.Helper()
+	photos, err := repo.ListByAdvertID(context.Background(), advertID)
+	require.NoError(t, err)
+	urls := []string{}
+	for i, p := range photos {
+		assert.Equal(t, i+1, p.Position, "positions are 1..n")
+		urls = append(urls, p.URL)
+	}
+	return urls
+}
+
+func testCreateGet(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, _ := newRepos(t)
+
+	ad := model.Advert{
+		OwnerID:       "seller-1",
+		Name:          "Bike",
+		Description:   "Red bike",
+		Price:         99.99,
+		CreatedAt:     baseTime,
+		DefaultLocale: "ru",
+	}
+	id, err := adverts.Create(ctx, ad)
+	require.NoError(t, err)
+	other, err := adverts.Create(ctx, ad)
+	require.NoError(t, err)
+	assert.NotEqual(t, id, other, "IDs are unique")
+
+	got, err := adverts.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, id, got.ID)
+	assert.Equal(t, ad.OwnerID, got.OwnerID)
+	assert.Equal(t, ad.Name, got.Name)
+	assert.Equal(t, ad.Description, got.Description)
+	assert.Equal(t, ad.Price, got.Price)
+	assert.True(t, ad.CreatedAt.Equal(got.CreatedAt), "created_at %v != %v", got.CreatedAt, ad.CreatedAt)
+	assert.Equal(t, ad.DefaultLocale, got.DefaultLocale)
+	assert.Empty(t, got.FlagReason)
+	assert.Zero(t, got.ViewCount)
+	assert.Nil(t, got.Translations, "reads leave translations nil")
+}
+
+func testUpdate(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, _ := newRepos(t)
+	id := createAdvert(t, adverts, 0, "Bike", 100)
+
+	require.NoError(t, adverts.Update(ctx, model.Advert{
+		ID: id, Name: "Bicycle", Description: "Blue bicycle", Price: 120.5, DefaultLocale: "de",
+		// Not updatable
+		OwnerID: "someone-else", CreatedAt: baseTime.Add(time.Hour), ViewCount: 7,
+	}))
+
+	got, err := adverts.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Bicycle", got.Name)
+	assert.Equal(t, "Blue bicycle", got.Description)
+	assert.Equal(t, 120.5, got.Price)
+	assert.Equal(t, "de", got.DefaultLocale)
+	assert.Empty(t, got.OwnerID)
+	assert.True(t, baseTime.Equal(got.CreatedAt))
+	assert.Zero(t, got.ViewCount)
+}
+
+func testAdvertNotFound(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, _ := newRepos(t)
+	id := createAdvert(t, adverts, 0, "Bike", 100)
+	missing := id + 1000
+
+	_, err := adverts.GetByID(ctx, missing)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.ErrorIs(t, adverts.Update(ctx, model.Advert{ID: missing, Name: "Ghost", Price: 1}), sql.ErrNoRows)
+	assert.ErrorIs(t, adverts.Delete(ctx, missing), sql.ErrNoRows)
+	// Flagging a missing advert is a no-op
+	assert.NoError(t, adverts.SetFlag(ctx, missing, "spam"))
+	_, err = adverts.GetByID(ctx, missing)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, adverts.Delete(ctx, id))
+	_, err = adverts.GetByID(ctx, id)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.ErrorIs(t, adverts.Delete(ctx, id), sql.ErrNoRows, "deleting twice")
+}
+
+func testListByIDs(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, _ := newRepos(t)
+	bike := createAdvert(t, adverts, 0, "Bike", 100)
+	createAdvert(t, adverts, 1, "Car", 200)
+	boat := createAdvert(t, adverts, 2, "Boat", 300)
+
+	// Ordered by ID, unknown and repeated IDs are skipped
+	ads, err := adverts.ListByIDs(ctx, []int{boat, boat + 1000, bike, boat})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bike", "Boat"}, names(ads))
+	assert.Equal(t, 100.0, ads[0].Price)
+
+	ads, err = adverts.ListByIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, ads)
+}
+
+func testSort(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, _ := newRepos(t)
+	// Every sort field has distinct values, so the order is fully defined
+	a := createAdvert(t, adverts, 0, "a", 300)
+	b := createAdvert(t, adverts, 2, "b", 100)
+	c := createAdvert(t, adverts, 1, "c", 200)
+	require.NoError(t, adverts.AddViews(ctx, map[int]int64{a: 5, b: 20, c: 10}))
+
+	cases := []struct {
+		field, order string
+		want         []string
+	}{
+		{"id", "ASC", []string{"a", "b", "c"}},
+		{"id", "DESC", []string{"c", "b", "a"}},
+		{"price", "ASC", []string{"b", "c", "a"}},
+		{"price", "DESC", []string{"a", "c", "b"}},
+		{"created_at", "ASC", []string{"a", "c", "b"}},
+		{"created_at", "DESC", []string{"b", "c", "a"}},
+		{"view_count", "ASC", []string{"a", "c", "b"}},
+		{"view_count", "DESC", []string{"b", "c", "a"}},
+	}
+	for _, tc := range cases {
+		ads, err := adverts.List(ctx, model.AdvertFilter{}, 10, 0, tc.field, tc.order)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, names(ads), "%s %s", tc.field, tc.order)
+	}
+}
+
+func testPagination(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, _ := newRepos(t)
+	for i, name := range []string{"a", "b", "c", "d", "e"} {
+		createAdvert(t, adverts, i, name, float64(10*(i+1)))
+	}
+
+	cases := []struct {
+		limit, offset int
+		want          []string
+	}{
+		{2, 0, []string{"a", "b"}},
+		{2, 2, []string{"c", "d"}},
+		{2, 4, []string{"e"}},
+		{2, 5, []string{}},
+		{2, 100, []string{}},
+		{10, 0, []string{"a", "b", "c", "d", "e"}},
+		{0, 0, []string{}},
+	}
+	for _, tc := range cases {
+		ads, err := adverts.List(ctx, model.AdvertFilter{}, tc.limit, tc.offset, "price", "ASC")
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, names(ads), "limit %d offset %d", tc.limit, tc.offset)
+	}
+}
+
+func testFilter(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, _ := newRepos(t)
+	for i, price := range []float64{100, 200, 300} {
+		createAdvert(t, adverts, 24*i, string(rune('a'+i)), price)
+	}
+	price := func(p float64) *float64 { return &p }
+	day := func(d int) *time.Time {
+		at := baseTime.AddDate(0, 0, d)
+		return &at
+	}
+
+	cases := map[string]struct {
+		filter model.AdvertFilter
+		want   []string
+	}{
+		"MinPrice":  {model.AdvertFilter{MinPrice: price(200)}, []string{"b", "c"}},
+		"MaxPrice":  {model.AdvertFilter{MaxPrice: price(200)}, []string{"a", "b"}},
+		"PriceBand": {model.AdvertFilter{MinPrice: price(150), MaxPrice: price(250)}, []string{"b"}},
+		// CreatedTo is exclusive
+		"CreatedRange": {model.AdvertFilter{CreatedFrom: day(1), CreatedTo: day(2)}, []string{"b"}},
+		"None":         {model.AdvertFilter{MinPrice: price(1000)}, []string{}},
+	}
+	for name, tc := range cases {
+		ads, err := adverts.List(ctx, tc.filter, 10, 0, "id", "ASC")
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, names(ads), name)
+	}
+}
+
+func testTranslations(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, _ := newRepos(t)
+
+	id, err := adverts.Create(ctx, model.Advert{
+		Name: "Bike", Description: "Red bike", Price: 10, CreatedAt: baseTime, DefaultLocale: "en",
+		Translations: []model.AdvertTranslation{
+			{Locale: "ru", Name: "Велосипед", Description: "Красный велосипед"},
+			{Locale: "en", Name: "Bike", Description: "Red bike"},
+		},
+	})
+	require.NoError(t, err)
+	untranslated := createAdvert(t, adverts, 1, "Car", 20)
+
+	want := []model.AdvertTranslation{
+		{AdvertID: id, Locale: "en", Name: "Bike", Description: "Red bike"},
+		{AdvertID: id, Locale: "ru", Name: "Велосипед", Description: "Красный велосипед"},
+	}
+	translations, err := adverts.ListTranslations(ctx, []int{id, untranslated, id + 1000})
+	require.NoError(t, err)
+	assert.Equal(t, map[int][]model.AdvertTranslation{id: want}, translations)
+
+	// Nil translations keep the stored ones
+	require.NoError(t, adverts.Update(ctx, model.Advert{ID: id, Name: "Bike", Description: "Red bike", Price: 15, DefaultLocale: "en"}))
+	translations, err = adverts.ListTranslations(ctx, []int{id})
+	require.NoError(t, err)
+	assert.Equal(t, want, translations[id])
+
+	// Otherwise they replace them
+	require.NoError(t, adverts.Update(ctx, model.Advert{
+		ID: id, Name: "Велосипед", Description: "Синий велосипед", Price: 15, DefaultLocale: "ru",
+		Translations: []model.AdvertTranslation{{Locale: "ru", Name: "Велосипед", Description: "Синий велосипед"}},
+	}))
+	translations, err = adverts.ListTranslations(ctx, []int{id})
+	require.NoError(t, err)
+	assert.Equal(t, []model.AdvertTranslation{
+		{AdvertID: id, Locale: "ru", Name: "Велосипед", Description: "Синий велосипед"},
+	}, translations[id])
+}
+
+func testAdvertPhotoURLs(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, photos := newRepos(t)
+
+	id, err := adverts.Create(ctx, model.Advert{
+		Name: "Bike", Description: "Red bike", Price: 10, CreatedAt: baseTime, DefaultLocale: "en",
+		PhotoURLs: []string{"http://img/1", "http://img/2"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://img/1", "http://img/2"}, photoURLs(t, photos, id))
+
+	// Nil photo URLs keep the stored photos
+	require.NoError(t, adverts.Update(ctx, model.Advert{ID: id, Name: "Bike", Description: "Red bike", Price: 15, DefaultLocale: "en"}))
+	assert.Equal(t, []string{"http://img/1", "http://img/2"}, photoURLs(t, photos, id))
+
+	// Otherwise they replace them
+	require.NoError(t, adverts.Update(ctx, model.Advert{
+		ID: id, Name: "Bike", Description: "Red bike", Price: 15, DefaultLocale: "en",
+		PhotoURLs: []string{"http://img/3"},
+	}))
+	assert.Equal(t, []string{"http://img/3"}, photoURLs(t, photos, id))
+}
+
+func testFlagAndViews(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, _ := newRepos(t)
+	id := createAdvert(t, adverts, 0, "Bike", 100)
+
+	require.NoError(t, adverts.SetFlag(ctx, id, "duplicate photo"))
+	// Unknown IDs are ignored
+	require.NoError(t, adverts.AddViews(ctx, map[int]int64{id: 3, id + 1000: 5}))
+	require.NoError(t, adverts.AddViews(ctx, map[int]int64{id: 2}))
+	require.NoError(t, adverts.AddViews(ctx, nil))
+
+	got, err := adverts.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "duplicate photo", got.FlagReason)
+	assert.Equal(t, int64(5), got.ViewCount)
+
+	require.NoError(t, adverts.SetFlag(ctx, id, ""))
+	got, err = adverts.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, got.FlagReason)
+}
+
+func testCascadeDelete(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, photos := newRepos(t)
+
+	id, err := adverts.Create(ctx, model.Advert{
+		Name: "Bike", Price: 10, CreatedAt: baseTime, DefaultLocale: "en",
+		Translations: []model.AdvertTranslation{{Locale: "en", Name: "Bike"}},
+	})
+	require.NoError(t, err)
+	photoIDs := createPhotos(t, photos, id, "https://example.com/1.jpg", "https://example.com/2.jpg")
+	require.NoError(t, photos.SaveVariant(ctx, model.PhotoVariant{PhotoID: photoIDs[0], Name: "thumb", URL: "thumb.jpg", Width: 1, Height: 1}))
+	kept := createAdvert(t, adverts, 1, "Car", 20)
+	createPhotos(t, photos, kept, "https://example.com/car.jpg")
+
+	require.NoError(t, adverts.Delete(ctx, id))
+
+	byAdvert, err := photos.ListByAdvertIDs(ctx, []int{id, kept})
+	require.NoError(t, err)
+	assert.NotContains(t, byAdvert, id)
+	assert.Len(t, byAdvert[kept], 1, "photos of other adverts stay")
+	_, err = photos.GetMainPhotoURL(ctx, id)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	url, err := photos.GetMainPhotoVariantURL(ctx, id, "thumb")
+	require.NoError(t, err)
+	assert.Empty(t, url)
+	translations, err := adverts.ListTranslations(ctx, []int{id})
+	require.NoError(t, err)
+	assert.Empty(t, translations)
+}
+
+func testStream(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, photos := newRepos(t)
+	a := createAdvert(t, adverts, 0, "a", 200)
+	b := createAdvert(t, adverts, 1, "b", 100)
+	createAdvert(t, adverts, 2, "c", 300)
+	createPhotos(t, photos, a, "a1", "a2")
+	createPhotos(t, photos, b, "b1")
+
+	var streamed []string
+	urls := map[string][]string{}
+	err := adverts.Stream(ctx, model.AdvertFilter{}, "price", "ASC", 2, func(ad model.Advert, photoURLs []string) error {
+		streamed = append(streamed, ad.Name)
+		urls[ad.Name] = append([]string{}, photoURLs...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a", "c"}, streamed)
+	assert.Equal(t, map[string][]string{"a": {"a1", "a2"}, "b": {"b1"}, "c": {}}, urls)
+
+	// An error of fn stops the walk
+	calls := 0
+	err = adverts.Stream(ctx, model.AdvertFilter{}, "id", "ASC", 1, func(model.Advert, []string) error {
+		calls++
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, calls)
+
+	// Filters apply the same way as in List
+	minPrice, maxPrice := 150.0, 250.0
+	streamed = nil
+	err = adverts.Stream(ctx, model.AdvertFilter{MinPrice: &minPrice, MaxPrice: &maxPrice}, "id", "ASC", 1, func(ad model.Advert, _ []string) error {
+		streamed = append(streamed, ad.Name)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, streamed)
+}
+
+func testPhotoOrder(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, photos := newRepos(t)
+	id := createAdvert(t, adverts, 0, "Bike", 100)
+	other := createAdvert(t, adverts, 1, "Car", 200)
+	empty := createAdvert(t, adverts, 2, "Boat", 300)
+
+	// Stored out of order
+	for _, p := range []model.Photo{{URL: "third", Position: 3}, {URL: "first", Position: 1}, {URL: "second", Position: 2}} {
+		p.AdvertID = id
+		require.NoError(t, photos.Create(ctx, p))
+	}
+	createPhotos(t, photos, other, "car")
+
+	all, err := photos.GetAllPhotoURLs(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "third"}, all)
+	assert.Equal(t, []string{"first", "second", "third"}, photoURLs(t, photos, id))
+
+	list, err := photos.ListByAdvertID(ctx, empty)
+	require.NoError(t, err)
+	assert.NotNil(t, list)
+	assert.Empty(t, list)
+
+	byAdvert, err := photos.ListByAdvertIDs(ctx, []int{id, other, empty})
+	require.NoError(t, err)
+	assert.Len(t, byAdvert, 2, "adverts without photos are missing")
+	require.Len(t, byAdvert[id], 3)
+	for i, p := range byAdvert[id] {
+		assert.Equal(t, id, p.AdvertID)
+		assert.Equal(t, i+1, p.Position)
+		assert.Equal(t, model.PhotoStatusUnchecked, p.Status)
+	}
+	assert.Equal(t, "car", byAdvert[other][0].URL)
+}
+
+func testPhotoInsert(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, photos := newRepos(t)
+	id := createAdvert(t, adverts, 0, "Bike", 100)
+	createPhotos(t, photos, id, "a", "b")
+
+	inserted, err := photos.Insert(ctx, model.Photo{AdvertID: id, URL: "middle", Position: 2}, 10)
+	require.NoError(t, err)
+	assert.Positive(t, inserted.ID)
+	assert.Equal(t, 2, inserted.Position)
+	assert.Equal(t, model.PhotoStatusUnchecked, inserted.Status)
+	assert.Equal(t, []string{"a", "middle", "b"}, photoURLs(t, photos, id))
+
+	inserted, err = photos.Insert(ctx, model.Photo{AdvertID: id, URL: "first", Position: 1}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, inserted.Position)
+
+	// Positions outside 1..n+1 append the photo
+	for _, position := range []int{0, -1, 100} {
+		inserted, err = photos.Insert(ctx, model.Photo{AdvertID: id, URL: "last", Position: position}, 10)
+		require.NoError(t, err)
+		assert.Equal(t, len(photoURLs(t, photos, id)), inserted.Position, "position %d", position)
+	}
+	assert.Equal(t, []string{"first", "a", "middle", "b", "last", "last", "last"}, photoURLs(t, photos, id))
+
+	// The advert is full
+	_, err = photos.Insert(ctx, model.Photo{AdvertID: id, URL: "extra"}, 7)
+	assert.ErrorIs(t, err, repository.ErrPhotoCount)
+	assert.Len(t, photoURLs(t, photos, id), 7)
+}
+
+func testPhotoDelete(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, photos := newRepos(t)
+	id := createAdvert(t, adverts, 0, "Bike", 100)
+	other := createAdvert(t, adverts, 1, "Car", 200)
+	ids := createPhotos(t, photos, id, "a", "b", "c")
+	otherIDs := createPhotos(t, photos, other, "car")
+
+	require.NoError(t, photos.Delete(ctx, id, ids[1]))
+	assert.Equal(t, []string{"a", "c"}, photoURLs(t, photos, id), "positions are compacted")
+
+	assert.ErrorIs(t, photos.Delete(ctx, id, ids[1]), sql.ErrNoRows, "already deleted")
+	assert.ErrorIs(t, photos.Delete(ctx, id, otherIDs[0]), sql.ErrNoRows, "photo of another advert")
+	assert.Equal(t, []string{"car"}, photoURLs(t, photos, other))
+	assert.ErrorIs(t, photos.Delete(ctx, other, otherIDs[0]), repository.ErrPhotoCount, "last photo")
+	assert.Equal(t, []string{"car"}, photoURLs(t, photos, other))
+
+	require.NoError(t, photos.DeleteByAdvertID(ctx, id))
+	assert.Empty(t, photoURLs(t, photos, id))
+	assert.Equal(t, []string{"car"}, photoURLs(t, photos, other))
+}
+
+func testPhotoReorder(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, photos := newRepos(t)
+	id := createAdvert(t, adverts, 0, "Bike", 100)
+	other := createAdvert(t, adverts, 1, "Car", 200)
+	ids := createPhotos(t, photos, id, "a", "b", "c")
+	otherIDs := createPhotos(t, photos, other, "car")
+
+	require.NoError(t, photos.Reorder(ctx, id, []int{ids[2], ids[0], ids[1]}))
+	assert.Equal(t, []string{"c", "a", "b"}, photoURLs(t, photos, id))
+
+	// Every photo of the advert exactly once
+	for name, order := range map[string][]int{
+		"Missing":      {ids[2], ids[0]},
+		"Duplicate":    {ids[2], ids[2], ids[0]},
+		"Extra":        {ids[2], ids[0], ids[1], ids[1]},
+		"OtherAdvert":  {ids[2], ids[0], otherIDs[0]},
+		"UnknownPhoto": {ids[2], ids[0], otherIDs[0] + 1000},
+	} {
+		assert.Error(t, photos.Reorder(ctx, id, order), name)
+		assert.Equal(t, []string{"c", "a", "b"}, photoURLs(t, photos, id), "%s leaves the order", name)
+	}
+	assert.Equal(t, []string{"car"}, photoURLs(t, photos, other))
+}
+
+func testMainPhoto(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, photos := newRepos(t)
+	id := createAdvert(t, adverts, 0, "Bike", 100)
+	ids := createPhotos(t, photos, id, "a", "b")
+	require.NoError(t, photos.SaveVariant(ctx, model.PhotoVariant{PhotoID: ids[1], Name: "thumb", URL: "b-thumb", Width: 1, Height: 1}))
+
+	url, err := photos.GetMainPhotoURL(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "a", url)
+	url, err = photos.GetMainPhotoVariantURL(ctx, id, "thumb")
+	require.NoError(t, err)
+	assert.Empty(t, url, "variant not generated")
+
+	// Broken photos are skipped
+	require.NoError(t, photos.SetCheckResult(ctx, ids[0], model.PhotoStatusBroken, "404", baseTime))
+	url, err = photos.GetMainPhotoURL(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "b", url)
+	url, err = photos.GetMainPhotoVariantURL(ctx, id, "thumb")
+	require.NoError(t, err)
+	assert.Equal(t, "b-thumb", url)
+
+	byAdvert, err := photos.ListByAdvertIDs(ctx, []int{id})
+	require.NoError(t, err)
+	require.Len(t, byAdvert[id], 2)
+	assert.Equal(t, model.PhotoStatusBroken, byAdvert[id][0].Status)
+	assert.Equal(t, "404", byAdvert[id][0].CheckError)
+	assert.Equal(t, map[string]string{"thumb": "b-thumb"}, byAdvert[id][1].Variants)
+}
+
+func testMissingVariants(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, photos := newRepos(t)
+	id := createAdvert(t, adverts, 0, "Bike", 100)
+	ids := createPhotos(t, photos, id, "/media/a.jpg", "/media/b.jpg", "/media/c.jpg", "http://example.com/d.jpg")
+	variants := []string{"thumb", "large"}
+	for _, name := range variants {
+		require.NoError(t, photos.SaveVariant(ctx, model.PhotoVariant{PhotoID: ids[0], Name: name, URL: name, Width: 1, Height: 1}))
+	}
+	require.NoError(t, photos.SaveVariant(ctx, model.PhotoVariant{PhotoID: ids[1], Name: "thumb", URL: "thumb", Width: 1, Height: 1}))
+
+	// Only uploaded photos lacking some variant, in ID order
+	missing, err := photos.ListMissingVariants(ctx, "/media/", variants, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, missing, 2)
+	assert.Equal(t, []int{ids[1], ids[2]}, []int{missing[0].ID, missing[1].ID})
+	assert.Equal(t, "/media/b.jpg", missing[0].URL)
+
+	// The next batch starts after the last seen ID
+	missing, err = photos.ListMissingVariants(ctx, "/media/", variants, ids[1], 10)
+	require.NoError(t, err)
+	require.Len(t, missing, 1)
+	assert.Equal(t, ids[2], missing[0].ID)
+	missing, err = photos.ListMissingVariants(ctx, "/media/", variants, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, missing, 1)
+	assert.Equal(t, ids[1], missing[0].ID)
+}
+
+func testPhotoNotFound(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	adverts, photos := newRepos(t)
+	id := createAdvert(t, adverts, 0, "Bike", 100)
+	missing := id + 1000
+
+	_, err := photos.GetMainPhotoURL(ctx, id)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "advert without photos")
+	_, err = photos.GetMainPhotoURL(ctx, missing)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	url, err := photos.GetMainPhotoVariantURL(ctx, missing, "thumb")
+	require.NoError(t, err)
+	assert.Empty(t, url)
+
+	// Changing the photos of a missing advert
+	_, err = photos.Insert(ctx, model.Photo{AdvertID: missing, URL: "a"}, 10)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.ErrorIs(t, photos.Delete(ctx, missing, 1), sql.ErrNoRows)
+	assert.ErrorIs(t, photos.Reorder(ctx, missing, nil), sql.ErrNoRows)
+	assert.Error(t, photos.Create(ctx, model.Photo{AdvertID: missing, URL: "a", Position: 1}))
+}
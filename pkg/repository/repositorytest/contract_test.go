@@ -0,0 +1,70 @@
+package repositorytest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/configs"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository/memory"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository/postgres"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository/repositorytest"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+func TestMemory(t *testing.T) {
+	newRepos := func(t *testing.T) (repository.AdvertRepo, repository.PhotoRepo) {
+		store := memory.NewStore()
+		return memory.NewMemoryAdvertRepo(store), memory.NewMemoryPhotoRepo(store)
+	}
+	t.Run("AdvertRepo", func(t *testing.T) { repositorytest.TestAdvertRepo(t, newRepos) })
+	t.Run("PhotoRepo", func(t *testing.T) { repositorytest.TestPhotoRepo(t, newRepos) })
+}
+
+// TestSQLite runs every test on a fresh database file.
+func TestSQLite(t *testing.T) {
+	newRepos := func(t *testing.T) (repository.AdvertRepo, repository.PhotoRepo) {
+		cfg := &configs.Config{}
+		cfg.DB.Driver = "sqlite"
+		cfg.DB.Path = filepath.Join(t.TempDir(), "test.db")
+		db, err := repository.NewDb(cfg)
+		if err != nil {
+			t.Fatalf("failed to open the test database: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		if err := sqlite.Migrate(context.Background(), db); err != nil {
+			t.Fatalf("failed to migrate the test database: %v", err)
+		}
+		return sqlite.NewSQLiteAdvertRepo(db), sqlite.NewSQLitePhotoRepo(db)
+	}
+	t.Run("AdvertRepo", func(t *testing.T) { repositorytest.TestAdvertRepo(t, newRepos) })
+	t.Run("PhotoRepo", func(t *testing.T) { repositorytest.TestPhotoRepo(t, newRepos) })
+}
+
+// TestPostgres needs a migrated database at TEST_DATABASE_DSN (make migrate-test-up);
+// every test starts from empty tables.
+func TestPostgres(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to the test database: %v", err)
+	}
+	defer db.Close()
+
+	newRepos := func(t *testing.T) (repository.AdvertRepo, repository.PhotoRepo) {
+		if _, err := db.Exec(`TRUNCATE adverts, photos, photo_variants, advert_translations, outbox RESTART IDENTITY CASCADE`); err != nil {
+			t.Fatalf("failed to empty the test database: %v", err)
+		}
+		return postgres.NewPostgresAdvertRepo(db), postgres.NewPostgresPhotoRepo(db)
+	}
+	t.Run("AdvertRepo", func(t *testing.T) { repositorytest.TestAdvertRepo(t, newRepos) })
+	t.Run("PhotoRepo", func(t *testing.T) { repositorytest.TestPhotoRepo(t, newRepos) })
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+type SavedSearchRepo interface {
+	// Create stores a search and returns its ID
+	Create(ctx context.Context, search model.SavedSearch) (int, error)
+	// ListByUser returns the user's searches, newest first
+	ListByUser(ctx context.Context, userID string) ([]model.SavedSearch, error)
+	// GetByID returns a search of the user, sql.ErrNoRows if there is none
+	GetByID(ctx context.Context, userID string, id int) (model.SavedSearch, error)
+	// Delete removes a search of the user, returns sql.ErrNoRows if there is none
+	Delete(ctx context.Context, userID string, id int) error
+	// ListMatching returns the searches of other users whose filters match the advert
+	ListMatching(ctx context.Context, ad model.Advert) ([]model.SavedSearch, error)
+}
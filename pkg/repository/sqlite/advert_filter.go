@@ -0,0 +1,39 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// filterClause renders the filter as a WHERE clause over the adverts columns
+// (prefixed with alias, if any); placeholders are numbered from firstArg.
+// An empty filter yields "" and no args.
+func filterClause(filter model.AdvertFilter, alias string, firstArg int) (string, []interface{}) {
+	if alias != "" {
+		alias += "."
+	}
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, alias, firstArg+len(args)-1))
+	}
+	if filter.MinPrice != nil {
+		add("%sprice >= $%d", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		add("%sprice <= $%d", *filter.MaxPrice)
+	}
+	if filter.CreatedFrom != nil {
+		add("%screated_at >= $%d", filter.CreatedFrom.UTC())
+	}
+	if filter.CreatedTo != nil {
+		add("%screated_at < $%d", filter.CreatedTo.UTC())
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,274 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// advertColumns lists the columns scanned into model.Advert
+const advertColumns = `id, name, description, price, created_at, owner_id, flag_reason, view_count, default_locale`
+
+type SQLiteAdvertRepo struct {
+	db *sqlx.DB
+}
+
+func NewSQLiteAdvertRepo(db *sqlx.DB) repository.AdvertRepo {
+	return &SQLiteAdvertRepo{db: db}
+}
+
+// idList encodes IDs as a JSON array for json_each, the SQLite stand-in for ANY($1).
+func idList(ids []int) string {
+	if ids == nil {
+		ids = []int{}
+	}
+	list, _ := json.Marshal(ids)
+	return string(list)
+}
+
+func (r *SQLiteAdvertRepo) Create(ctx context.Context, ad model.Advert) (int, error) {
+	err := repository.InTx(ctx, r.db, nil, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO adverts (name, description, price, created_at, owner_id, default_locale)
+         VALUES ($1, $2, ROUND($3, 2), $4, $5, $6)
+         RETURNING id`,
+			ad.Name, ad.Description, ad.Price, ad.CreatedAt.UTC(), ad.OwnerID, ad.DefaultLocale,
+		).Scan(&ad.ID)
+		if err != nil {
+			return err
+		}
+		if err := insertTranslations(ctx, tx, ad.ID, ad.Translations); err != nil {
+			return err
+		}
+		if err := insertPhotos(ctx, tx, ad.ID, ad.PhotoURLs); err != nil {
+			return err
+		}
+		return insertOutbox(ctx, tx, model.EventAdvertCreated, ad.ID, ad)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ad.ID, nil
+}
+
+func (r *SQLiteAdvertRepo) List(
+	ctx context.Context,
+	filter model.AdvertFilter,
+	limit, offset int,
+	sortField, sortOrder string,
+) ([]model.Advert, error) {
+	var ads []model.Advert
+	where, args := filterClause(filter, "", 3)
+	query := fmt.Sprintf(`
+        SELECT `+advertColumns+`
+          FROM adverts
+         %s
+         ORDER BY %s %s, id
+         LIMIT $1 OFFSET $2`, where, sortField, sortOrder)
+	if err := r.db.SelectContext(ctx, &ads, query, append([]interface{}{limit, offset}, args...)...); err != nil {
+		return nil, err
+	}
+	return ads, nil
+}
+
+func (r *SQLiteAdvertRepo) GetByID(ctx context.Context, id int) (model.Advert, error) {
+	var ad model.Advert
+	err := r.db.GetContext(ctx, &ad, `
+        SELECT `+advertColumns+`
+          FROM adverts
+         WHERE id = $1`, id)
+	return ad, err
+}
+
+func (r *SQLiteAdvertRepo) ListByIDs(ctx context.Context, ids []int) ([]model.Advert, error) {
+	ads := []model.Advert{}
+	err := r.db.SelectContext(ctx, &ads, `
+        SELECT `+advertColumns+`
+          FROM adverts
+         WHERE id IN (SELECT value FROM json_each($1))
+      ORDER BY id`, idList(ids))
+	return ads, err
+}
+
+func (r *SQLiteAdvertRepo) Update(ctx context.Context, ad model.Advert) error {
+	return repository.InTx(ctx, r.db, nil, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(
+			ctx,
+			`UPDATE adverts
+            SET name = $1,
+                description = $2,
+                price = ROUND($3, 2),
+                default_locale = $4
+          WHERE id = $5`,
+			ad.Name, ad.Description, ad.Price, ad.DefaultLocale, ad.ID,
+		)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return sql.ErrNoRows
+		}
+		if ad.Translations != nil {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM advert_translations WHERE advert_id = $1`, ad.ID); err != nil {
+				return err
+			}
+			if err := insertTranslations(ctx, tx, ad.ID, ad.Translations); err != nil {
+				return err
+			}
+		}
+		if ad.PhotoURLs != nil {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM photos WHERE advert_id = $1`, ad.ID); err != nil {
+				return err
+			}
+			if err := insertPhotos(ctx, tx, ad.ID, ad.PhotoURLs); err != nil {
+				return err
+			}
+		}
+		return insertOutbox(ctx, tx, model.EventAdvertUpdated, ad.ID, ad)
+	})
+}
+
+func (r *SQLiteAdvertRepo) ListTranslations(ctx context.Context, advertIDs []int) (map[int][]model.AdvertTranslation, error) {
+	var translations []model.AdvertTranslation
+	err := r.db.SelectContext(ctx, &translations, `
+        SELECT advert_id, locale, name, description
+          FROM advert_translations
+         WHERE advert_id IN (SELECT value FROM json_each($1))
+      ORDER BY advert_id, locale`, idList(advertIDs))
+	if err != nil {
+		return nil, err
+	}
+	byAdvert := make(map[int][]model.AdvertTranslation)
+	for _, t := range translations {
+		byAdvert[t.AdvertID] = append(byAdvert[t.AdvertID], t)
+	}
+	return byAdvert, nil
+}
+
+// insertTranslations stores the texts of an advert in a single statement.
+func insertTranslations(ctx context.Context, tx *sqlx.Tx, advertID int, translations []model.AdvertTranslation) error {
+	if len(translations) == 0 {
+		return nil
+	}
+	texts, err := json.Marshal(translations)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO advert_translations (advert_id, locale, name, description)
+        SELECT $1, t.value ->> 'locale', t.value ->> 'name', t.value ->> 'description'
+          FROM json_each($2) AS t`,
+		advertID, string(texts))
+	return err
+}
+
+// insertPhotos stores the photo URLs of an advert at positions 1, 2, ... in a single statement.
+func insertPhotos(ctx context.Context, tx *sqlx.Tx, advertID int, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	list, err := json.Marshal(urls)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO photos (advert_id, url, position)
+        SELECT $1, t.value, t.key + 1
+          FROM json_each($2) AS t`,
+		advertID, string(list))
+	return err
+}
+
+// Delete relies on ON DELETE CASCADE for photos, their variants and translations.
+func (r *SQLiteAdvertRepo) Delete(ctx context.Context, id int) error {
+	return repository.InTx(ctx, r.db, nil, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM adverts WHERE id = $1`, id)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return sql.ErrNoRows
+		}
+		return insertOutbox(ctx, tx, model.EventAdvertDeleted, id, map[string]int{"id": id})
+	})
+}
+
+func (r *SQLiteAdvertRepo) SetFlag(ctx context.Context, id int, reason string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE adverts SET flag_reason = $1 WHERE id = $2`, reason, id)
+	return err
+}
+
+// AddViews applies all counters in a single statement.
+func (r *SQLiteAdvertRepo) AddViews(ctx context.Context, views map[int]int64) error {
+	if len(views) == 0 {
+		return nil
+	}
+	// JSON object keys are strings, so the counters are keyed by the decimal ID
+	counts := make(map[string]int64, len(views))
+	for id, n := range views {
+		counts[fmt.Sprint(id)] = n
+	}
+	body, err := json.Marshal(counts)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+        UPDATE adverts
+           SET view_count = view_count + v.value
+          FROM json_each($1) AS v
+         WHERE adverts.id = CAST(v.key AS INTEGER)`, string(body))
+	return err
+}
+
+// Stream walks over all adverts with a single query: SQLite reads rows lazily
+// from a consistent snapshot, so batchSize is not needed to bound memory.
+func (r *SQLiteAdvertRepo) Stream(
+	ctx context.Context,
+	filter model.AdvertFilter,
+	sortField, sortOrder string,
+	batchSize int,
+	fn func(ad model.Advert, photoURLs []string) error,
+) error {
+	where, args := filterClause(filter, "a", 1)
+	query := fmt.Sprintf(`
+        SELECT a.id, a.name, a.description, a.price, a.created_at, a.owner_id, a.flag_reason, a.view_count, a.default_locale,
+               (SELECT json_group_array(p.url ORDER BY p.position)
+                  FROM photos p
+                 WHERE p.advert_id = a.id) AS photo_urls
+          FROM adverts a
+         %s
+         ORDER BY a.%s %s, a.id`, where, sortField, sortOrder)
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query adverts for export: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row struct {
+			model.Advert
+			PhotoURLs string `db:"photo_urls"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return err
+		}
+		var urls []string
+		if err := json.Unmarshal([]byte(row.PhotoURLs), &urls); err != nil {
+			return err
+		}
+		if err := fn(row.Advert, urls); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
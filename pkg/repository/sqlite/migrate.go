@@ -0,0 +1,83 @@
+// Package sqlite implements the repositories on SQLite through the pure-Go
+// modernc.org/sqlite driver, for small deployments without a Postgres server.
+// Open the database with repository.NewDb and db.driver "sqlite", then Migrate it.
+package sqlite
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate applies the pending up migrations of migrations/ in order. The version is
+// kept in schema_migrations, laid out like the table of golang-migrate.
+func Migrate(ctx context.Context, db *sqlx.DB) error {
+	if _, err := db.ExecContext(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL, dirty BOOLEAN NOT NULL);
+        CREATE UNIQUE INDEX IF NOT EXISTS version_unique ON schema_migrations (version);`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	var current int
+	if err := db.GetContext(ctx, &current, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	files, err := fs.Glob(migrations, "migrations/*.up.sql")
+	if err != nil {
+		return err
+	}
+	versions := make(map[int]string, len(files))
+	for _, file := range files {
+		version, err := strconv.Atoi(strings.SplitN(path.Base(file), "_", 2)[0])
+		if err != nil {
+			return fmt.Errorf("migration %s has no version: %w", file, err)
+		}
+		versions[version] = file
+	}
+	pending := make([]int, 0, len(versions))
+	for version := range versions {
+		if version > current {
+			pending = append(pending, version)
+		}
+	}
+	sort.Ints(pending)
+
+	for _, version := range pending {
+		if err := migrate(ctx, db, version, versions[version]); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", versions[version], err)
+		}
+	}
+	return nil
+}
+
+// migrate applies one migration file and records its version in the same transaction.
+func migrate(ctx context.Context, db *sqlx.DB, version int, file string) error {
+	script, err := migrations.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return repository.InTx(ctx, db, nil, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, FALSE)`, version); err != nil {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS saved_searches;
+DROP TABLE IF EXISTS favorites;
+DROP TABLE IF EXISTS photo_variants;
+DROP TABLE IF EXISTS photos;
+DROP TABLE IF EXISTS advert_translations;
+DROP TABLE IF EXISTS adverts;
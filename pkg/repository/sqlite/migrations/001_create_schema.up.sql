@@ -0,0 +1,119 @@
+-- The schema of migrations/ (Postgres) in one step, adapted to SQLite:
+-- timestamps are DATETIME text in UTC, prices REAL rounded to cents on write,
+-- webhook events are stored as a Postgres array literal ({"a","b"}).
+
+CREATE TABLE IF NOT EXISTS adverts (
+                         id             INTEGER PRIMARY KEY AUTOINCREMENT,
+                         name           TEXT NOT NULL,
+                         description    TEXT NOT NULL,
+                         price          REAL NOT NULL,
+                         created_at     DATETIME NOT NULL,
+                         owner_id       TEXT NOT NULL DEFAULT '',  -- '' = anonymous
+                         flag_reason    TEXT NOT NULL DEFAULT '',
+                         view_count     INTEGER NOT NULL DEFAULT 0,
+                         default_locale TEXT NOT NULL DEFAULT 'en'
+);
+
+CREATE INDEX IF NOT EXISTS idx_adverts_price ON adverts(price);
+CREATE INDEX IF NOT EXISTS idx_adverts_created_at ON adverts(created_at);
+CREATE INDEX IF NOT EXISTS idx_adverts_view_count ON adverts(view_count DESC, id);
+
+CREATE TABLE IF NOT EXISTS advert_translations (
+                                     advert_id   INTEGER NOT NULL REFERENCES adverts(id) ON DELETE CASCADE,
+                                     locale      TEXT NOT NULL, -- BCP 47 tag: en, ru, en-GB, ...
+                                     name        TEXT NOT NULL,
+                                     description TEXT NOT NULL,
+                                     PRIMARY KEY (advert_id, locale)
+);
+
+-- No UNIQUE (advert_id, position): SQLite cannot defer it while positions are shifted,
+-- the photo repository keeps them 1..n inside its transactions instead
+CREATE TABLE IF NOT EXISTS photos (
+                        id          INTEGER PRIMARY KEY AUTOINCREMENT,
+                        advert_id   INTEGER NOT NULL REFERENCES adverts(id) ON DELETE CASCADE,
+                        url         TEXT NOT NULL,
+                        position    INTEGER NOT NULL,                    -- 1 = main photo, 2,3… = gallery order
+                        status      TEXT NOT NULL DEFAULT 'unchecked',  -- unchecked | ok | broken
+                        check_error TEXT NOT NULL DEFAULT '',
+                        checked_at  DATETIME NULL,
+                        phash       INTEGER NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_photos_advert_id ON photos(advert_id, position);
+CREATE INDEX IF NOT EXISTS idx_photos_checked_at ON photos(checked_at);
+CREATE INDEX IF NOT EXISTS idx_photos_phash ON photos(phash) WHERE phash IS NOT NULL AND phash <> 0;
+
+CREATE TABLE IF NOT EXISTS photo_variants (
+                                photo_id INTEGER NOT NULL REFERENCES photos(id) ON DELETE CASCADE,
+                                name     TEXT NOT NULL,   -- thumb, medium, large…
+                                url      TEXT NOT NULL,
+                                width    INTEGER NOT NULL,
+                                height   INTEGER NOT NULL,
+                                PRIMARY KEY (photo_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS favorites (
+                           user_id      TEXT NOT NULL,
+                           advert_id    INTEGER NOT NULL,
+                           name         TEXT NOT NULL,      -- advert name when it was favorited
+                           price_at_add REAL NOT NULL,
+                           created_at   DATETIME NOT NULL,
+                           PRIMARY KEY (user_id, advert_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorites_advert_id ON favorites(advert_id);
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+                                id           INTEGER PRIMARY KEY AUTOINCREMENT,
+                                user_id      TEXT NOT NULL,
+                                email        TEXT NOT NULL DEFAULT '',       -- '' = no e-mail notifications
+                                name         TEXT NOT NULL,
+                                min_price    REAL NULL,
+                                max_price    REAL NULL,
+                                created_from DATETIME NULL,
+                                created_to   DATETIME NULL,                  -- exclusive
+                                sort         TEXT NOT NULL DEFAULT '',       -- list sort param, e.g. price_asc
+                                created_at   DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user_id ON saved_searches(user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+                                       id         INTEGER PRIMARY KEY AUTOINCREMENT,
+                                       url        TEXT NOT NULL,
+                                       secret     TEXT NOT NULL,   -- HMAC-SHA256 key of the payload signature
+                                       events     TEXT NOT NULL,   -- {"advert.created","advert.deleted"}
+                                       created_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+                                    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+                                    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+                                    event           TEXT NOT NULL,
+                                    payload         TEXT NOT NULL,
+                                    status          TEXT NOT NULL DEFAULT 'pending', -- pending | delivered | dead
+                                    attempts        INTEGER NOT NULL DEFAULT 0,
+                                    next_attempt_at DATETIME NOT NULL,
+                                    last_error      TEXT NOT NULL DEFAULT '',
+                                    response_code   INTEGER NOT NULL DEFAULT 0,
+                                    created_at      DATETIME NOT NULL,
+                                    delivered_at    DATETIME NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS outbox (
+                        id              INTEGER PRIMARY KEY AUTOINCREMENT,
+                        aggregate_id    INTEGER NOT NULL,  -- advert ID, no FK: deleted adverts keep their events
+                        event           TEXT NOT NULL,
+                        payload         TEXT NOT NULL,
+                        attempts        INTEGER NOT NULL DEFAULT 0,
+                        next_attempt_at DATETIME NOT NULL,
+                        last_error      TEXT NOT NULL DEFAULT '',
+                        created_at      DATETIME NOT NULL,
+                        published_at    DATETIME NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox(aggregate_id, id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_published_at ON outbox(published_at) WHERE published_at IS NOT NULL;
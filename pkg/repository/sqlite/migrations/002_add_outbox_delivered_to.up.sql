@@ -0,0 +1 @@
+ALTER TABLE outbox ADD COLUMN delivered_to TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type SQLiteOutboxRepo struct {
+	db *sqlx.DB
+	// relayMu serializes Relay, SQLite has no SKIP LOCKED
+	relayMu sync.Mutex
+}
+
+func NewSQLiteOutboxRepo(db *sqlx.DB) repository.OutboxRepo {
+	return &SQLiteOutboxRepo{db: db}
+}
+
+// insertOutbox records an advert event inside the transaction of the change.
+func insertOutbox(ctx context.Context, tx *sqlx.Tx, event string, advertID int, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO outbox (aggregate_id, event, payload, next_attempt_at, created_at) VALUES ($1, $2, $3, $4, $4)`,
+		advertID, event, string(body), now)
+	return err
+}
+
+// Relay passes the due events to fn outside of a transaction: publishers write to
+// the database themselves, which a transaction holding the SQLite write lock would
+// block. Concurrent relays are serialized instead of skipping each other's events.
+func (r *SQLiteOutboxRepo) Relay(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	fn func(e model.OutboxEvent) model.OutboxEvent,
+) (int, error) {
+	r.relayMu.Lock()
+	defer r.relayMu.Unlock()
+
+	// Only the head of every advert's queue is eligible: while it is failing,
+	// later events of the same advert wait, which keeps them in order.
+	var events []model.OutboxEvent
+	if err := r.db.SelectContext(ctx, &events, `
+        SELECT id, aggregate_id, event, payload, attempts, next_attempt_at, last_error, created_at, published_at, delivered_to
+          FROM outbox o
+         WHERE published_at IS NULL
+           AND next_attempt_at <= $1
+           AND NOT EXISTS (SELECT 1
+                             FROM outbox p
+                            WHERE p.aggregate_id = o.aggregate_id
+                              AND p.published_at IS NULL
+                              AND p.id < o.id)
+      ORDER BY id
+         LIMIT $2`, now.UTC(), limit); err != nil {
+		return 0, err
+	}
+
+	for _, e := range events {
+		e = fn(e)
+		if _, err := r.db.ExecContext(ctx, `
+            UPDATE outbox
+               SET attempts = $1,
+                   next_attempt_at = $2,
+                   last_error = $3,
+                   published_at = $4,
+                   delivered_to = $5
+             WHERE id = $6`,
+			e.Attempts, e.NextAttemptAt.UTC(), e.LastError, utcPtr(e.PublishedAt), e.DeliveredTo, e.ID); err != nil {
+			return 0, err
+		}
+	}
+	return len(events), nil
+}
+
+func (r *SQLiteOutboxRepo) Lag(ctx context.Context) (model.OutboxLag, error) {
+	var lag model.OutboxLag
+	if err := r.db.GetContext(ctx, &lag.Pending, `SELECT COUNT(*) FROM outbox WHERE published_at IS NULL`); err != nil {
+		return lag, err
+	}
+	if lag.Pending == 0 {
+		return lag, nil
+	}
+	// MIN() would lose the DATETIME column type the driver parses times by
+	var oldest time.Time
+	if err := r.db.GetContext(ctx, &oldest, `
+        SELECT created_at
+          FROM outbox
+         WHERE published_at IS NULL
+      ORDER BY created_at
+         LIMIT 1`); err != nil {
+		return lag, err
+	}
+	lag.OldestAge = time.Since(oldest).Seconds()
+	return lag, nil
+}
+
+func (r *SQLiteOutboxRepo) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM outbox WHERE published_at < $1`, before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,403 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// photoColumns lists the columns scanned into model.Photo
+const photoColumns = `id, advert_id, url, position, status, check_error, checked_at, phash`
+
+type SQLitePhotoRepo struct {
+	db *sqlx.DB
+}
+
+func NewSQLitePhotoRepo(db *sqlx.DB) repository.PhotoRepo {
+	return &SQLitePhotoRepo{db: db}
+}
+
+func (r *SQLitePhotoRepo) Create(ctx context.Context, photo model.Photo) error {
+	query := `
+        INSERT INTO photos (advert_id, url, position)
+        VALUES ($1, $2, $3)
+    `
+	_, err := r.db.ExecContext(ctx, query, photo.AdvertID, photo.URL, photo.Position)
+	return err
+}
+
+func (r *SQLitePhotoRepo) GetMainPhotoURL(ctx context.Context, advertID int) (string, error) {
+	var url string
+	err := r.db.GetContext(
+		ctx, &url,
+		`
+        SELECT url
+          FROM photos
+         WHERE advert_id = $1
+           AND status <> 'broken'
+      ORDER BY position
+         LIMIT 1`, advertID,
+	)
+	return url, err
+}
+
+func (r *SQLitePhotoRepo) GetAllPhotoURLs(ctx context.Context, advertID int) ([]string, error) {
+	var urls []string
+	err := r.db.SelectContext(
+		ctx, &urls,
+		`
+        SELECT url
+          FROM photos
+         WHERE advert_id = $1
+      ORDER BY position`, advertID,
+	)
+	return urls, err
+}
+
+func (r *SQLitePhotoRepo) DeleteByAdvertID(ctx context.Context, advertID int) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM photos WHERE advert_id = $1`, advertID); err != nil {
+		return fmt.Errorf("failed to delete photos for advert %d: %w", advertID, err)
+	}
+	return nil
+}
+
+func (r *SQLitePhotoRepo) ListByAdvertID(ctx context.Context, advertID int) ([]model.Photo, error) {
+	byAdvert, err := r.ListByAdvertIDs(ctx, []int{advertID})
+	if err != nil {
+		return nil, err
+	}
+	if photos := byAdvert[advertID]; photos != nil {
+		return photos, nil
+	}
+	return []model.Photo{}, nil
+}
+
+func (r *SQLitePhotoRepo) ListByAdvertIDs(ctx context.Context, advertIDs []int) (map[int][]model.Photo, error) {
+	ids := idList(advertIDs)
+
+	var photos []model.Photo
+	err := r.db.SelectContext(
+		ctx, &photos,
+		`
+        SELECT `+photoColumns+`
+          FROM photos
+         WHERE advert_id IN (SELECT value FROM json_each($1))
+      ORDER BY advert_id, position`, ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var variants []model.PhotoVariant
+	err = r.db.SelectContext(
+		ctx, &variants,
+		`
+        SELECT v.photo_id, v.name, v.url, v.width, v.height
+          FROM photo_variants v
+          JOIN photos p ON p.id = v.photo_id
+         WHERE p.advert_id IN (SELECT value FROM json_each($1))`, ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+	byPhoto := make(map[int]map[string]string, len(photos))
+	for _, v := range variants {
+		if byPhoto[v.PhotoID] == nil {
+			byPhoto[v.PhotoID] = make(map[string]string)
+		}
+		byPhoto[v.PhotoID][v.Name] = v.URL
+	}
+	byAdvert := make(map[int][]model.Photo, len(advertIDs))
+	for _, p := range photos {
+		p.Variants = byPhoto[p.ID]
+		byAdvert[p.AdvertID] = append(byAdvert[p.AdvertID], p)
+	}
+	return byAdvert, nil
+}
+
+func (r *SQLitePhotoRepo) Insert(ctx context.Context, photo model.Photo, maxPhotos int) (model.Photo, error) {
+	err := r.inTx(ctx, photo.AdvertID, func(tx *sqlx.Tx) error {
+		var count int
+		if err := tx.GetContext(ctx, &count, `SELECT COUNT(*) FROM photos WHERE advert_id = $1`, photo.AdvertID); err != nil {
+			return err
+		}
+		if count >= maxPhotos {
+			return repository.ErrPhotoCount
+		}
+		if photo.Position < 1 || photo.Position > count+1 {
+			photo.Position = count + 1
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+        UPDATE photos
+           SET position = position + 1
+         WHERE advert_id = $1
+           AND position >= $2`, photo.AdvertID, photo.Position); err != nil {
+			return err
+		}
+		return tx.QueryRowxContext(ctx, `
+        INSERT INTO photos (advert_id, url, position)
+        VALUES ($1, $2, $3)
+     RETURNING id, status`, photo.AdvertID, photo.URL, photo.Position).Scan(&photo.ID, &photo.Status)
+	})
+	if err != nil {
+		return model.Photo{}, fmt.Errorf("failed to insert photo for advert %d: %w", photo.AdvertID, err)
+	}
+	return photo, nil
+}
+
+func (r *SQLitePhotoRepo) Delete(ctx context.Context, advertID, photoID int) error {
+	err := r.inTx(ctx, advertID, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+        DELETE
+          FROM photos
+         WHERE id = $1
+           AND advert_id = $2`, photoID, advertID)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return sql.ErrNoRows
+		}
+		var left int
+		if err := tx.GetContext(ctx, &left, `SELECT COUNT(*) FROM photos WHERE advert_id = $1`, advertID); err != nil {
+			return err
+		}
+		if left == 0 {
+			return repository.ErrPhotoCount
+		}
+		return compactPositions(ctx, tx, advertID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete photo %d of advert %d: %w", photoID, advertID, err)
+	}
+	return nil
+}
+
+func (r *SQLitePhotoRepo) Reorder(ctx context.Context, advertID int, photoIDs []int) error {
+	err := r.inTx(ctx, advertID, func(tx *sqlx.Tx) error {
+		var count int
+		if err := tx.GetContext(ctx, &count, `SELECT COUNT(*) FROM photos WHERE advert_id = $1`, advertID); err != nil {
+			return err
+		}
+
+		// json_each numbers the array elements from 0 in key
+		res, err := tx.ExecContext(ctx, `
+        UPDATE photos
+           SET position = o.key + 1
+          FROM json_each($2) AS o
+         WHERE photos.id = o.value
+           AND photos.advert_id = $1`, advertID, idList(photoIDs))
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if int(affected) != count || len(photoIDs) != count {
+			return fmt.Errorf("photo ids do not match the %d photos of the advert", count)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reorder photos of advert %d: %w", advertID, err)
+	}
+	return nil
+}
+
+func (r *SQLitePhotoRepo) SaveVariant(ctx context.Context, variant model.PhotoVariant) error {
+	query := `
+        INSERT INTO photo_variants (photo_id, name, url, width, height)
+        VALUES ($1, $2, $3, $4, $5)
+   ON CONFLICT (photo_id, name)
+     DO UPDATE SET url = excluded.url,
+                   width = excluded.width,
+                   height = excluded.height
+    `
+	if _, err := r.db.ExecContext(ctx, query, variant.PhotoID, variant.Name, variant.URL, variant.Width, variant.Height); err != nil {
+		return fmt.Errorf("failed to save %s variant of photo %d: %w", variant.Name, variant.PhotoID, err)
+	}
+	return nil
+}
+
+func (r *SQLitePhotoRepo) GetMainPhotoVariantURL(ctx context.Context, advertID int, name string) (string, error) {
+	var url string
+	err := r.db.GetContext(
+		ctx, &url,
+		`
+        SELECT COALESCE(
+                   (SELECT v.url
+                      FROM photo_variants v
+                     WHERE v.photo_id = p.id
+                       AND v.name = $2), '')
+          FROM photos p
+         WHERE p.advert_id = $1
+           AND p.status <> 'broken'
+      ORDER BY p.position
+         LIMIT 1`, advertID, name,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return url, err
+}
+
+func (r *SQLitePhotoRepo) ListMissingVariants(ctx context.Context, urlPrefix string, names []string, afterID, limit int) ([]model.Photo, error) {
+	if names == nil {
+		names = []string{}
+	}
+	list, _ := json.Marshal(names)
+	photos := []model.Photo{}
+	err := r.db.SelectContext(
+		ctx, &photos,
+		`
+        SELECT `+photoColumns+`
+          FROM photos p
+         WHERE p.id > $1
+           AND substr(p.url, 1, length($2)) = $2
+           AND (SELECT COUNT(*)
+                  FROM photo_variants v
+                 WHERE v.photo_id = p.id
+                   AND v.name IN (SELECT value FROM json_each($3))) < $4
+      ORDER BY p.id
+         LIMIT $5`, afterID, urlPrefix, string(list), len(names), limit,
+	)
+	return photos, err
+}
+
+func (r *SQLitePhotoRepo) ListDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]model.Photo, error) {
+	photos := []model.Photo{}
+	err := r.db.SelectContext(
+		ctx, &photos,
+		`
+        SELECT `+photoColumns+`
+          FROM photos
+         WHERE checked_at IS NULL
+            OR checked_at < $1
+      ORDER BY checked_at NULLS FIRST, id
+         LIMIT $2`, checkedBefore.UTC(), limit,
+	)
+	return photos, err
+}
+
+func (r *SQLitePhotoRepo) SetCheckResult(ctx context.Context, photoID int, status, checkError string, checkedAt time.Time) error {
+	query := `
+        UPDATE photos
+           SET status = $1,
+               check_error = $2,
+               checked_at = $3
+         WHERE id = $4
+    `
+	if _, err := r.db.ExecContext(ctx, query, status, checkError, checkedAt.UTC(), photoID); err != nil {
+		return fmt.Errorf("failed to save check result of photo %d: %w", photoID, err)
+	}
+	return nil
+}
+
+func (r *SQLitePhotoRepo) ListUnhashed(ctx context.Context, limit int) ([]model.Photo, error) {
+	photos := []model.Photo{}
+	err := r.db.SelectContext(
+		ctx, &photos,
+		`
+        SELECT `+photoColumns+`
+          FROM photos
+         WHERE phash IS NULL
+           AND status = 'ok'
+      ORDER BY id
+         LIMIT $1`, limit,
+	)
+	return photos, err
+}
+
+func (r *SQLitePhotoRepo) SetHash(ctx context.Context, photoID int, hash int64) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE photos SET phash = $1 WHERE id = $2`, hash, photoID); err != nil {
+		return fmt.Errorf("failed to save hash of photo %d: %w", photoID, err)
+	}
+	return nil
+}
+
+func (r *SQLitePhotoRepo) ListHashDuplicates(ctx context.Context, limit int) ([]model.DuplicatePhoto, error) {
+	photos := []model.DuplicatePhoto{}
+	err := r.db.SelectContext(
+		ctx, &photos,
+		`
+          WITH shared AS (
+                SELECT p.phash
+                  FROM photos p
+                  JOIN adverts a ON a.id = p.advert_id
+                 WHERE p.phash IS NOT NULL
+                   AND p.phash <> 0
+              GROUP BY p.phash
+                HAVING COUNT(DISTINCT COALESCE(NULLIF(a.owner_id, ''), 'advert:' || a.id)) > 1
+              ORDER BY p.phash
+                 LIMIT $1)
+        SELECT p.phash, p.id AS photo_id, p.url, a.id AS advert_id, a.owner_id, a.flag_reason
+          FROM photos p
+          JOIN adverts a ON a.id = p.advert_id
+          JOIN shared s ON s.phash = p.phash
+      ORDER BY p.phash, a.id, p.id`, limit,
+	)
+	return photos, err
+}
+
+func (r *SQLitePhotoRepo) HashUsedByOtherOwner(ctx context.Context, hash int64, ownerID string, advertID int) (bool, error) {
+	var used bool
+	err := r.db.GetContext(
+		ctx, &used,
+		`
+        SELECT EXISTS (
+                SELECT 1
+                  FROM photos p
+                  JOIN adverts a ON a.id = p.advert_id
+                 WHERE p.phash = $1
+                   AND a.id <> $3
+                   AND ($2 = '' OR a.owner_id = '' OR a.owner_id <> $2))`, hash, ownerID, advertID,
+	)
+	return used, err
+}
+
+// inTx runs fn in a transaction on an existing advert. The transactions are
+// immediate (_txlock in repository.DSN), so they take the database write lock
+// up front and concurrent position changes are serialized. The change is
+// recorded in the outbox as an update of the advert.
+func (r *SQLitePhotoRepo) inTx(ctx context.Context, advertID int, fn func(tx *sqlx.Tx) error) error {
+	return repository.InTx(ctx, r.db, nil, func(tx *sqlx.Tx) error {
+		var ad model.Advert
+		if err := tx.GetContext(ctx, &ad, `
+        SELECT `+advertColumns+`
+          FROM adverts
+         WHERE id = $1`, advertID); err != nil {
+			return err
+		}
+		if err := fn(tx); err != nil {
+			return err
+		}
+		if err := tx.SelectContext(ctx, &ad.PhotoURLs, `SELECT url FROM photos WHERE advert_id = $1 ORDER BY position`, advertID); err != nil {
+			return err
+		}
+		return insertOutbox(ctx, tx, model.EventAdvertUpdated, advertID, ad)
+	})
+}
+
+// compactPositions renumbers the photos of the advert to 1..n keeping their order.
+func compactPositions(ctx context.Context, tx *sqlx.Tx, advertID int) error {
+	_, err := tx.ExecContext(ctx, `
+        UPDATE photos
+           SET position = r.rn
+          FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY position, id) AS rn
+                  FROM photos
+                 WHERE advert_id = $1) AS r
+         WHERE photos.id = r.id
+           AND photos.position <> r.rn`, advertID)
+	return err
+}
@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type SQLiteStatsRepo struct {
+	db *sqlx.DB
+}
+
+func NewSQLiteStatsRepo(db *sqlx.DB) repository.StatsRepo {
+	return &SQLiteStatsRepo{db: db}
+}
+
+// PriceStats computes the median like PERCENTILE_CONT(0.5) in a second query,
+// averaging the one or two middle prices.
+func (r *SQLiteStatsRepo) PriceStats(ctx context.Context, filter model.AdvertFilter) (model.PriceStats, error) {
+	var stats model.PriceStats
+	where, args := filterClause(filter, "", 1)
+	query := fmt.Sprintf(`
+        SELECT COUNT(*) AS count,
+               MIN(price) AS min,
+               MAX(price) AS max,
+               AVG(price) AS avg
+          FROM adverts
+         %s`, where)
+	if err := r.db.GetContext(ctx, &stats, query, args...); err != nil || stats.Count == 0 {
+		return stats, err
+	}
+
+	where, args = filterClause(filter, "", 3)
+	query = fmt.Sprintf(`
+        SELECT AVG(price)
+          FROM (SELECT price
+                  FROM adverts
+                 %s
+              ORDER BY price
+                 LIMIT $1 OFFSET $2)`, where)
+	middle := 2 - stats.Count%2
+	err := r.db.GetContext(ctx, &stats.Median, query, append([]interface{}{middle, (stats.Count - 1) / 2}, args...)...)
+	return stats, err
+}
+
+func (r *SQLiteStatsRepo) PriceHistogram(ctx context.Context, filter model.AdvertFilter, bounds []float64) ([]int64, error) {
+	if bounds == nil {
+		bounds = []float64{}
+	}
+	list, err := json.Marshal(bounds)
+	if err != nil {
+		return nil, err
+	}
+	where, args := filterClause(filter, "a", 2)
+	// The number of bounds not above the price is 0 below the first bound and i for
+	// [bounds[i-1], bounds[i]), like WIDTH_BUCKET
+	query := fmt.Sprintf(`
+        SELECT (SELECT COUNT(*) FROM json_each($1) b WHERE b.value <= a.price) AS bucket, COUNT(*) AS count
+          FROM adverts a
+         %s
+      GROUP BY 1`, where)
+	rows, err := r.db.QueryxContext(ctx, query, append([]interface{}{string(list)}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]int64, len(bounds)+1)
+	for rows.Next() {
+		var bucket int
+		var count int64
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, err
+		}
+		counts[bucket] = count
+	}
+	return counts, rows.Err()
+}
+
+func (r *SQLiteStatsRepo) CreatedPerDay(ctx context.Context, filter model.AdvertFilter) ([]model.DayCount, error) {
+	where, args := filterClause(filter, "", 1)
+	query := fmt.Sprintf(`
+        SELECT date(created_at) AS day, COUNT(*) AS count
+          FROM adverts
+         %s
+      GROUP BY 1
+      ORDER BY 1`, where)
+	var rows []struct {
+		Day   string `db:"day"`
+		Count int64  `db:"count"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	days := make([]model.DayCount, 0, len(rows))
+	for _, row := range rows {
+		day, err := time.Parse(time.DateOnly, row.Day)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected day %q: %w", row.Day, err)
+		}
+		days = append(days, model.DayCount{Day: day, Count: row.Count})
+	}
+	return days, nil
+}
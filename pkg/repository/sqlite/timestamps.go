@@ -0,0 +1,16 @@
+package sqlite
+
+import "time"
+
+// Timestamps are stored as text (_time_format=sqlite in repository.DSN) and
+// compared as strings, which orders them correctly only if all of them are in
+// one zone, so every time is converted to UTC before it is written or compared.
+
+// utcPtr converts an optional time to UTC, keeping nil.
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
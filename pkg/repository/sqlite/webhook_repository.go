@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// webhookDeliveryColumns lists the columns scanned into model.WebhookDelivery
+const webhookDeliveryColumns = `id, subscription_id, event, payload, status, attempts, next_attempt_at,
+               last_error, response_code, created_at, delivered_at`
+
+type SQLiteWebhookRepo struct {
+	db *sqlx.DB
+}
+
+func NewSQLiteWebhookRepo(db *sqlx.DB) repository.WebhookRepo {
+	return &SQLiteWebhookRepo{db: db}
+}
+
+// CreateSubscription stores the events as the array literal of pq.StringArray,
+// which scans them back the same way.
+func (r *SQLiteWebhookRepo) CreateSubscription(ctx context.Context, sub model.WebhookSubscription) (int, error) {
+	var id int
+	err := r.db.QueryRowContext(
+		ctx,
+		`INSERT INTO webhook_subscriptions (url, secret, events, created_at)
+         VALUES ($1, $2, $3, $4)
+         RETURNING id`,
+		sub.URL, sub.Secret, sub.Events, sub.CreatedAt.UTC(),
+	).Scan(&id)
+	return id, err
+}
+
+func (r *SQLiteWebhookRepo) ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	subs := []model.WebhookSubscription{}
+	err := r.db.SelectContext(ctx, &subs, `
+        SELECT id, url, events, created_at
+          FROM webhook_subscriptions
+      ORDER BY id`)
+	return subs, err
+}
+
+func (r *SQLiteWebhookRepo) DeleteSubscription(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *SQLiteWebhookRepo) EnqueueEvent(ctx context.Context, event, payload string) (int, error) {
+	now := time.Now().UTC()
+	// pq.StringArray quotes every element: {"advert.created","advert.deleted"}
+	res, err := r.db.ExecContext(ctx, `
+        INSERT INTO webhook_deliveries (subscription_id, event, payload, next_attempt_at, created_at)
+        SELECT id, $1, $2, $3, $3
+          FROM webhook_subscriptions
+         WHERE INSTR(events, '"' || $1 || '"') > 0`, event, payload, now)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (r *SQLiteWebhookRepo) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]model.WebhookDelivery, map[int]model.WebhookSubscription, error) {
+	// A single UPDATE takes the write lock, so concurrent dispatchers claim in turn
+	deliveries := []model.WebhookDelivery{}
+	err := r.db.SelectContext(ctx, &deliveries, `
+        UPDATE webhook_deliveries
+           SET next_attempt_at = $2
+         WHERE id IN (SELECT id
+                        FROM webhook_deliveries
+                       WHERE status = 'pending'
+                         AND next_attempt_at <= $1
+                    ORDER BY next_attempt_at
+                       LIMIT $3)
+     RETURNING `+webhookDeliveryColumns, now.UTC(), now.Add(lease).UTC(), limit)
+	if err != nil || len(deliveries) == 0 {
+		return deliveries, nil, err
+	}
+
+	ids := make([]int, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, d.SubscriptionID)
+	}
+	var subs []model.WebhookSubscription
+	if err := r.db.SelectContext(ctx, &subs, `
+        SELECT id, url, secret, events, created_at
+          FROM webhook_subscriptions
+         WHERE id IN (SELECT value FROM json_each($1))`, idList(ids)); err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[int]model.WebhookSubscription, len(subs))
+	for _, s := range subs {
+		byID[s.ID] = s
+	}
+	return deliveries, byID, nil
+}
+
+func (r *SQLiteWebhookRepo) UpdateDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx, `
+        UPDATE webhook_deliveries
+           SET status = $1,
+               attempts = $2,
+               next_attempt_at = $3,
+               last_error = $4,
+               response_code = $5,
+               delivered_at = $6
+         WHERE id = $7`,
+		d.Status, d.Attempts, d.NextAttemptAt.UTC(), d.LastError, d.ResponseCode, utcPtr(d.DeliveredAt), d.ID)
+	return err
+}
+
+func (r *SQLiteWebhookRepo) ListDeliveries(ctx context.Context, subscriptionID int, status string, limit int) ([]model.WebhookDelivery, error) {
+	deliveries := []model.WebhookDelivery{}
+	err := r.db.SelectContext(ctx, &deliveries, `
+        SELECT `+webhookDeliveryColumns+`
+          FROM webhook_deliveries
+         WHERE subscription_id = $1
+           AND ($2 = '' OR status = $2)
+      ORDER BY id DESC
+         LIMIT $3`, subscriptionID, status, limit)
+	return deliveries, err
+}
+
+func (r *SQLiteWebhookRepo) RetryDelivery(ctx context.Context, subscriptionID int, deliveryID int64) error {
+	res, err := r.db.ExecContext(ctx, `
+        UPDATE webhook_deliveries
+           SET status = 'pending',
+               attempts = 0,
+               next_attempt_at = $3
+         WHERE id = $1
+           AND subscription_id = $2
+           AND status <> 'delivered'`, deliveryID, subscriptionID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
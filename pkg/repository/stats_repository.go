@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+type StatsRepo interface {
+	// PriceStats returns the count and min/max/avg/median price of the filtered adverts
+	PriceStats(ctx context.Context, filter model.AdvertFilter) (model.PriceStats, error)
+	// PriceHistogram counts the filtered adverts per price bucket. bounds must be ascending;
+	// the result has len(bounds)+1 items: below bounds[0], [bounds[i], bounds[i+1]) and from the last bound up
+	PriceHistogram(ctx context.Context, filter model.AdvertFilter, bounds []float64) ([]int64, error)
+	// CreatedPerDay counts the filtered adverts per creation day, days without adverts are omitted
+	CreatedPerDay(ctx context.Context, filter model.AdvertFilter) ([]model.DayCount, error)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+type WebhookRepo interface {
+	// CreateSubscription stores a subscription and returns its ID
+	CreateSubscription(ctx context.Context, sub model.WebhookSubscription) (int, error)
+	// ListSubscriptions returns all subscriptions without their secrets
+	ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	// DeleteSubscription removes a subscription with its deliveries, sql.ErrNoRows if there is none
+	DeleteSubscription(ctx context.Context, id int) error
+
+	// EnqueueEvent creates a pending delivery for every subscription to the event
+	// and returns how many were created
+	EnqueueEvent(ctx context.Context, event, payload string) (int, error)
+	// ClaimDue returns up to limit pending deliveries due before now together with the
+	// subscriptions, postponing them by lease so that concurrent dispatchers skip them
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, map[int]model.WebhookSubscription, error)
+	// UpdateDelivery saves the outcome of an attempt (status, attempts, next attempt, error, response)
+	UpdateDelivery(ctx context.Context, d model.WebhookDelivery) error
+	// ListDeliveries returns the latest deliveries of a subscription, optionally by status
+	ListDeliveries(ctx context.Context, subscriptionID int, status string, limit int) ([]model.WebhookDelivery, error)
+	// RetryDelivery makes an undelivered delivery of the subscription pending again right away
+	// with a fresh retry budget, sql.ErrNoRows if there is none
+	RetryDelivery(ctx context.Context, subscriptionID int, deliveryID int64) error
+}
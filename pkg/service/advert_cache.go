@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// AdvertCache stores encoded advert reads for the caching AdvertService.
+// The in-memory LRU of NewLRUAdvertCache is the default; an external cache
+// (Redis, Memcached) shared by the replicas can be plugged in instead.
+// Implementations must be safe for concurrent use.
+type AdvertCache interface {
+	// Get returns the value stored under key and whether there is one
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl, 0 keeps it until it is evicted
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// AdvertCacheMetrics describes how the advert cache performs since the start of the process.
+type AdvertCacheMetrics struct {
+	// Hits and Misses count GetByID and List calls served from the cache or not
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+	// Shared counts misses that waited for the same read of a concurrent call
+	// instead of querying the repositories again
+	Shared uint64 `json:"shared"`
+	// Errors counts failed cache operations, the calls fell back to the repositories
+	Errors uint64 `json:"errors"`
+}
+
+// AdvertInvalidator is told about changes to adverts made around the AdvertService
+// (favorites, views, photo variants and checks), so that cached reads of them are dropped.
+type AdvertInvalidator interface {
+	// Invalidate drops the cached reads of the adverts and all list pages.
+	Invalidate(ctx context.Context, advertIDs ...int)
+}
+
+// NoInvalidation is the AdvertInvalidator to use when adverts are not cached.
+var NoInvalidation AdvertInvalidator = noInvalidation{}
+
+type noInvalidation struct{}
+
+func (noInvalidation) Invalidate(context.Context, ...int) {}
+
+// CachingAdvertService is an AdvertService caching GetByID and List.
+type CachingAdvertService interface {
+	AdvertService
+	AdvertInvalidator
+
+	// Run invalidates the adverts announced on the change feed until ctx is cancelled,
+	// so that changes made on other replicas or to photos are not served stale.
+	Run(ctx context.Context)
+
+	// Metrics returns the hit/miss counters of the cache.
+	Metrics() AdvertCacheMetrics
+}
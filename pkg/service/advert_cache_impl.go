@@ -0,0 +1,268 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// lruAdvertCache is an in-memory AdvertCache evicting the least recently used entries.
+type lruAdvertCache struct {
+	size int
+
+	mu      sync.Mutex
+	order   *list.List // of *lruEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time // zero = never
+}
+
+// NewLRUAdvertCache creates an in-memory AdvertCache holding up to size entries.
+func NewLRUAdvertCache(size int) AdvertCache {
+	return &lruAdvertCache{
+		size:    max(size, 1),
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *lruAdvertCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := el.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(el)
+	return entry.value, true, nil
+}
+
+func (c *lruAdvertCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value = &lruEntry{key: key, value: value, expires: expires}
+		c.order.MoveToFront(el)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+	return nil
+}
+
+// Cache keys of the versions. Entries are keyed by the version of the data they
+// were read from, so bumping a version invalidates all entries of an advert (any
+// fields and locales) or all list pages at once, also in a cache shared by replicas.
+const (
+	advertVersionKey = "advert:%d:version"
+	listVersionKey   = "adverts:version"
+)
+
+// cachingAdvertService caches the reads of the wrapped AdvertService.
+type cachingAdvertService struct {
+	AdvertService
+	cache AdvertCache
+	ttl   time.Duration
+	feed  repository.ChangeFeed
+	group singleflight.Group
+
+	hits, misses, shared, errors atomic.Uint64
+}
+
+// NewCachingAdvertService wraps next so that GetByID and List are served from cache for ttl.
+// Create, Update and Delete through it invalidate the affected entries at once, Run
+// invalidates the adverts changed elsewhere as they come through feed (advert and photo
+// changes of every replica). Favorites, views and photo processing call Invalidate;
+// with a per-replica cache those made on another replica show up once the entries expire.
+// Concurrent misses of the same key share a single call of next.
+func NewCachingAdvertService(next AdvertService, cache AdvertCache, ttl time.Duration, feed repository.ChangeFeed) CachingAdvertService {
+	return &cachingAdvertService{AdvertService: next, cache: cache, ttl: ttl, feed: feed}
+}
+
+func (s *cachingAdvertService) GetByID(ctx context.Context, id int, fields AdvertFields, locales ...string) (AdvertDetail, error) {
+	return cached(ctx, s, fmt.Sprintf(advertVersionKey, id), func(version string) string {
+		return fmt.Sprintf("advert:%d:%s:%s:%s", id, version, fieldsKey(fields), strings.Join(locales, ","))
+	}, func(ctx context.Context) (AdvertDetail, error) {
+		return s.AdvertService.GetByID(ctx, id, fields, locales...)
+	})
+}
+
+func (s *cachingAdvertService) List(
+	ctx context.Context,
+	filter model.AdvertFilter,
+	page int,
+	sortField, sortOrder string,
+	fields AdvertFields,
+	locales ...string,
+) ([]AdvertDetail, error) {
+	return cached(ctx, s, listVersionKey, func(version string) string {
+		return fmt.Sprintf("adverts:%s:%s:%d:%s:%s:%s:%s", version, filterKey(filter), page,
+			sortField, sortOrder, fieldsKey(fields), strings.Join(locales, ","))
+	}, func(ctx context.Context) ([]AdvertDetail, error) {
+		return s.AdvertService.List(ctx, filter, page, sortField, sortOrder, fields, locales...)
+	})
+}
+
+func (s *cachingAdvertService) Create(ctx context.Context, input CreateAdvertInput) (int, error) {
+	id, err := s.AdvertService.Create(ctx, input)
+	if err == nil {
+		s.bump(ctx, listVersionKey)
+	}
+	return id, err
+}
+
+// Update and Delete invalidate even when they fail, as the change may be partly applied.
+func (s *cachingAdvertService) Update(ctx context.Context, id int, input UpdateAdvertInput) error {
+	err := s.AdvertService.Update(ctx, id, input)
+	s.bump(ctx, fmt.Sprintf(advertVersionKey, id))
+	s.bump(ctx, listVersionKey)
+	return err
+}
+
+func (s *cachingAdvertService) Delete(ctx context.Context, id int) error {
+	err := s.AdvertService.Delete(ctx, id)
+	s.bump(ctx, fmt.Sprintf(advertVersionKey, id))
+	s.bump(ctx, listVersionKey)
+	return err
+}
+
+func (s *cachingAdvertService) Invalidate(ctx context.Context, advertIDs ...int) {
+	for _, id := range advertIDs {
+		s.bump(ctx, fmt.Sprintf(advertVersionKey, id))
+	}
+	s.bump(ctx, listVersionKey)
+}
+
+func (s *cachingAdvertService) Run(ctx context.Context) {
+	listenChanges(ctx, s.feed, "advert cache", func(change AdvertChange) {
+		s.Invalidate(ctx, change.AdvertID)
+	})
+}
+
+func (s *cachingAdvertService) Metrics() AdvertCacheMetrics {
+	metrics := AdvertCacheMetrics{
+		Hits:   s.hits.Load(),
+		Misses: s.misses.Load(),
+		Shared: s.shared.Load(),
+		Errors: s.errors.Load(),
+	}
+	if total := metrics.Hits + metrics.Misses; total > 0 {
+		metrics.HitRatio = float64(metrics.Hits) / float64(total)
+	}
+	return metrics
+}
+
+// cached returns the entry keyed by key at the current version of versionKey, loading
+// and storing it with load on a miss. Entries are stored as JSON, so callers never
+// share the slices of a cached value. Cache failures fall back to load.
+func cached[T any](
+	ctx context.Context,
+	s *cachingAdvertService,
+	versionKey string,
+	key func(version string) string,
+	load func(ctx context.Context) (T, error),
+) (T, error) {
+	var value T
+	version, err := s.version(ctx, versionKey)
+	if err != nil {
+		s.errors.Add(1)
+		return load(ctx)
+	}
+	entryKey := key(version)
+	if body, ok, err := s.cache.Get(ctx, entryKey); err != nil {
+		s.errors.Add(1)
+	} else if ok && json.Unmarshal(body, &value) == nil {
+		s.hits.Add(1)
+		return value, nil
+	}
+	s.misses.Add(1)
+
+	// The shared load must not fail for everybody when the first caller goes away
+	loaded := false
+	body, err, _ := s.group.Do(entryKey, func() (interface{}, error) {
+		loaded = true
+		ctx := context.WithoutCancel(ctx)
+		fresh, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		body, err := json.Marshal(fresh)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.cache.Set(ctx, entryKey, body, s.ttl); err != nil {
+			s.errors.Add(1)
+		}
+		return body, nil
+	})
+	if !loaded {
+		s.shared.Add(1)
+	}
+	if err != nil {
+		return value, err
+	}
+	err = json.Unmarshal(body.([]byte), &value)
+	return value, err
+}
+
+// version returns the current version of the entries under key, starting a new one if the
+// cache has none. Versions are never reused, so an evicted version cannot revive old entries.
+func (s *cachingAdvertService) version(ctx context.Context, key string) (string, error) {
+	version, ok, err := s.cache.Get(ctx, key)
+	if err != nil || ok {
+		return string(version), err
+	}
+	return s.newVersion(ctx, key)
+}
+
+func (s *cachingAdvertService) newVersion(ctx context.Context, key string) (string, error) {
+	version := strconv.FormatInt(time.Now().UnixNano(), 36)
+	return version, s.cache.Set(ctx, key, []byte(version), 0)
+}
+
+// bump invalidates the entries under the version key.
+func (s *cachingAdvertService) bump(ctx context.Context, key string) {
+	if _, err := s.newVersion(context.WithoutCancel(ctx), key); err != nil {
+		s.errors.Add(1)
+	}
+}
+
+// fieldsKey renders a field set in a stable order.
+func fieldsKey(fields AdvertFields) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,174 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository/memory"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLRUAdvertCache(t *testing.T) {
+	ctx := context.Background()
+	cache := service.NewLRUAdvertCache(2)
+
+	assert.NoError(t, cache.Set(ctx, "a", []byte("1"), 0))
+	assert.NoError(t, cache.Set(ctx, "b", []byte("2"), 0))
+	// Reading a makes b the least recently used
+	_, ok, _ := cache.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.NoError(t, cache.Set(ctx, "c", []byte("3"), 0))
+
+	_, ok, _ = cache.Get(ctx, "b")
+	assert.False(t, ok)
+	value, ok, _ := cache.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	assert.NoError(t, cache.Set(ctx, "d", []byte("4"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+	_, ok, _ = cache.Get(ctx, "d")
+	assert.False(t, ok)
+}
+
+func TestCachingAdvertService_GetByID(t *testing.T) {
+	ctx := context.Background()
+	next := new(mocks.MockAdvertService)
+	svc := service.NewCachingAdvertService(next, service.NewLRUAdvertCache(100), time.Minute, nil)
+
+	detail := service.AdvertDetail{
+		AdvertSummary: service.AdvertSummary{ID: 1, Name: "Bike", Price: 100},
+		AllPhotosURLs: []string{"http://img1"},
+	}
+	next.On("GetByID", mock.Anything, 1, service.AllAdvertFields, []string{"en"}).Return(detail, nil).Twice()
+	next.On("Update", mock.Anything, 1, mock.Anything).Return(nil).Once()
+
+	for i := 0; i < 3; i++ {
+		got, err := svc.GetByID(ctx, 1, service.AllAdvertFields, "en")
+		assert.NoError(t, err)
+		assert.Equal(t, detail, got)
+	}
+	// The update invalidates the advert, the next read reaches the wrapped service
+	assert.NoError(t, svc.Update(ctx, 1, service.UpdateAdvertInput{}))
+	_, err := svc.GetByID(ctx, 1, service.AllAdvertFields, "en")
+	assert.NoError(t, err)
+
+	next.AssertExpectations(t)
+	metrics := svc.Metrics()
+	assert.Equal(t, uint64(2), metrics.Hits)
+	assert.Equal(t, uint64(2), metrics.Misses)
+	assert.Equal(t, 0.5, metrics.HitRatio)
+}
+
+func TestCachingAdvertService_Invalidation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	next := new(mocks.MockAdvertService)
+	feed := memory.NewMemoryChangeFeed()
+	svc := service.NewCachingAdvertService(next, service.NewLRUAdvertCache(100), time.Minute, feed)
+	go svc.Run(ctx)
+
+	next.On("GetByID", mock.Anything, 1, service.SummaryFields, []string(nil)).
+		Return(service.AdvertDetail{AdvertSummary: service.AdvertSummary{ID: 1}}, nil)
+	// reread reports whether a read of the advert missed the cache
+	reread := func() bool {
+		before := svc.Metrics().Misses
+		_, err := svc.GetByID(ctx, 1, service.SummaryFields)
+		assert.NoError(t, err)
+		return svc.Metrics().Misses > before
+	}
+	assert.True(t, reread())
+	assert.False(t, reread())
+
+	// Favorites and views invalidate directly
+	svc.Invalidate(ctx, 1)
+	assert.True(t, reread())
+	assert.False(t, reread())
+
+	// A photo change, or a change on another replica, comes through the feed.
+	// Publish is retried until Run listens and has handled it.
+	assert.Eventually(t, func() bool {
+		return feed.Publish(ctx, `{"id":5,"event":"advert.updated","advert_id":1}`) == nil && reread()
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCachingAdvertService_ErrorsAreNotCached(t *testing.T) {
+	ctx := context.Background()
+	next := new(mocks.MockAdvertService)
+	svc := service.NewCachingAdvertService(next, service.NewLRUAdvertCache(100), time.Minute, nil)
+
+	next.On("GetByID", mock.Anything, 7, service.SummaryFields, []string(nil)).
+		Return(service.AdvertDetail{}, error_message.ErrAdvertNotFound).Twice()
+
+	for i := 0; i < 2; i++ {
+		_, err := svc.GetByID(ctx, 7, service.SummaryFields)
+		assert.ErrorIs(t, err, error_message.ErrAdvertNotFound)
+	}
+	next.AssertExpectations(t)
+}
+
+func TestCachingAdvertService_List(t *testing.T) {
+	ctx := context.Background()
+	next := new(mocks.MockAdvertService)
+	svc := service.NewCachingAdvertService(next, service.NewLRUAdvertCache(100), time.Minute, nil)
+
+	minPrice := 50.0
+	filter := model.AdvertFilter{MinPrice: &minPrice}
+	page := []service.AdvertDetail{{AdvertSummary: service.AdvertSummary{ID: 1, Name: "Bike", Price: 100}}}
+	next.On("List", mock.Anything, filter, 1, "price", "asc", service.SummaryFields, []string(nil)).Return(page, nil).Twice()
+	next.On("List", mock.Anything, model.AdvertFilter{}, 1, "price", "asc", service.SummaryFields, []string(nil)).Return(page, nil).Once()
+	next.On("Create", mock.Anything, mock.Anything).Return(2, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		got, err := svc.List(ctx, filter, 1, "price", "asc", service.SummaryFields)
+		assert.NoError(t, err)
+		assert.Equal(t, page, got)
+	}
+	// Another filter is another entry
+	_, err := svc.List(ctx, model.AdvertFilter{}, 1, "price", "asc", service.SummaryFields)
+	assert.NoError(t, err)
+
+	// A new advert invalidates every page
+	_, err = svc.Create(ctx, service.CreateAdvertInput{Name: "Lamp"})
+	assert.NoError(t, err)
+	_, err = svc.List(ctx, filter, 1, "price", "asc", service.SummaryFields)
+	assert.NoError(t, err)
+
+	next.AssertExpectations(t)
+}
+
+func TestCachingAdvertService_ConcurrentMissesShareOneLoad(t *testing.T) {
+	ctx := context.Background()
+	next := new(mocks.MockAdvertService)
+	svc := service.NewCachingAdvertService(next, service.NewLRUAdvertCache(100), time.Minute, nil)
+
+	next.On("GetByID", mock.Anything, 1, service.SummaryFields, []string(nil)).
+		Run(func(mock.Arguments) { time.Sleep(50 * time.Millisecond) }).
+		Return(service.AdvertDetail{AdvertSummary: service.AdvertSummary{ID: 1}}, nil).Once()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := svc.GetByID(ctx, 1, service.SummaryFields)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, got.ID)
+		}()
+	}
+	wg.Wait()
+
+	// Callers either waited for the single load or found its result in the cache
+	next.AssertExpectations(t)
+	metrics := svc.Metrics()
+	assert.Equal(t, uint64(10), metrics.Hits+metrics.Misses)
+	assert.Equal(t, metrics.Misses-1, metrics.Shared)
+}
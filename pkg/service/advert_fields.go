@@ -0,0 +1,70 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+)
+
+// Optional advert fields a caller can ask for. The advert ID is always returned.
+const (
+	FieldName          = "name"
+	FieldPrice         = "price"
+	FieldMainPhoto     = "main_photo"
+	FieldDescription   = "description"
+	FieldPhotos        = "photos"
+	FieldCreatedAt     = "created_at"
+	FieldFavoriteCount = "favorite_count"
+	FieldViewCount     = "view_count"
+)
+
+// AdvertFields is the set of fields to fetch for an advert.
+// The service skips the queries for fields that are not in the set.
+type AdvertFields map[string]struct{}
+
+var (
+	// SummaryFields are returned by the list and by GetByID when no fields are requested.
+	SummaryFields = NewAdvertFields(FieldName, FieldMainPhoto, FieldPrice)
+	// AllAdvertFields is the full advert view.
+	AllAdvertFields = NewAdvertFields(FieldName, FieldPrice, FieldMainPhoto, FieldDescription,
+		FieldPhotos, FieldCreatedAt, FieldFavoriteCount, FieldViewCount)
+)
+
+// NewAdvertFields builds a field set from field names.
+func NewAdvertFields(names ...string) AdvertFields {
+	fields := make(AdvertFields, len(names))
+	for _, name := range names {
+		fields[name] = struct{}{}
+	}
+	return fields
+}
+
+// Has reports whether the field is in the set.
+func (f AdvertFields) Has(name string) bool {
+	_, ok := f[name]
+	return ok
+}
+
+// ParseAdvertFields parses the comma-separated fields param, e.g. "name,price,photos".
+// An empty param means def; "true" and "false", kept for older clients, mean
+// the full view and the summary. Unknown fields yield ErrWrongFieldsParam.
+func ParseAdvertFields(raw string, def AdvertFields) (AdvertFields, error) {
+	switch strings.TrimSpace(raw) {
+	case "":
+		return def, nil
+	case "true":
+		return AllAdvertFields, nil
+	case "false":
+		return SummaryFields, nil
+	}
+
+	fields := make(AdvertFields)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if !AllAdvertFields.Has(name) {
+			return nil, error_message.ErrWrongFieldsParam
+		}
+		fields[name] = struct{}{}
+	}
+	return fields, nil
+}
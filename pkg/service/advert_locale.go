@@ -0,0 +1,163 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the locale of adverts created without one.
+const DefaultLocale = "en"
+
+// Limits of the advert texts and locales, the same as of the adverts table,
+// and of the photos of an advert.
+const (
+	maxLocaleLength      = 16
+	maxNameLength        = 200
+	maxDescriptionLength = 1000
+	maxPhotos            = 3
+)
+
+// AdvertText is the name and description of an advert in one locale.
+type AdvertText struct {
+	Name        string
+	Description string
+}
+
+// NormalizeLocale returns the canonical form of a BCP 47 locale ("EN-gb" → "en-GB")
+// or ErrWrongLocale.
+func NormalizeLocale(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil || tag == language.Und || len(tag.String()) > maxLocaleLength {
+		return "", error_message.ErrWrongLocale
+	}
+	return tag.String(), nil
+}
+
+// ValidateAdvertPayload checks the advert fields of a create or update request
+// and reports every invalid one; nil fields are not being changed and are skipped.
+// Create and Update run it, transports may call it to reject a request early.
+func ValidateAdvertPayload(name, description *string, photos *[]string, price *float64) error {
+	var invalid []*error_message.Error
+	if name != nil && (*name == "" || utf8.RuneCountInString(*name) > maxNameLength) {
+		invalid = append(invalid, error_message.ErrWrongTitle)
+	}
+	if description != nil && (*description == "" || utf8.RuneCountInString(*description) > maxDescriptionLength) {
+		invalid = append(invalid, error_message.ErrWrongDescription)
+	}
+	if photos != nil && (len(*photos) == 0 || len(*photos) > maxPhotos) {
+		invalid = append(invalid, error_message.ErrWrongPhotos)
+	}
+	if price != nil && *price <= 0 {
+		invalid = append(invalid, error_message.ErrNotPositivePrice)
+	}
+	return error_message.Validate(invalid...)
+}
+
+// validText reports whether an advert text in a translation is within the limits.
+func validText(text AdvertText) bool {
+	return text.Name != "" &&
+		utf8.RuneCountInString(text.Name) <= maxNameLength &&
+		utf8.RuneCountInString(text.Description) <= maxDescriptionLength
+}
+
+// buildTranslations puts the default-locale text and the other translations of a new
+// advert together, sorted by locale; text wins over a translation to the same locale.
+func buildTranslations(locale string, text AdvertText, translations map[string]AdvertText) (string, []model.AdvertTranslation, error) {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return "", nil, err
+	}
+
+	texts := make(map[string]AdvertText, len(translations)+1)
+	for l, t := range translations {
+		l, err := NormalizeLocale(l)
+		if err != nil || !validText(t) {
+			return "", nil, error_message.ErrWrongTranslation
+		}
+		texts[l] = t
+	}
+	texts[locale] = text
+	return locale, translationList(texts), nil
+}
+
+// translationList converts texts keyed by locale to translations sorted by locale.
+func translationList(texts map[string]AdvertText) []model.AdvertTranslation {
+	list := make([]model.AdvertTranslation, 0, len(texts))
+	for locale, text := range texts {
+		list = append(list, model.AdvertTranslation{Locale: locale, Name: text.Name, Description: text.Description})
+	}
+	slices.SortFunc(list, func(a, b model.AdvertTranslation) int {
+		return strings.Compare(a.Locale, b.Locale)
+	})
+	return list
+}
+
+// defaultTranslation is the text of the advert in its default locale.
+func defaultTranslation(advert model.Advert) model.AdvertTranslation {
+	return model.AdvertTranslation{
+		AdvertID:    advert.ID,
+		Locale:      advert.DefaultLocale,
+		Name:        advert.Name,
+		Description: advert.Description,
+	}
+}
+
+// translationPicker picks the texts of the adverts of one request. The preferred
+// locales are parsed once and a matcher is built once per set of advert locales,
+// which most adverts of a page share.
+type translationPicker struct {
+	preferred []language.Tag
+	matchers  map[string]language.Matcher
+}
+
+// newTranslationPicker prefers locales in order, most preferred first.
+// Malformed locales are ignored.
+func newTranslationPicker(locales []string) *translationPicker {
+	preferred := make([]language.Tag, 0, len(locales))
+	for _, locale := range locales {
+		if tag, err := language.Parse(locale); err == nil {
+			preferred = append(preferred, tag)
+		}
+	}
+	return &translationPicker{preferred: preferred, matchers: make(map[string]language.Matcher)}
+}
+
+// pick returns the translation best matching the preferred locales, or the
+// default-locale text when none of them matches.
+func (p *translationPicker) pick(advert model.Advert, translations []model.AdvertTranslation) model.AdvertTranslation {
+	fallback := defaultTranslation(advert)
+	if len(p.preferred) == 0 || len(translations) == 0 {
+		return fallback
+	}
+
+	// The default locale goes first: it is what the matcher falls back to
+	locales := make([]string, 0, len(translations)+1)
+	locales = append(locales, advert.DefaultLocale)
+	for _, t := range translations {
+		locales = append(locales, t.Locale)
+	}
+	key := strings.Join(locales, ",")
+	matcher, ok := p.matchers[key]
+	if !ok {
+		supported := make([]language.Tag, 0, len(locales))
+		for _, locale := range locales {
+			supported = append(supported, language.Make(locale))
+		}
+		matcher = language.NewMatcher(supported)
+		p.matchers[key] = matcher
+	}
+	_, index, confidence := matcher.Match(p.preferred...)
+	if confidence == language.No || index == 0 {
+		return fallback
+	}
+	return translations[index-1]
+}
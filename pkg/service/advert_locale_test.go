@@ -0,0 +1,189 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func localizedAdvert(id int) model.Advert {
+	ad := *sampleAdvertModel(id)
+	ad.Name, ad.Description, ad.DefaultLocale = "Bike", "Red bike", "en"
+	return ad
+}
+
+func advertTranslations(id int) []model.AdvertTranslation {
+	return []model.AdvertTranslation{
+		{AdvertID: id, Locale: "en", Name: "Bike", Description: "Red bike"},
+		{AdvertID: id, Locale: "ru", Name: "Велосипед", Description: "Красный велосипед"},
+	}
+}
+
+func TestAdvertService_GetByIDLocales(t *testing.T) {
+	ctx := context.Background()
+	fields := service.NewAdvertFields(service.FieldName, service.FieldDescription)
+
+	cases := []struct {
+		name    string
+		locales []string
+		want    model.AdvertTranslation
+	}{
+		{"Exact", []string{"ru"}, advertTranslations(1)[1]},
+		{"Region", []string{"ru-RU", "en"}, advertTranslations(1)[1]},
+		{"Preference", []string{"de", "en", "ru"}, advertTranslations(1)[0]},
+		{"DefaultLocale", []string{"de"}, advertTranslations(1)[0]},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAdRepo := new(MockAdvertRepo)
+			svc := service.NewAdvertService(mockAdRepo, new(MockPhotoRepo), new(MockFavoriteRepo))
+
+			mockAdRepo.On("GetByID", mock.Anything, 1).Return(localizedAdvert(1), nil).Once()
+			mockAdRepo.On("ListTranslations", mock.Anything, []int{1}).
+				Return(map[int][]model.AdvertTranslation{1: advertTranslations(1)}, nil).Once()
+
+			detail, err := svc.GetByID(ctx, 1, fields, tc.locales...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want.Locale, detail.Locale)
+			assert.Equal(t, tc.want.Name, detail.Name)
+			assert.Equal(t, tc.want.Description, detail.Description)
+			mockAdRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("NoPreferenceNoQuery", func(t *testing.T) {
+		mockAdRepo := new(MockAdvertRepo)
+		svc := service.NewAdvertService(mockAdRepo, new(MockPhotoRepo), new(MockFavoriteRepo))
+		mockAdRepo.On("GetByID", mock.Anything, 1).Return(localizedAdvert(1), nil).Once()
+
+		detail, err := svc.GetByID(ctx, 1, fields)
+		require.NoError(t, err)
+		assert.Equal(t, "en", detail.Locale)
+		assert.Equal(t, "Bike", detail.Name)
+		mockAdRepo.AssertNotCalled(t, "ListTranslations", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAdvertService_ListLocales(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	svc := service.NewAdvertService(mockAdRepo, new(MockPhotoRepo), new(MockFavoriteRepo))
+
+	untranslated := localizedAdvert(2)
+	untranslated.Name = "Car"
+	mockAdRepo.On("List", mock.Anything, model.AdvertFilter{}, 10, 0, "id", "ASC").
+		Return([]model.Advert{localizedAdvert(1), untranslated}, nil).Once()
+	// One query for the whole page
+	mockAdRepo.On("ListTranslations", mock.Anything, []int{1, 2}).
+		Return(map[int][]model.AdvertTranslation{1: advertTranslations(1)}, nil).Once()
+
+	details, err := svc.List(context.Background(), model.AdvertFilter{}, 1, "", "", service.NewAdvertFields(service.FieldName), "ru")
+	require.NoError(t, err)
+	assert.Equal(t, []service.AdvertDetail{
+		{AdvertSummary: service.AdvertSummary{ID: 1, Name: "Велосипед", Locale: "ru"}},
+		{AdvertSummary: service.AdvertSummary{ID: 2, Name: "Car", Locale: "en"}},
+	}, details)
+	mockAdRepo.AssertExpectations(t)
+}
+
+func TestAdvertService_CreateTranslations(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockAdRepo := new(MockAdvertRepo)
+		mockPhRepo := new(MockPhotoRepo)
+		svc := service.NewAdvertService(mockAdRepo, mockPhRepo, new(MockFavoriteRepo))
+
+		mockAdRepo.On("Create", mock.Anything, mock.MatchedBy(func(ad model.Advert) bool {
+			return ad.DefaultLocale == "ru" && ad.Name == "Велосипед" && assert.ObjectsAreEqual([]model.AdvertTranslation{
+				{Locale: "en-GB", Name: "Bike", Description: "Red bike"},
+				{Locale: "ru", Name: "Велосипед", Description: "Красный велосипед"},
+			}, ad.Translations)
+		})).Return(5, nil).Once()
+
+		id, err := svc.Create(ctx, service.CreateAdvertInput{
+			Name: "Велосипед", Description: "Красный велосипед", Photos: []string{"http://img"}, Price: 100, Locale: "RU",
+			Translations: map[string]service.AdvertText{"en-gb": {Name: "Bike", Description: "Red bike"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 5, id)
+		mockAdRepo.AssertExpectations(t)
+	})
+
+	for name, tc := range map[string]struct {
+		input service.CreateAdvertInput
+		err   error
+	}{
+		"WrongLocale": {service.CreateAdvertInput{Name: "Bike", Description: "Red bike", Photos: []string{"http://img"}, Price: 1, Locale: "not a locale"}, error_message.ErrWrongLocale},
+		"WrongTranslationLocale": {service.CreateAdvertInput{Name: "Bike", Description: "Red bike", Photos: []string{"http://img"}, Price: 1,
+			Translations: map[string]service.AdvertText{"??": {Name: "Bike"}}}, error_message.ErrWrongTranslation},
+		"EmptyTranslationName": {service.CreateAdvertInput{Name: "Bike", Description: "Red bike", Photos: []string{"http://img"}, Price: 1,
+			Translations: map[string]service.AdvertText{"ru": {Description: "Велосипед"}}}, error_message.ErrWrongTranslation},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockAdRepo := new(MockAdvertRepo)
+			svc := service.NewAdvertService(mockAdRepo, new(MockPhotoRepo), new(MockFavoriteRepo))
+
+			_, err := svc.Create(ctx, tc.input)
+			assert.ErrorIs(t, err, tc.err)
+			mockAdRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAdvertService_UpdateTranslations(t *testing.T) {
+	ctx := context.Background()
+
+	newService := func() (service.AdvertService, *MockAdvertRepo) {
+		mockAdRepo := new(MockAdvertRepo)
+		mockAdRepo.On("GetByID", mock.Anything, 1).Return(localizedAdvert(1), nil).Once()
+		mockAdRepo.On("ListTranslations", mock.Anything, []int{1}).
+			Return(map[int][]model.AdvertTranslation{1: advertTranslations(1)}, nil).Once()
+		return service.NewAdvertService(mockAdRepo, new(MockPhotoRepo), new(MockFavoriteRepo)), mockAdRepo
+	}
+
+	t.Run("SwitchDefaultLocale", func(t *testing.T) {
+		svc, mockAdRepo := newService()
+		mockAdRepo.On("Update", mock.Anything, mock.MatchedBy(func(ad model.Advert) bool {
+			return ad.DefaultLocale == "ru" && ad.Name == "Велосипед" && ad.Description == "Синий велосипед" &&
+				assert.ObjectsAreEqual([]model.AdvertTranslation{
+					{Locale: "ru", Name: "Велосипед", Description: "Синий велосипед"},
+				}, ad.Translations)
+		})).Return(nil).Once()
+
+		err := svc.Update(ctx, 1, service.UpdateAdvertInput{
+			Locale:       strPtr("ru"),
+			Description:  strPtr("Синий велосипед"),
+			Translations: map[string]*service.AdvertText{"en": nil},
+		})
+		require.NoError(t, err)
+		mockAdRepo.AssertExpectations(t)
+	})
+
+	t.Run("RemoveDefaultLocale", func(t *testing.T) {
+		svc, mockAdRepo := newService()
+
+		err := svc.Update(ctx, 1, service.UpdateAdvertInput{Translations: map[string]*service.AdvertText{"en": nil}})
+		assert.ErrorIs(t, err, error_message.ErrMissingDefaultText)
+		mockAdRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("PriceOnlyKeepsTranslations", func(t *testing.T) {
+		mockAdRepo := new(MockAdvertRepo)
+		svc := service.NewAdvertService(mockAdRepo, new(MockPhotoRepo), new(MockFavoriteRepo))
+		mockAdRepo.On("GetByID", mock.Anything, 1).Return(localizedAdvert(1), nil).Once()
+		mockAdRepo.On("Update", mock.Anything, mock.MatchedBy(func(ad model.Advert) bool {
+			return ad.Price == 50 && ad.Translations == nil
+		})).Return(nil).Once()
+
+		require.NoError(t, svc.Update(ctx, 1, service.UpdateAdvertInput{Price: floatPtr(50)}))
+		mockAdRepo.AssertNotCalled(t, "ListTranslations", mock.Anything, mock.Anything)
+		mockAdRepo.AssertExpectations(t)
+	})
+}
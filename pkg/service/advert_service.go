@@ -1,39 +1,73 @@
 package service
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
 
 // CreateAdvertInput contains data for creating an advert.
+// OwnerID is empty for anonymous adverts.
+// Name and Description are in Locale, the default locale of the advert
+// (DefaultLocale if empty); Translations adds texts in other locales.
 type CreateAdvertInput struct {
-	Name        string
-	Description string
-	Photos      []string
-	Price       float64
+	OwnerID      string
+	Name         string
+	Description  string
+	Photos       []string
+	Price        float64
+	Locale       string
+	Translations map[string]AdvertText
 }
 
 // UpdateAdvertInput contains fields for partial advert update.
 // Any of them can be nil — in that case, the corresponding field is not changed.
+// Locale switches the default locale of the advert, Name and Description change
+// the text in it. Translations sets the texts of the listed locales, a nil text
+// removes the locale.
 type UpdateAdvertInput struct {
-	Name        *string
-	Description *string
-	Photos      *[]string
-	Price       *float64
+	Name         *string
+	Description  *string
+	Photos       *[]string
+	Price        *float64
+	Locale       *string
+	Translations map[string]*AdvertText
 }
 
 // AdvertSummary represents the data returned in the advert list.
-// Fields: ID, name, main photo (first URL), and price.
+// Fields: ID, name, main photo (first URL), its thumbnail (if generated), price
+// and creation time. Locale is the locale of the name and description.
 type AdvertSummary struct {
-	ID           int     `json:"id"`
-	Name         string  `json:"name"`
-	MainPhotoURL string  `json:"main_photo_url"`
-	Price        float64 `json:"price"`
+	ID                int       `json:"id"`
+	Name              string    `json:"name"`
+	Locale            string    `json:"locale,omitempty"`
+	MainPhotoURL      string    `json:"main_photo_url"`
+	MainPhotoThumbURL string    `json:"main_photo_thumb_url,omitempty"`
+	Price             float64   `json:"price"`
+	CreatedAt         time.Time `json:"created_at,omitzero"`
 }
 
 // AdvertDetail represents a full advert view.
-// Includes AdvertSummary + description + all photo URLs.
+// Includes AdvertSummary + description + all photo URLs + how many users favorited
+// and viewed it.
 type AdvertDetail struct {
 	AdvertSummary
 	Description   string   `json:"description"`
 	AllPhotosURLs []string `json:"all_photos_urls"`
+	FavoriteCount int      `json:"favorite_count"`
+	ViewCount     int64    `json:"view_count"`
+}
+
+// AdvertExportRow represents a single advert in the bulk export,
+// including all photo URLs ordered by position.
+type AdvertExportRow struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Price       float64   `json:"price"`
+	CreatedAt   time.Time `json:"created_at"`
+	PhotoURLs   []string  `json:"photo_urls"`
 }
 
 // AdvertService describes the business logic for working with adverts.
@@ -42,15 +76,27 @@ type AdvertService interface {
 	Create(ctx context.Context, input CreateAdvertInput) (int, error)
 
 	// GetByID returns an advert by ID.
-	// If fields == true, includes Description and AllPhotosURLs,
-	// otherwise — only AdvertSummary.
-	GetByID(ctx context.Context, id int, fields bool) (AdvertDetail, error)
+	// Only the requested fields are fetched, the others keep their zero values.
+	// The name and description are in the locale best matching locales (most
+	// preferred first), the default locale of the advert if none does.
+	GetByID(ctx context.Context, id int, fields AdvertFields, locales ...string) (AdvertDetail, error)
+
+	// GetByIDs returns several adverts at once, keyed by ID, the same way GetByID
+	// returns one; unknown IDs are missing from the map.
+	GetByIDs(ctx context.Context, ids []int, fields AdvertFields, locales ...string) (map[int]AdvertDetail, error)
 
 	// List returns a paginated list of adverts.
+	// filter — price and creation date bounds (empty = all adverts),
 	// page — page number (1-based),
-	// sortField — "price" or "date",
-	// sortOrder — "asc" or "desc".
-	List(ctx context.Context, page int, sortField, sortOrder string) ([]AdvertSummary, error)
+	// sortField — "price", "date" or "popular" (view count),
+	// sortOrder — "asc" or "desc",
+	// fields — the fields to fetch for every advert (see GetByID),
+	// locales — the preferred locales of the names and descriptions (see GetByID).
+	List(ctx context.Context, filter model.AdvertFilter, page int, sortField, sortOrder string, fields AdvertFields, locales ...string) ([]AdvertDetail, error)
+
+	// Export streams the filtered adverts to fn one by one, never loading the whole
+	// catalogue into memory. Filtering and sorting follow the same rules as List.
+	Export(ctx context.Context, filter model.AdvertFilter, sortField, sortOrder string, fn func(row AdvertExportRow) error) error
 
 	// Update partially updates an advert by ID.
 	// Uses UpdateAdvertInput to determine which fields to change.
@@ -13,172 +13,373 @@ import (
 	"time"
 )
 
+// ListPageSize is how many items a single page of a list contains.
+const ListPageSize = 10
+
+// exportBatchSize is how many rows Export fetches from the repository at once.
+const exportBatchSize = 500
+
 type advertService struct {
-	advertRepo repository.AdvertRepo
-	photoRepo  repository.PhotoRepo
+	advertRepo   repository.AdvertRepo
+	photoRepo    repository.PhotoRepo
+	favoriteRepo repository.FavoriteRepo
 }
 
-func NewAdvertService(ar repository.AdvertRepo, pr repository.PhotoRepo) AdvertService {
+func NewAdvertService(ar repository.AdvertRepo, pr repository.PhotoRepo, fr repository.FavoriteRepo) AdvertService {
 	return &advertService{
-		advertRepo: ar,
-		photoRepo:  pr,
+		advertRepo:   ar,
+		photoRepo:    pr,
+		favoriteRepo: fr,
 	}
 }
 
-func (s *advertService) Create(ctx context.Context, input CreateAdvertInput) (int, error) {
+// validateCreateInput checks the rules every new advert must satisfy and returns its
+// default locale and translations. It is shared by Create and the bulk import dry-run.
+func validateCreateInput(input CreateAdvertInput) (string, []model.AdvertTranslation, error) {
 	if input.Name == "" {
-		return 0, errors.New("name is required")
-	}
-	if input.Price <= 0 {
-		return 0, errors.New("price must be greater than zero")
+		return "", nil, error_message.ErrMissingName
 	}
-	advert := model.Advert{
-		Name:        input.Name,
-		Description: input.Description,
-		Price:       input.Price,
-		CreatedAt:   time.Now(),
+	if err := ValidateAdvertPayload(&input.Name, &input.Description, &input.Photos, &input.Price); err != nil {
+		return "", nil, err
 	}
-	advertID, err := s.advertRepo.Create(ctx, advert)
+	return buildTranslations(input.Locale, AdvertText{Name: input.Name, Description: input.Description}, input.Translations)
+}
+
+func (s *advertService) Create(ctx context.Context, input CreateAdvertInput) (int, error) {
+	locale, translations, err := validateCreateInput(input)
 	if err != nil {
 		return 0, err
 	}
-	for idx, url := range input.Photos {
-		photo := model.Photo{
-			AdvertID: advertID,
-			URL:      url,
-			Position: idx,
-		}
-		if err := s.photoRepo.Create(ctx, photo); err != nil {
-			return advertID, err
-		}
+	advert := model.Advert{
+		OwnerID:       input.OwnerID,
+		Name:          input.Name,
+		Description:   input.Description,
+		Price:         input.Price,
+		CreatedAt:     time.Now(),
+		DefaultLocale: locale,
+		Translations:  translations,
+		PhotoURLs:     input.Photos,
 	}
-	return advertID, nil
+	return s.advertRepo.Create(ctx, advert)
 }
 
-func (s *advertService) GetByID(ctx context.Context, id int, fields bool) (AdvertDetail, error) {
+func (s *advertService) GetByID(ctx context.Context, id int, fields AdvertFields, locales ...string) (AdvertDetail, error) {
 	advert, err := s.advertRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AdvertDetail{}, error_message.ErrAdvertNotFound
+		}
+		return AdvertDetail{}, fmt.Errorf("service.GetByID: advertRepo.GetByID (id=%d): %w", id, err)
+	}
+	texts, err := s.translate(ctx, []model.Advert{advert}, fields, locales)
 	if err != nil {
 		return AdvertDetail{}, err
 	}
-
-	mainURL, err := s.photoRepo.GetMainPhotoURL(ctx, id)
+	details, err := s.details(ctx, []model.Advert{advert}, texts, fields)
 	if err != nil {
 		return AdvertDetail{}, err
 	}
+	return details[0], nil
+}
+
+func (s *advertService) GetByIDs(ctx context.Context, ids []int, fields AdvertFields, locales ...string) (map[int]AdvertDetail, error) {
+	details := make(map[int]AdvertDetail, len(ids))
+	if len(ids) == 0 {
+		return details, nil
+	}
+	adverts, err := s.advertRepo.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("service.GetByIDs: advertRepo.ListByIDs: %w", err)
+	}
+	texts, err := s.translate(ctx, adverts, fields, locales)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.details(ctx, adverts, texts, fields)
+	if err != nil {
+		return nil, err
+	}
+	for _, detail := range list {
+		details[detail.ID] = detail
+	}
+	return details, nil
+}
+
+// translate picks the text of every advert in the preferred locales, querying
+// the translations only when the name or description is requested in a locale.
+func (s *advertService) translate(ctx context.Context, adverts []model.Advert, fields AdvertFields, locales []string) ([]model.AdvertTranslation, error) {
+	texts := make([]model.AdvertTranslation, len(adverts))
+	for i, advert := range adverts {
+		texts[i] = defaultTranslation(advert)
+	}
+	if len(locales) == 0 || len(adverts) == 0 || !(fields.Has(FieldName) || fields.Has(FieldDescription)) {
+		return texts, nil
+	}
 
-	summary := AdvertSummary{
-		ID:           advert.ID,
-		Name:         advert.Name,
-		MainPhotoURL: mainURL,
-		Price:        advert.Price,
+	ids := make([]int, 0, len(adverts))
+	for _, advert := range adverts {
+		ids = append(ids, advert.ID)
 	}
+	translations, err := s.advertRepo.ListTranslations(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("service.translate: advertRepo.ListTranslations: %w", err)
+	}
+	picker := newTranslationPicker(locales)
+	for i, advert := range adverts {
+		texts[i] = picker.pick(advert, translations[advert.ID])
+	}
+	return texts, nil
+}
 
-	if !fields {
-		return AdvertDetail{AdvertSummary: summary}, nil
+// details builds the details of adverts in order, counting the favorites of all of them
+// with a single query.
+func (s *advertService) details(ctx context.Context, adverts []model.Advert, texts []model.AdvertTranslation, fields AdvertFields) ([]AdvertDetail, error) {
+	var favorites map[int]int
+	if fields.Has(FieldFavoriteCount) && len(adverts) > 0 {
+		ids := make([]int, 0, len(adverts))
+		for _, advert := range adverts {
+			ids = append(ids, advert.ID)
+		}
+		var err error
+		if favorites, err = s.favoriteRepo.CountByAdvertIDs(ctx, ids); err != nil {
+			return nil, fmt.Errorf("service.details: favoriteRepo.CountByAdvertIDs: %w", err)
+		}
+	}
+	details := make([]AdvertDetail, 0, len(adverts))
+	for i, advert := range adverts {
+		detail, err := s.detail(ctx, advert, texts[i], fields)
+		if err != nil {
+			return nil, err
+		}
+		detail.FavoriteCount = favorites[advert.ID]
+		details = append(details, detail)
 	}
-	photos, err := s.photoRepo.GetAllPhotoURLs(ctx, id)
+	return details, nil
+}
+
+// detail builds the advert view with the given text, running only the queries
+// the requested fields need; the favorite count is filled in by details.
+func (s *advertService) detail(ctx context.Context, advert model.Advert, text model.AdvertTranslation, fields AdvertFields) (AdvertDetail, error) {
+	summary, err := s.summarize(ctx, advert, text, fields)
 	if err != nil {
 		return AdvertDetail{}, err
 	}
-	detail := AdvertDetail{
-		AdvertSummary: summary,
-		Description:   advert.Description,
-		AllPhotosURLs: photos,
+	detail := AdvertDetail{AdvertSummary: summary}
+
+	if fields.Has(FieldDescription) {
+		detail.Description = text.Description
+		detail.Locale = text.Locale
+	}
+	if fields.Has(FieldViewCount) {
+		detail.ViewCount = advert.ViewCount
+	}
+	if fields.Has(FieldPhotos) {
+		if detail.AllPhotosURLs, err = s.photoRepo.GetAllPhotoURLs(ctx, advert.ID); err != nil {
+			return AdvertDetail{}, err
+		}
 	}
 	return detail, nil
 }
 
-func (s *advertService) List(ctx context.Context, page int, sortField, sortOrder string) ([]AdvertSummary, error) {
+// summarize builds the summary of an advert, querying the main photo
+// only when it is requested.
+func (s *advertService) summarize(ctx context.Context, advert model.Advert, text model.AdvertTranslation, fields AdvertFields) (AdvertSummary, error) {
+	summary := AdvertSummary{ID: advert.ID}
+	if fields.Has(FieldName) {
+		summary.Name = text.Name
+		summary.Locale = text.Locale
+	}
+	if fields.Has(FieldPrice) {
+		summary.Price = advert.Price
+	}
+	if fields.Has(FieldCreatedAt) {
+		summary.CreatedAt = advert.CreatedAt
+	}
+	if fields.Has(FieldMainPhoto) {
+		// An advert without photos has no main photo
+		mainURL, err := s.photoRepo.GetMainPhotoURL(ctx, advert.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return AdvertSummary{}, err
+		}
+		thumbURL, err := s.photoRepo.GetMainPhotoVariantURL(ctx, advert.ID, ThumbVariant)
+		if err != nil {
+			return AdvertSummary{}, err
+		}
+		summary.MainPhotoURL = mainURL
+		summary.MainPhotoThumbURL = thumbURL
+	}
+	return summary, nil
+}
+
+// resolveSort maps the public sort params to a column and direction.
+// Empty params mean the default "id ASC".
+func resolveSort(sortField, sortOrder string) (string, string, error) {
+	if strings.TrimSpace(sortField) == "" && strings.TrimSpace(sortOrder) == "" {
+		return "id", "ASC", nil
+	}
+
+	var column, direction string
+	switch sortField {
+	case "price":
+		column = "price"
+	case "date":
+		column = "created_at"
+	case "popular":
+		column = "view_count"
+	default:
+		return "", "", error_message.ErrWrongSortParams
+	}
+	switch strings.ToLower(sortOrder) {
+	case "asc":
+		direction = "ASC"
+	case "desc":
+		direction = "DESC"
+	default:
+		return "", "", error_message.ErrWrongSortParams
+	}
+	return column, direction, nil
+}
+
+// ValidateSort checks the public sort params like List does, so that callers can
+// reject them before doing any work.
+func ValidateSort(sortField, sortOrder string) error {
+	_, _, err := resolveSort(sortField, sortOrder)
+	return err
+}
+
+// pageBounds converts a 1-based page number to LIMIT/OFFSET.
+func pageBounds(page int) (limit, offset int, err error) {
 	if page < 1 {
-		return nil, errors.New("page must be >= 1")
+		return 0, 0, error_message.ErrWrongPageNumber
 	}
+	return ListPageSize, (page - 1) * ListPageSize, nil
+}
 
-	const pageSize = 10
-	offset := (page - 1) * pageSize
+func (s *advertService) List(ctx context.Context, filter model.AdvertFilter, page int, sortField, sortOrder string, fields AdvertFields, locales ...string) ([]AdvertDetail, error) {
+	limit, offset, err := pageBounds(page)
+	if err != nil {
+		return nil, err
+	}
 
-	var defaultSortField string
-	var defaultSortOrder string
-	if strings.TrimSpace(sortField) == "" && strings.TrimSpace(sortOrder) == "" {
-		defaultSortField = "id"
-		defaultSortOrder = "ASC"
-	} else {
-		switch sortField {
-		case "price":
-			defaultSortField = "price"
-		case "date":
-			defaultSortField = "created_at"
-		default:
-			return nil, errors.New("invalid sort field: must be 'price' or 'date'")
-		}
-		switch strings.ToLower(sortOrder) {
-		case "asc":
-			defaultSortOrder = "ASC"
-		case "desc":
-			defaultSortOrder = "DESC"
-		default:
-			return nil, errors.New("invalid sort order: must be 'asc' or 'desc'")
-		}
+	defaultSortField, defaultSortOrder, err := resolveSort(sortField, sortOrder)
+	if err != nil {
+		return nil, err
 	}
 
-	adverts, err := s.advertRepo.List(ctx, pageSize, offset, defaultSortField, defaultSortOrder)
+	adverts, err := s.advertRepo.List(ctx, filter, limit, offset, defaultSortField, defaultSortOrder)
 	if err != nil {
 		return nil, err
 	}
 
-	summaries := make([]AdvertSummary, 0, len(adverts))
-	for _, adv := range adverts {
-		mainURL, err := s.photoRepo.GetMainPhotoURL(ctx, adv.ID)
-		if err != nil {
-			return nil, err
-		}
-		summaries = append(summaries, AdvertSummary{
-			ID:           adv.ID,
-			Name:         adv.Name,
-			MainPhotoURL: mainURL,
-			Price:        adv.Price,
-		})
+	texts, err := s.translate(ctx, adverts, fields, locales)
+	if err != nil {
+		return nil, err
+	}
+	return s.details(ctx, adverts, texts, fields)
+}
+
+func (s *advertService) Export(ctx context.Context, filter model.AdvertFilter, sortField, sortOrder string, fn func(row AdvertExportRow) error) error {
+	column, direction, err := resolveSort(sortField, sortOrder)
+	if err != nil {
+		return err
 	}
-	return summaries, nil
+
+	return s.advertRepo.Stream(ctx, filter, column, direction, exportBatchSize, func(ad model.Advert, photoURLs []string) error {
+		return fn(AdvertExportRow{
+			ID:          ad.ID,
+			Name:        ad.Name,
+			Description: ad.Description,
+			Price:       ad.Price,
+			CreatedAt:   ad.CreatedAt,
+			PhotoURLs:   photoURLs,
+		})
+	})
 }
 
 func (s *advertService) Update(ctx context.Context, id int, input UpdateAdvertInput) error {
+	if err := ValidateAdvertPayload(input.Name, input.Description, input.Photos, input.Price); err != nil {
+		return err
+	}
 	advert, err := s.advertRepo.GetByID(ctx, id)
 	if err != nil {
-		return error_message.ErrAdvertNotFound
+		if errors.Is(err, sql.ErrNoRows) {
+			return error_message.ErrAdvertNotFound
+		}
+		return fmt.Errorf("service.Update: advertRepo.GetByID (id=%d): %w", id, err)
 	}
 
-	if input.Name != nil {
-		advert.Name = *input.Name
-	}
-	if input.Description != nil {
-		advert.Description = *input.Description
+	if input.Name != nil || input.Description != nil || input.Locale != nil || input.Translations != nil {
+		if err := s.retranslate(ctx, &advert, input); err != nil {
+			return err
+		}
 	}
 	if input.Price != nil {
-		if *input.Price <= 0 {
-			return error_message.ErrNotPositivePrice
-		}
 		advert.Price = *input.Price
 	}
-
+	if input.Photos != nil {
+		advert.PhotoURLs = *input.Photos
+	}
+	// The advert may have been deleted since it was read
 	if err := s.advertRepo.Update(ctx, advert); err != nil {
-		return err
+		if errors.Is(err, sql.ErrNoRows) {
+			return error_message.ErrAdvertNotFound
+		}
+		return fmt.Errorf("service.Update: advertRepo.Update (id=%d): %w", id, err)
 	}
+	return nil
+}
 
-	if input.Photos != nil {
-		if err := s.photoRepo.DeleteByAdvertID(ctx, id); err != nil {
-			return err
+// retranslate applies the text changes of input to the advert, filling
+// advert.Translations with every locale the advert has afterwards.
+func (s *advertService) retranslate(ctx context.Context, advert *model.Advert, input UpdateAdvertInput) error {
+	stored, err := s.advertRepo.ListTranslations(ctx, []int{advert.ID})
+	if err != nil {
+		return fmt.Errorf("service.Update: advertRepo.ListTranslations (id=%d): %w", advert.ID, err)
+	}
+	texts := make(map[string]AdvertText, len(stored[advert.ID])+len(input.Translations)+1)
+	for _, t := range stored[advert.ID] {
+		texts[t.Locale] = AdvertText{Name: t.Name, Description: t.Description}
+	}
+	// The advert itself always holds the text of its default locale
+	texts[advert.DefaultLocale] = AdvertText{Name: advert.Name, Description: advert.Description}
+
+	for l, t := range input.Translations {
+		l, err := NormalizeLocale(l)
+		if err != nil {
+			return error_message.ErrWrongTranslation
+		}
+		if t == nil {
+			delete(texts, l)
+			continue
 		}
-		for idx, url := range *input.Photos {
-			photo := model.Photo{
-				AdvertID: id,
-				URL:      url,
-				Position: idx,
-			}
-			if err := s.photoRepo.Create(ctx, photo); err != nil {
-				return err
-			}
+		if !validText(*t) {
+			return error_message.ErrWrongTranslation
+		}
+		texts[l] = *t
+	}
+
+	locale := advert.DefaultLocale
+	if input.Locale != nil {
+		if locale, err = NormalizeLocale(*input.Locale); err != nil {
+			return err
 		}
 	}
+	text := texts[locale]
+	if input.Name != nil {
+		text.Name = *input.Name
+	}
+	if input.Description != nil {
+		text.Description = *input.Description
+	}
+	if text.Name == "" {
+		return error_message.ErrMissingDefaultText
+	}
+	texts[locale] = text
+
+	advert.DefaultLocale = locale
+	advert.Name = text.Name
+	advert.Description = text.Description
+	advert.Translations = translationList(texts)
 	return nil
 }
 
@@ -195,7 +396,7 @@ func (s *advertService) Delete(ctx context.Context, id int) error {
 		if errors.Is(err, sql.ErrNoRows) {
 			return error_message.ErrAdvertNotFound
 		}
-		return fmt.Errorf("service.Delete: advertRepo.GetByID (id=%d): %w", id, err)
+		return fmt.Errorf("service.Delete: advertRepo.Delete (id=%d): %w", id, err)
 	}
 	return nil
 }
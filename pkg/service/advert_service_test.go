@@ -2,9 +2,12 @@ package service_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
 	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,13 +26,14 @@ func (m *MockAdvertRepo) Create(ctx context.Context, ad model.Advert) (int, erro
 	return args.Int(0), args.Error(1)
 }
 
-// List returns a paginated list of adverts with sorting
+// List returns a filtered, paginated list of adverts with sorting
 func (m *MockAdvertRepo) List(
 	ctx context.Context,
+	filter model.AdvertFilter,
 	limit, offset int,
 	sortField, sortOrder string,
 ) ([]model.Advert, error) {
-	args := m.Called(ctx, limit, offset, sortField, sortOrder)
+	args := m.Called(ctx, filter, limit, offset, sortField, sortOrder)
 	if stored := args.Get(0); stored != nil {
 		return stored.([]model.Advert), args.Error(1)
 	}
@@ -37,6 +41,12 @@ func (m *MockAdvertRepo) List(
 }
 
 // GetByID returns an advert by ID
+func (m *MockAdvertRepo) ListByIDs(ctx context.Context, ids []int) ([]model.Advert, error) {
+	args := m.Called(ctx, ids)
+	ads, _ := args.Get(0).([]model.Advert)
+	return ads, args.Error(1)
+}
+
 func (m *MockAdvertRepo) GetByID(ctx context.Context, id int) (model.Advert, error) {
 	args := m.Called(ctx, id)
 	// if the first argument is not nil, cast it to model.Advert
@@ -58,6 +68,37 @@ func (m *MockAdvertRepo) Delete(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
+// Stream walks over all adverts, calling fn for each one
+func (m *MockAdvertRepo) SetFlag(ctx context.Context, id int, reason string) error {
+	args := m.Called(ctx, id, reason)
+	return args.Error(0)
+}
+
+func (m *MockAdvertRepo) AddViews(ctx context.Context, views map[int]int64) error {
+	args := m.Called(ctx, views)
+	return args.Error(0)
+}
+
+// ListTranslations mocks fetching translations of several adverts
+func (m *MockAdvertRepo) ListTranslations(ctx context.Context, advertIDs []int) (map[int][]model.AdvertTranslation, error) {
+	args := m.Called(ctx, advertIDs)
+	if t, ok := args.Get(0).(map[int][]model.AdvertTranslation); ok {
+		return t, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAdvertRepo) Stream(
+	ctx context.Context,
+	filter model.AdvertFilter,
+	sortField, sortOrder string,
+	batchSize int,
+	fn func(ad model.Advert, photoURLs []string) error,
+) error {
+	args := m.Called(ctx, filter, sortField, sortOrder, batchSize, fn)
+	return args.Error(0)
+}
+
 // MockPhotoRepo implements a mock for repository.PhotoRepo
 type MockPhotoRepo struct {
 	mock.Mock
@@ -90,6 +131,120 @@ func (m *MockPhotoRepo) DeleteByAdvertID(ctx context.Context, advertID int) erro
 	return args.Error(0)
 }
 
+// ListByAdvertID returns all photos of the advert ordered by position
+func (m *MockPhotoRepo) ListByAdvertID(ctx context.Context, advertID int) ([]model.Photo, error) {
+	args := m.Called(ctx, advertID)
+	if photos, ok := args.Get(0).([]model.Photo); ok {
+		return photos, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPhotoRepo) ListByAdvertIDs(ctx context.Context, advertIDs []int) (map[int][]model.Photo, error) {
+	args := m.Called(ctx, advertIDs)
+	return args.Get(0).(map[int][]model.Photo), args.Error(1)
+}
+
+// Insert puts a photo at the given position
+func (m *MockPhotoRepo) Insert(ctx context.Context, photo model.Photo, maxPhotos int) (model.Photo, error) {
+	args := m.Called(ctx, photo, maxPhotos)
+	if stored, ok := args.Get(0).(model.Photo); ok {
+		return stored, args.Error(1)
+	}
+	return model.Photo{}, args.Error(1)
+}
+
+// Delete removes a single photo of the advert
+func (m *MockPhotoRepo) Delete(ctx context.Context, advertID, photoID int) error {
+	args := m.Called(ctx, advertID, photoID)
+	return args.Error(0)
+}
+
+// Reorder assigns new positions following photoIDs
+func (m *MockPhotoRepo) Reorder(ctx context.Context, advertID int, photoIDs []int) error {
+	args := m.Called(ctx, advertID, photoIDs)
+	return args.Error(0)
+}
+
+// SaveVariant creates or replaces a resized variant of a photo
+func (m *MockPhotoRepo) SaveVariant(ctx context.Context, variant model.PhotoVariant) error {
+	args := m.Called(ctx, variant)
+	return args.Error(0)
+}
+
+// GetMainPhotoVariantURL returns the URL of the named variant of the main photo
+func (m *MockPhotoRepo) GetMainPhotoVariantURL(ctx context.Context, advertID int, name string) (string, error) {
+	args := m.Called(ctx, advertID, name)
+	return args.String(0), args.Error(1)
+}
+
+// ListMissingVariants returns uploaded photos lacking some variants
+func (m *MockPhotoRepo) ListMissingVariants(ctx context.Context, urlPrefix string, names []string, afterID, limit int) ([]model.Photo, error) {
+	args := m.Called(ctx, urlPrefix, names, afterID, limit)
+	return args.Get(0).([]model.Photo), args.Error(1)
+}
+
+// ListDueForCheck returns photos waiting for URL verification
+func (m *MockPhotoRepo) ListDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]model.Photo, error) {
+	args := m.Called(ctx, checkedBefore, limit)
+	if photos, ok := args.Get(0).([]model.Photo); ok {
+		return photos, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// SetCheckResult records the outcome of a URL verification
+func (m *MockPhotoRepo) SetCheckResult(ctx context.Context, photoID int, status, checkError string, checkedAt time.Time) error {
+	args := m.Called(ctx, photoID, status, checkError, checkedAt)
+	return args.Error(0)
+}
+
+func (m *MockPhotoRepo) ListUnhashed(ctx context.Context, limit int) ([]model.Photo, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]model.Photo), args.Error(1)
+}
+
+func (m *MockPhotoRepo) SetHash(ctx context.Context, photoID int, hash int64) error {
+	args := m.Called(ctx, photoID, hash)
+	return args.Error(0)
+}
+
+func (m *MockPhotoRepo) ListHashDuplicates(ctx context.Context, limit int) ([]model.DuplicatePhoto, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]model.DuplicatePhoto), args.Error(1)
+}
+
+func (m *MockPhotoRepo) HashUsedByOtherOwner(ctx context.Context, hash int64, ownerID string, advertID int) (bool, error) {
+	args := m.Called(ctx, hash, ownerID, advertID)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockFavoriteRepo implements a mock for repository.FavoriteRepo
+type MockFavoriteRepo struct {
+	mock.Mock
+}
+
+func (m *MockFavoriteRepo) Add(ctx context.Context, fav model.Favorite) error {
+	args := m.Called(ctx, fav)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteRepo) Remove(ctx context.Context, userID string, advertID int) error {
+	args := m.Called(ctx, userID, advertID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteRepo) List(ctx context.Context, userID string, limit, offset int, sortField, sortOrder string) ([]model.FavoriteAdvert, error) {
+	args := m.Called(ctx, userID, limit, offset, sortField, sortOrder)
+	return args.Get(0).([]model.FavoriteAdvert), args.Error(1)
+}
+
+func (m *MockFavoriteRepo) CountByAdvertIDs(ctx context.Context, advertIDs []int) (map[int]int, error) {
+	args := m.Called(ctx, advertIDs)
+	counts, _ := args.Get(0).(map[int]int)
+	return counts, args.Error(1)
+}
+
 func sampleAdvertModel(id int) *model.Advert {
 	return &model.Advert{
 		ID:          id,
@@ -107,7 +262,7 @@ func samplePhotos() []string {
 func TestAdvertService_Create(t *testing.T) {
 	mockAdRepo := new(MockAdvertRepo)
 	mockPhRepo := new(MockPhotoRepo)
-	svc := service.NewAdvertService(mockAdRepo, mockPhRepo)
+	svc := service.NewAdvertService(mockAdRepo, mockPhRepo, new(MockFavoriteRepo))
 
 	input := service.CreateAdvertInput{
 		Name:        "New Ad",
@@ -120,57 +275,166 @@ func TestAdvertService_Create(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Success", func(t *testing.T) {
-		// Expect CreateAdvert(ctx, *model.Advert) → returns ID = 1
+		// The photos are stored together with the advert, in one transaction
 		mockAdRepo.
-			On("CreateAdvert", mock.Anything, mock.MatchedBy(func(ad *model.Advert) bool {
-				return ad.Name == input.Name && ad.Description == input.Description && ad.Price == input.Price
+			On("Create", mock.Anything, mock.MatchedBy(func(ad model.Advert) bool {
+				return ad.Name == input.Name && ad.Description == input.Description && ad.Price == input.Price &&
+					assert.ObjectsAreEqual(input.Photos, ad.PhotoURLs)
 			})).
-			Return(1, nil)
-
-		// Then expect InsertPhotos(ctx, advertID, urls)
-		mockPhRepo.
-			On("InsertPhotos", mock.Anything, 1, input.Photos).
-			Return(nil)
+			Return(1, nil).
+			Once()
 
 		id, err := svc.Create(ctx, input)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, id)
 
 		mockAdRepo.AssertExpectations(t)
-		mockPhRepo.AssertExpectations(t)
+		mockPhRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 	})
 
-	t.Run("ErrorOnCreateAdvert", func(t *testing.T) {
+	t.Run("ErrorOnCreate", func(t *testing.T) {
 		mockAdRepo.
-			On("CreateAdvert", mock.Anything, mock.Anything).
-			Return(0, errors.New("db error"))
+			On("Create", mock.Anything, mock.Anything).
+			Return(0, errors.New("db error")).
+			Once()
 
 		id, err := svc.Create(ctx, input)
 		assert.Error(t, err)
 		assert.Equal(t, 0, id)
 
 		mockAdRepo.AssertExpectations(t)
-		mockPhRepo.AssertNotCalled(t, "InsertPhotos", mock.Anything, mock.Anything, mock.Anything)
+		mockPhRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 	})
+}
 
-	t.Run("ErrorOnInsertPhotos", func(t *testing.T) {
-		mockAdRepo.
-			On("CreateAdvert", mock.Anything, mock.Anything).
-			Return(2, nil)
+func TestAdvertService_GetByIDFields(t *testing.T) {
+	ctx := context.Background()
+	ad := sampleAdvertModel(1)
+	ad.ViewCount = 7
 
-		mockPhRepo.
-			On("InsertPhotos", mock.Anything, 2, input.Photos).
-			Return(errors.New("photo insert error"))
+	t.Run("OnlyRequestedQueries", func(t *testing.T) {
+		mockAdRepo := new(MockAdvertRepo)
+		mockPhRepo := new(MockPhotoRepo)
+		mockFavRepo := new(MockFavoriteRepo)
+		svc := service.NewAdvertService(mockAdRepo, mockPhRepo, mockFavRepo)
 
-		id, err := svc.Create(ctx, input)
-		assert.Error(t, err)
-		// Assume that on photo insertion error, service returns 0
-		assert.Equal(t, 0, id)
+		mockAdRepo.On("GetByID", mock.Anything, 1).Return(*ad, nil).Once()
+		mockPhRepo.On("GetAllPhotoURLs", mock.Anything, 1).Return(samplePhotos(), nil).Once()
+
+		detail, err := svc.GetByID(ctx, 1, service.NewAdvertFields(service.FieldName, service.FieldPhotos, service.FieldViewCount))
+		assert.NoError(t, err)
+		assert.Equal(t, service.AdvertDetail{
+			AdvertSummary: service.AdvertSummary{ID: 1, Name: ad.Name},
+			AllPhotosURLs: samplePhotos(),
+			ViewCount:     7,
+		}, detail)
 
+		mockPhRepo.AssertNotCalled(t, "GetMainPhotoURL", mock.Anything, mock.Anything)
+		mockFavRepo.AssertNotCalled(t, "CountByAdvertIDs", mock.Anything, mock.Anything)
 		mockAdRepo.AssertExpectations(t)
 		mockPhRepo.AssertExpectations(t)
 	})
+
+	t.Run("Batch", func(t *testing.T) {
+		mockAdRepo := new(MockAdvertRepo)
+		mockFavRepo := new(MockFavoriteRepo)
+		svc := service.NewAdvertService(mockAdRepo, new(MockPhotoRepo), mockFavRepo)
+
+		other := sampleAdvertModel(2)
+		mockAdRepo.On("ListByIDs", mock.Anything, []int{1, 2, 3}).Return([]model.Advert{*ad, *other}, nil).Once()
+		// Favorites of the whole batch are counted at once
+		mockFavRepo.On("CountByAdvertIDs", mock.Anything, []int{1, 2}).Return(map[int]int{1: 3}, nil).Once()
+
+		details, err := svc.GetByIDs(ctx, []int{1, 2, 3}, service.NewAdvertFields(service.FieldDescription, service.FieldFavoriteCount))
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]service.AdvertDetail{
+			1: {AdvertSummary: service.AdvertSummary{ID: 1, Locale: ad.DefaultLocale}, Description: ad.Description, FavoriteCount: 3},
+			2: {AdvertSummary: service.AdvertSummary{ID: 2, Locale: other.DefaultLocale}, Description: other.Description},
+		}, details)
+
+		mockAdRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+		mockAdRepo.AssertExpectations(t)
+		mockFavRepo.AssertExpectations(t)
+	})
+
+	t.Run("MainPhotoWithoutPhotos", func(t *testing.T) {
+		mockAdRepo := new(MockAdvertRepo)
+		mockPhRepo := new(MockPhotoRepo)
+		svc := service.NewAdvertService(mockAdRepo, mockPhRepo, new(MockFavoriteRepo))
+
+		mockAdRepo.On("List", mock.Anything, model.AdvertFilter{}, 10, 0, "id", "ASC").Return([]model.Advert{*ad}, nil).Once()
+		mockPhRepo.On("GetMainPhotoURL", mock.Anything, 1).Return("http://img1", nil).Once()
+		mockPhRepo.On("GetMainPhotoVariantURL", mock.Anything, 1, service.ThumbVariant).Return("", nil).Once()
+
+		details, err := svc.List(ctx, model.AdvertFilter{}, 1, "", "", service.NewAdvertFields(service.FieldMainPhoto, service.FieldCreatedAt))
+		assert.NoError(t, err)
+		assert.Equal(t, []service.AdvertDetail{{
+			AdvertSummary: service.AdvertSummary{ID: 1, MainPhotoURL: "http://img1", CreatedAt: ad.CreatedAt},
+		}}, details)
+
+		mockPhRepo.AssertNotCalled(t, "GetAllPhotoURLs", mock.Anything, mock.Anything)
+		mockAdRepo.AssertExpectations(t)
+		mockPhRepo.AssertExpectations(t)
+	})
+}
+
+func TestParseAdvertFields(t *testing.T) {
+	fields, err := service.ParseAdvertFields("name, price,photos", service.SummaryFields)
+	assert.NoError(t, err)
+	assert.Equal(t, service.NewAdvertFields(service.FieldName, service.FieldPrice, service.FieldPhotos), fields)
+
+	fields, err = service.ParseAdvertFields("", service.SummaryFields)
+	assert.NoError(t, err)
+	assert.Equal(t, service.SummaryFields, fields)
+
+	fields, err = service.ParseAdvertFields("true", service.SummaryFields)
+	assert.NoError(t, err)
+	assert.Equal(t, service.AllAdvertFields, fields)
+
+	for _, raw := range []string{"name,owner", "name,", "1"} {
+		_, err = service.ParseAdvertFields(raw, service.SummaryFields)
+		assert.ErrorIs(t, err, error_message.ErrWrongFieldsParam, raw)
+	}
 }
 
 func strPtr(s string) *string     { return &s }
 func floatPtr(f float64) *float64 { return &f }
+
+func TestAdvertService_ValidatesPayload(t *testing.T) {
+	ctx := context.Background()
+	mockAdRepo := new(MockAdvertRepo)
+	svc := service.NewAdvertService(mockAdRepo, new(MockPhotoRepo), new(MockFavoriteRepo))
+
+	// Every transport gets the same limits, not only the REST handlers
+	_, err := svc.Create(ctx, service.CreateAdvertInput{
+		Name:        strings.Repeat("a", 5000),
+		Description: "Red bike",
+		Photos:      []string{"http://1", "http://2", "http://3", "http://4", "http://5"},
+		Price:       100,
+	})
+	assert.ErrorIs(t, err, error_message.ErrWrongTitle)
+	assert.ErrorIs(t, err, error_message.ErrWrongPhotos)
+
+	description := strings.Repeat("a", 1001)
+	err = svc.Update(ctx, 1, service.UpdateAdvertInput{Description: &description})
+	assert.ErrorIs(t, err, error_message.ErrWrongDescription)
+
+	mockAdRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockAdRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestAdvertService_DeletedMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	mockAdRepo := new(MockAdvertRepo)
+	svc := service.NewAdvertService(mockAdRepo, new(MockPhotoRepo), new(MockFavoriteRepo))
+
+	// The advert is read, then deleted by another request before the change is stored
+	mockAdRepo.On("GetByID", mock.Anything, 1).Return(model.Advert{ID: 1, Name: "Bike", DefaultLocale: "en"}, nil).Twice()
+	mockAdRepo.On("Update", mock.Anything, mock.Anything).Return(sql.ErrNoRows).Once()
+	mockAdRepo.On("Delete", mock.Anything, 1).Return(sql.ErrNoRows).Once()
+
+	price := 50.0
+	assert.ErrorIs(t, svc.Update(ctx, 1, service.UpdateAdvertInput{Price: &price}), error_message.ErrAdvertNotFound)
+	assert.ErrorIs(t, svc.Delete(ctx, 1), error_message.ErrAdvertNotFound)
+	mockAdRepo.AssertExpectations(t)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// AdvertChange is a single event of the advert change stream.
+// ID is the outbox event ID, Data is the AdvertSummary of a created or updated
+// advert and {"id": ...} of a deleted one.
+type AdvertChange struct {
+	ID       int64           `json:"id"`
+	Event    string          `json:"event"`
+	AdvertID int             `json:"advert_id"`
+	Price    *float64        `json:"price,omitempty"`
+	Data     json.RawMessage `json:"data"`
+	// EventID is assigned by the stream in the order of delivery and sent as the SSE id
+	EventID string `json:"-"`
+}
+
+// AdvertSubscription is a client of the change stream.
+type AdvertSubscription struct {
+	// Replay holds the buffered changes after the requested event ID
+	Replay []AdvertChange
+	// Gap is set when the changes after the requested event ID are no longer
+	// buffered or were never buffered here (a restart or another replica)
+	Gap bool
+	// C delivers new changes; it is closed when the client is too slow
+	// and should reconnect with the last received ID
+	C <-chan AdvertChange
+
+	close func()
+}
+
+// Close stops the delivery of changes to the subscription.
+func (s *AdvertSubscription) Close() {
+	if s.close != nil {
+		s.close()
+	}
+}
+
+// AdvertStream fans advert changes from the change feed out to subscribers.
+type AdvertStream interface {
+	// Subscribe registers a client interested in changes matching the price range of filter.
+	// Adverts have no category, so the price range is the only filter. The changes
+	// delivered after lastEventID (an EventID, "" for none) are replayed from the buffer.
+	Subscribe(lastEventID string, filter model.AdvertFilter) (*AdvertSubscription, error)
+
+	// Run listens to the change feed until ctx is cancelled.
+	Run(ctx context.Context)
+}
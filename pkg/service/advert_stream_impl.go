@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+// streamClientBuffer is how many changes may wait for a slow client before it is dropped
+const streamClientBuffer = 64
+
+type streamClient struct {
+	filter model.AdvertFilter
+	ch     chan AdvertChange
+}
+
+type advertStream struct {
+	feed repository.ChangeFeed
+	// epoch tells the event IDs of this process from those of a previous run or another replica
+	epoch string
+
+	mu sync.Mutex
+	// seq numbers the changes in the order they are broadcast; outbox IDs can't be
+	// used for resume since they have gaps and are not published in order
+	seq int64
+	// buffer is a ring of the last changes, the one with sequence number n is at (n-1) % len(buffer)
+	buffer   []AdvertChange
+	buffered int
+	clients  map[*streamClient]struct{}
+}
+
+// NewAdvertStream keeps the last replaySize changes for Last-Event-ID resume.
+func NewAdvertStream(feed repository.ChangeFeed, replaySize int) AdvertStream {
+	if replaySize < 1 {
+		replaySize = 1000
+	}
+	return &advertStream{
+		feed:    feed,
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		buffer:  make([]AdvertChange, replaySize),
+		clients: make(map[*streamClient]struct{}),
+	}
+}
+
+func (s *advertStream) Subscribe(lastEventID string, filter model.AdvertFilter) (*AdvertSubscription, error) {
+	var (
+		epoch   string
+		lastSeq int64
+	)
+	if lastEventID != "" {
+		var (
+			seqText string
+			ok      bool
+			err     error
+		)
+		epoch, seqText, ok = strings.Cut(lastEventID, "-")
+		lastSeq, err = strconv.ParseInt(seqText, 10, 64)
+		if !ok || err != nil || lastSeq < 0 {
+			return nil, error_message.ErrWrongLastEventID
+		}
+	}
+
+	client := &streamClient{filter: filter, ch: make(chan AdvertChange, streamClientBuffer)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub := &AdvertSubscription{C: client.ch}
+	if lastEventID != "" {
+		// An ID of another run or replica says nothing about what this buffer holds
+		first := s.seq - int64(s.buffered) + 1
+		sub.Gap = epoch != s.epoch || lastSeq < first-1 || lastSeq > s.seq
+		if !sub.Gap {
+			for seq := lastSeq + 1; seq <= s.seq; seq++ {
+				change := s.buffer[(seq-1)%int64(len(s.buffer))]
+				if matchesChange(filter, change) {
+					sub.Replay = append(sub.Replay, change)
+				}
+			}
+		}
+	}
+	s.clients[client] = struct{}{}
+	sub.close = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.clients[client]; ok {
+			delete(s.clients, client)
+			close(client.ch)
+		}
+	}
+	return sub, nil
+}
+
+// broadcast numbers and buffers the change and passes it to every matching client.
+func (s *advertStream) broadcast(change AdvertChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	change.EventID = s.epoch + "-" + strconv.FormatInt(s.seq, 10)
+	s.buffer[(s.seq-1)%int64(len(s.buffer))] = change
+	if s.buffered < len(s.buffer) {
+		s.buffered++
+	}
+
+	for client := range s.clients {
+		if !matchesChange(client.filter, change) {
+			continue
+		}
+		select {
+		case client.ch <- change:
+		default:
+			// Too slow: drop the client, it resumes from its last event ID
+			delete(s.clients, client)
+			close(client.ch)
+		}
+	}
+}
+
+// matchesChange reports whether a change falls into the price range of filter.
+// Deletions carry no price and reach every client.
+func matchesChange(filter model.AdvertFilter, change AdvertChange) bool {
+	if change.Price == nil {
+		return true
+	}
+	if filter.MinPrice != nil && *change.Price < *filter.MinPrice {
+		return false
+	}
+	if filter.MaxPrice != nil && *change.Price > *filter.MaxPrice {
+		return false
+	}
+	return true
+}
+
+func (s *advertStream) Run(ctx context.Context) {
+	listenChanges(ctx, s.feed, "advert stream", s.broadcast)
+}
+
+// listenChanges calls fn for every change on the feed until ctx is cancelled,
+// listening again after a failure.
+func listenChanges(ctx context.Context, feed repository.ChangeFeed, name string, fn func(change AdvertChange)) {
+	for {
+		err := feed.Listen(ctx, func(msg string) {
+			var change AdvertChange
+			if err := json.Unmarshal([]byte(msg), &change); err != nil {
+				log.Printf("%s: invalid advert change %q: %v", name, msg, err)
+				return
+			}
+			fn(change)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("%s: advert change feed stopped: %v", name, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
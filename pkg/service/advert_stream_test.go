@@ -0,0 +1,192 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// chanFeed is an in-process repository.ChangeFeed
+type chanFeed chan string
+
+func (f chanFeed) Publish(_ context.Context, msg string) error {
+	f <- msg
+	return nil
+}
+
+func (f chanFeed) Listen(ctx context.Context, fn func(msg string)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg := <-f:
+			fn(msg)
+		}
+	}
+}
+
+func publishChange(t *testing.T, feed chanFeed, id int64, price float64) {
+	t.Helper()
+	msg, _ := json.Marshal(service.AdvertChange{
+		ID: id, Event: model.EventAdvertUpdated, AdvertID: int(id), Price: &price,
+		Data: json.RawMessage(fmt.Sprintf(`{"id":%d}`, id)),
+	})
+	assert.NoError(t, feed.Publish(context.Background(), string(msg)))
+}
+
+func receiveChange(t *testing.T, c <-chan service.AdvertChange) service.AdvertChange {
+	t.Helper()
+	select {
+	case change := <-c:
+		return change
+	case <-time.After(time.Second):
+		t.Fatal("no change received")
+		return service.AdvertChange{}
+	}
+}
+
+func subscribe(t *testing.T, stream service.AdvertStream, lastEventID string, filter model.AdvertFilter) *service.AdvertSubscription {
+	t.Helper()
+	sub, err := stream.Subscribe(lastEventID, filter)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(sub.Close)
+	return sub
+}
+
+func TestAdvertStream_FilterAndReplay(t *testing.T) {
+	feed := make(chanFeed)
+	stream := service.NewAdvertStream(feed, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go stream.Run(ctx)
+
+	min := 100.0
+	cheap := subscribe(t, stream, "", model.AdvertFilter{})
+	expensive := subscribe(t, stream, "", model.AdvertFilter{MinPrice: &min})
+
+	// Outbox IDs have gaps and arrive out of order, the stream numbers changes itself
+	publishChange(t, feed, 7, 50)
+	publishChange(t, feed, 4, 150)
+	first := receiveChange(t, cheap.C)
+	assert.Equal(t, int64(7), first.ID)
+	assert.Equal(t, int64(4), receiveChange(t, cheap.C).ID)
+	assert.Equal(t, int64(4), receiveChange(t, expensive.C).ID)
+
+	t.Run("Resume", func(t *testing.T) {
+		sub := subscribe(t, stream, first.EventID, model.AdvertFilter{})
+		assert.False(t, sub.Gap)
+		assert.Len(t, sub.Replay, 1)
+		assert.Equal(t, int64(4), sub.Replay[0].ID)
+	})
+
+	t.Run("Gap", func(t *testing.T) {
+		var second service.AdvertChange
+		// The buffer keeps 3 changes, so the first two are evicted
+		for id := int64(10); id <= 12; id++ {
+			publishChange(t, feed, id, 10)
+			change := receiveChange(t, cheap.C)
+			if id == 10 {
+				second = change
+			}
+		}
+		sub := subscribe(t, stream, first.EventID, model.AdvertFilter{})
+		assert.True(t, sub.Gap)
+		assert.Empty(t, sub.Replay)
+
+		sub = subscribe(t, stream, second.EventID, model.AdvertFilter{})
+		assert.False(t, sub.Gap)
+		assert.Len(t, sub.Replay, 2)
+	})
+
+	t.Run("WrongID", func(t *testing.T) {
+		for _, id := range []string{"7", "x-y", "x--1"} {
+			_, err := stream.Subscribe(id, model.AdvertFilter{})
+			assert.ErrorIs(t, err, error_message.ErrWrongLastEventID, id)
+		}
+	})
+}
+
+func TestAdvertStream_GapAfterRestart(t *testing.T) {
+	feed := make(chanFeed)
+	old := service.NewAdvertStream(feed, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	go old.Run(ctx)
+	live := subscribe(t, old, "", model.AdvertFilter{})
+	publishChange(t, feed, 1, 10)
+	last := receiveChange(t, live.C)
+	cancel()
+
+	// A restarted process or another replica numbers its changes anew
+	feed = make(chanFeed)
+	stream := service.NewAdvertStream(feed, 3)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go stream.Run(ctx)
+
+	sub := subscribe(t, stream, last.EventID, model.AdvertFilter{})
+	assert.True(t, sub.Gap)
+
+	live = subscribe(t, stream, "", model.AdvertFilter{})
+	publishChange(t, feed, 2, 10)
+	receiveChange(t, live.C)
+	sub = subscribe(t, stream, last.EventID, model.AdvertFilter{})
+	assert.True(t, sub.Gap)
+	assert.Empty(t, sub.Replay)
+}
+
+func TestAdvertStream_DropsSlowClient(t *testing.T) {
+	feed := make(chanFeed)
+	stream := service.NewAdvertStream(feed, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go stream.Run(ctx)
+
+	slow, err := stream.Subscribe("", model.AdvertFilter{})
+	assert.NoError(t, err)
+	for id := int64(1); id <= 100; id++ {
+		publishChange(t, feed, id, 10)
+	}
+
+	received := 0
+	for range slow.C {
+		received++
+	}
+	assert.Less(t, received, 100)
+	// Closing a dropped subscription is safe
+	slow.Close()
+}
+
+func TestChangeFeedPublisher(t *testing.T) {
+	mockPhRepo := new(MockPhotoRepo)
+	feed := make(chanFeed, 2)
+	publisher := service.NewChangeFeedPublisher(mockPhRepo, feed)
+	ctx := context.Background()
+
+	mockPhRepo.On("GetMainPhotoURL", mock.Anything, 9).Return("", sql.ErrNoRows).Once()
+	mockPhRepo.On("GetMainPhotoVariantURL", mock.Anything, 9, service.ThumbVariant).Return("", nil).Once()
+
+	err := publisher.Publish(ctx, model.OutboxEvent{ID: 4, AggregateID: 9, Event: model.EventAdvertCreated, Payload: `{"id":9,"name":"Bike","price":120}`})
+	assert.NoError(t, err)
+	assert.NoError(t, publisher.Publish(ctx, model.OutboxEvent{ID: 5, AggregateID: 9, Event: model.EventAdvertDeleted, Payload: `{"id":9}`}))
+
+	var created, deleted service.AdvertChange
+	assert.NoError(t, json.Unmarshal([]byte(<-feed), &created))
+	assert.NoError(t, json.Unmarshal([]byte(<-feed), &deleted))
+	assert.Equal(t, 120.0, *created.Price)
+	assert.JSONEq(t, `{"id":9,"name":"Bike","main_photo_url":"","price":120}`, string(created.Data))
+	assert.Nil(t, deleted.Price)
+	assert.JSONEq(t, `{"id":9}`, string(deleted.Data))
+	mockPhRepo.AssertExpectations(t)
+}
@@ -0,0 +1,55 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAdvertService_Export(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	mockPhRepo := new(MockPhotoRepo)
+	svc := service.NewAdvertService(mockAdRepo, mockPhRepo, new(MockFavoriteRepo))
+
+	created := time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC)
+	minPrice := 5.0
+	filter := model.AdvertFilter{MinPrice: &minPrice}
+
+	t.Run("Success", func(t *testing.T) {
+		// The repository receives the resolved column and order, and feeds rows back one by one
+		mockAdRepo.
+			On("Stream", mock.Anything, filter, "price", "DESC", mock.AnythingOfType("int"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				fn := args.Get(5).(func(model.Advert, []string) error)
+				_ = fn(model.Advert{ID: 1, Name: "A", Description: "Desc", Price: 10, CreatedAt: created}, []string{"http://a1", "http://a2"})
+				_ = fn(model.Advert{ID: 2, Name: "B", Price: 5, CreatedAt: created}, []string{})
+			}).
+			Return(nil).
+			Once()
+
+		var rows []service.AdvertExportRow
+		err := svc.Export(context.Background(), filter, "price", "desc", func(row service.AdvertExportRow) error {
+			rows = append(rows, row)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []service.AdvertExportRow{
+			{ID: 1, Name: "A", Description: "Desc", Price: 10, CreatedAt: created, PhotoURLs: []string{"http://a1", "http://a2"}},
+			{ID: 2, Name: "B", Price: 5, CreatedAt: created, PhotoURLs: []string{}},
+		}, rows)
+		mockAdRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidSort", func(t *testing.T) {
+		err := svc.Export(context.Background(), model.AdvertFilter{}, "name", "asc", func(service.AdvertExportRow) error { return nil })
+		assert.Error(t, err)
+		mockAdRepo.AssertNumberOfCalls(t, "Stream", 1)
+	})
+}
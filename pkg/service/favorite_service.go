@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// FavoriteItem is a single entry of the user's watchlist.
+// Status is model.FavoriteStatusAvailable, FavoriteStatusPriceChanged or FavoriteStatusDeleted;
+// Price is the current price and is nil for a deleted advert.
+type FavoriteItem struct {
+	AdvertID     int       `json:"advert_id"`
+	Name         string    `json:"name"`
+	MainPhotoURL string    `json:"main_photo_url"`
+	Price        *float64  `json:"price"`
+	PriceAtAdd   float64   `json:"price_at_add"`
+	Status       string    `json:"status"`
+	FavoritedAt  time.Time `json:"favorited_at"`
+}
+
+// FavoriteService describes the business logic of the buyers' watchlist.
+type FavoriteService interface {
+	// Add bookmarks an existing advert; adding it again is a no-op.
+	Add(ctx context.Context, userID string, advertID int) error
+
+	// Remove deletes a bookmark, also for adverts that no longer exist.
+	Remove(ctx context.Context, userID string, advertID int) error
+
+	// List returns a page of the user's favorites; paging and sorting
+	// follow the same rules as AdvertService.List ("date" is the time of favoriting).
+	List(ctx context.Context, userID string, page int, sortField, sortOrder string) ([]FavoriteItem, error)
+}
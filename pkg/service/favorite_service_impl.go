@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+type favoriteService struct {
+	advertRepo   repository.AdvertRepo
+	favoriteRepo repository.FavoriteRepo
+	invalidator  AdvertInvalidator
+}
+
+// NewFavoriteService creates a FavoriteService; inv is told about the adverts whose
+// favorite count changed.
+func NewFavoriteService(ar repository.AdvertRepo, fr repository.FavoriteRepo, inv AdvertInvalidator) FavoriteService {
+	return &favoriteService{
+		advertRepo:   ar,
+		favoriteRepo: fr,
+		invalidator:  inv,
+	}
+}
+
+func (s *favoriteService) Add(ctx context.Context, userID string, advertID int) error {
+	if userID == "" {
+		return error_message.ErrMissingUserID
+	}
+	advert, err := s.advertRepo.GetByID(ctx, advertID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return error_message.ErrAdvertNotFound
+		}
+		return fmt.Errorf("service.Add: advertRepo.GetByID (id=%d): %w", advertID, err)
+	}
+
+	err = s.favoriteRepo.Add(ctx, model.Favorite{
+		UserID:     userID,
+		AdvertID:   advert.ID,
+		Name:       advert.Name,
+		PriceAtAdd: advert.Price,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidator.Invalidate(ctx, advertID)
+	return nil
+}
+
+func (s *favoriteService) Remove(ctx context.Context, userID string, advertID int) error {
+	if userID == "" {
+		return error_message.ErrMissingUserID
+	}
+	if err := s.favoriteRepo.Remove(ctx, userID, advertID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return error_message.ErrFavoriteNotFound
+		}
+		return fmt.Errorf("service.Remove: favoriteRepo.Remove (id=%d): %w", advertID, err)
+	}
+	s.invalidator.Invalidate(ctx, advertID)
+	return nil
+}
+
+func (s *favoriteService) List(ctx context.Context, userID string, page int, sortField, sortOrder string) ([]FavoriteItem, error) {
+	if userID == "" {
+		return nil, error_message.ErrMissingUserID
+	}
+	limit, offset, err := pageBounds(page)
+	if err != nil {
+		return nil, err
+	}
+	column, direction, err := resolveSort(sortField, sortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	favs, err := s.favoriteRepo.List(ctx, userID, limit, offset, column, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]FavoriteItem, 0, len(favs))
+	for _, fav := range favs {
+		status := model.FavoriteStatusAvailable
+		switch {
+		case fav.CurrentPrice == nil:
+			status = model.FavoriteStatusDeleted
+		case *fav.CurrentPrice != fav.PriceAtAdd:
+			status = model.FavoriteStatusPriceChanged
+		}
+		items = append(items, FavoriteItem{
+			AdvertID:     fav.AdvertID,
+			Name:         fav.Name,
+			MainPhotoURL: fav.MainPhotoURL,
+			Price:        fav.CurrentPrice,
+			PriceAtAdd:   fav.PriceAtAdd,
+			Status:       status,
+			FavoritedAt:  fav.CreatedAt,
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,99 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// recordingInvalidator remembers the invalidated advert IDs
+type recordingInvalidator struct {
+	ids []int
+}
+
+func (r *recordingInvalidator) Invalidate(_ context.Context, advertIDs ...int) {
+	r.ids = append(r.ids, advertIDs...)
+}
+
+func TestFavoriteService_Add(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	mockFavRepo := new(MockFavoriteRepo)
+	invalidator := &recordingInvalidator{}
+	svc := service.NewFavoriteService(mockAdRepo, mockFavRepo, invalidator)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockAdRepo.On("GetByID", mock.Anything, 1).Return(*sampleAdvertModel(1), nil).Once()
+		mockFavRepo.On("Add", mock.Anything, mock.MatchedBy(func(f model.Favorite) bool {
+			return f.UserID == "alice" && f.AdvertID == 1 && f.Name == "Test name" && f.PriceAtAdd == sampleAdvertModel(1).Price
+		})).Return(nil).Once()
+
+		assert.NoError(t, svc.Add(ctx, "alice", 1))
+		// The favorite count of cached reads is stale now
+		assert.Equal(t, []int{1}, invalidator.ids)
+	})
+
+	t.Run("AdvertNotFound", func(t *testing.T) {
+		mockAdRepo.On("GetByID", mock.Anything, 2).Return(model.Advert{}, sql.ErrNoRows).Once()
+
+		assert.ErrorIs(t, svc.Add(ctx, "alice", 2), error_message.ErrAdvertNotFound)
+		assert.Equal(t, []int{1}, invalidator.ids)
+	})
+
+	t.Run("Anonymous", func(t *testing.T) {
+		assert.ErrorIs(t, svc.Add(ctx, "", 1), error_message.ErrMissingUserID)
+	})
+
+	mockAdRepo.AssertExpectations(t)
+	mockFavRepo.AssertExpectations(t)
+}
+
+func TestFavoriteService_Remove(t *testing.T) {
+	mockFavRepo := new(MockFavoriteRepo)
+	svc := service.NewFavoriteService(new(MockAdvertRepo), mockFavRepo, service.NoInvalidation)
+	ctx := context.Background()
+
+	mockFavRepo.On("Remove", mock.Anything, "alice", 1).Return(nil).Once()
+	mockFavRepo.On("Remove", mock.Anything, "alice", 2).Return(sql.ErrNoRows).Once()
+
+	assert.NoError(t, svc.Remove(ctx, "alice", 1))
+	assert.ErrorIs(t, svc.Remove(ctx, "alice", 2), error_message.ErrFavoriteNotFound)
+	mockFavRepo.AssertExpectations(t)
+}
+
+func TestFavoriteService_List(t *testing.T) {
+	mockFavRepo := new(MockFavoriteRepo)
+	svc := service.NewFavoriteService(new(MockAdvertRepo), mockFavRepo, service.NoInvalidation)
+	ctx := context.Background()
+
+	same, changed := 100.0, 80.0
+	mockFavRepo.On("List", mock.Anything, "alice", 10, 10, "price", "DESC").Return([]model.FavoriteAdvert{
+		{Favorite: model.Favorite{AdvertID: 1, PriceAtAdd: 100}, CurrentPrice: &same},
+		{Favorite: model.Favorite{AdvertID: 2, PriceAtAdd: 100}, CurrentPrice: &changed},
+		{Favorite: model.Favorite{AdvertID: 3, Name: "Gone", PriceAtAdd: 50}},
+	}, nil).Once()
+
+	items, err := svc.List(ctx, "alice", 2, "price", "desc")
+	assert.NoError(t, err)
+	if assert.Len(t, items, 3) {
+		assert.Equal(t, model.FavoriteStatusAvailable, items[0].Status)
+		assert.Equal(t, model.FavoriteStatusPriceChanged, items[1].Status)
+		assert.Equal(t, 80.0, *items[1].Price)
+		assert.Equal(t, model.FavoriteStatusDeleted, items[2].Status)
+		assert.Nil(t, items[2].Price)
+		assert.Equal(t, "Gone", items[2].Name)
+	}
+
+	_, err = svc.List(ctx, "alice", 0, "", "")
+	assert.Error(t, err)
+	_, err = svc.List(ctx, "alice", 1, "name", "asc")
+	assert.Error(t, err)
+	mockFavRepo.AssertExpectations(t)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ImportFormat is the encoding of a bulk import file.
+type ImportFormat string
+
+const (
+	// ImportFormatCSV — comma-separated file with a header row:
+	// name,description,price,photos (photo URLs separated by "|") and an optional
+	// locale column. Translations cannot be imported from CSV.
+	ImportFormatCSV ImportFormat = "csv"
+	// ImportFormatNDJSON — one JSON object per line with the same fields
+	// as CreateAdvertRequest, including locale and translations.
+	ImportFormatNDJSON ImportFormat = "ndjson"
+)
+
+// ImportJobStatus describes the lifecycle of an import job.
+type ImportJobStatus string
+
+const (
+	ImportJobPending ImportJobStatus = "pending"
+	ImportJobRunning ImportJobStatus = "running"
+	ImportJobDone    ImportJobStatus = "done"
+)
+
+// ImportRowResult is the outcome of a single imported row.
+// Row is 1-based and does not count the CSV header.
+// ID is set only when the advert was actually created.
+type ImportRowResult struct {
+	Row   int    `json:"row"`
+	OK    bool   `json:"ok"`
+	ID    int    `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ImportReport aggregates per-row results of an import.
+type ImportReport struct {
+	Total     int               `json:"total"`
+	Processed int               `json:"processed"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Rows      []ImportRowResult `json:"rows"`
+}
+
+// ImportJob is a tracked bulk import.
+// Small files are processed inline and returned already done,
+// large ones run in the background and have to be polled via GetJob.
+// Jobs live in the memory of one instance: they are lost on restart,
+// together with the rows a background job has not processed yet.
+type ImportJob struct {
+	ID         string          `json:"id"`
+	Status     ImportJobStatus `json:"status"`
+	DryRun     bool            `json:"dry_run"`
+	CreatedAt  time.Time       `json:"created_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+	Report     ImportReport    `json:"report"`
+}
+
+// ImportService describes bulk creation of adverts from a file.
+type ImportService interface {
+	// Import parses r in the given format and creates an advert owned by ownerID for
+	// every valid row, applying the same validation as AdvertService.Create.
+	// Rows with fields the format does not define are rejected.
+	// If dryRun == true, rows are only validated and nothing is written.
+	Import(ctx context.Context, ownerID string, format ImportFormat, r io.Reader, dryRun bool) (ImportJob, error)
+
+	// GetJob returns the current state of an import job by its ID.
+	// Finished jobs are kept for an hour.
+	GetJob(ctx context.Context, id string) (ImportJob, error)
+
+	// Run drops expired jobs periodically until ctx is cancelled.
+	Run(ctx context.Context)
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+)
+
+// importJobTTL is how long finished jobs stay available for polling.
+const importJobTTL = time.Hour
+
+// importPruneInterval is how often Run looks for expired jobs.
+const importPruneInterval = time.Minute
+
+// maxNDJSONLine limits the size of a single NDJSON row.
+const maxNDJSONLine = 1 << 20
+
+// importRow is a parsed row waiting to be validated and created.
+// err is set when the row could not be decoded at all.
+type importRow struct {
+	row   int
+	input CreateAdvertInput
+	err   error
+}
+
+type importService struct {
+	advertSvc           AdvertService
+	backgroundThreshold int
+	maxRows             int
+
+	mu   sync.RWMutex
+	jobs map[string]*ImportJob
+}
+
+// NewImportService creates an ImportService on top of advertSvc.
+// Files with more than backgroundThreshold rows are processed in the background;
+// files with more than maxRows rows are rejected.
+func NewImportService(advertSvc AdvertService, backgroundThreshold, maxRows int) ImportService {
+	return &importService{
+		advertSvc:           advertSvc,
+		backgroundThreshold: backgroundThreshold,
+		maxRows:             maxRows,
+		jobs:                make(map[string]*ImportJob),
+	}
+}
+
+func (s *importService) Import(ctx context.Context, ownerID string, format ImportFormat, r io.Reader, dryRun bool) (ImportJob, error) {
+	rows, err := parseImportRows(format, r, s.maxRows)
+	if err != nil {
+		return ImportJob{}, err
+	}
+	for i := range rows {
+		rows[i].input.OwnerID = ownerID
+	}
+
+	id, err := newImportJobID()
+	if err != nil {
+		return ImportJob{}, fmt.Errorf("service.Import: generate job id: %w", err)
+	}
+	job := &ImportJob{
+		ID:        id,
+		Status:    ImportJobPending,
+		DryRun:    dryRun,
+		CreatedAt: time.Now(),
+		Report: ImportReport{
+			Total: len(rows),
+			Rows:  make([]ImportRowResult, 0, len(rows)),
+		},
+	}
+
+	s.mu.Lock()
+	s.jobs[job.ID] = job
+	s.mu.Unlock()
+
+	if len(rows) <= s.backgroundThreshold {
+		s.run(ctx, job, rows)
+		return s.GetJob(ctx, job.ID)
+	}
+
+	snapshot, err := s.GetJob(ctx, job.ID)
+	// The request context is cancelled as soon as the response is sent,
+	// so the background job must not depend on it.
+	go s.run(context.Background(), job, rows)
+	return snapshot, err
+}
+
+func (s *importService) GetJob(_ context.Context, id string) (ImportJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return ImportJob{}, error_message.ErrImportJobNotFound
+	}
+	snapshot := *job
+	snapshot.Report.Rows = append([]ImportRowResult(nil), job.Report.Rows...)
+	return snapshot, nil
+}
+
+// run validates or creates every row and records the result on job.
+func (s *importService) run(ctx context.Context, job *ImportJob, rows []importRow) {
+	s.mu.Lock()
+	job.Status = ImportJobRunning
+	s.mu.Unlock()
+
+	for _, row := range rows {
+		result := ImportRowResult{Row: row.row}
+		err := row.err
+		if err == nil {
+			if job.DryRun {
+				_, _, err = validateCreateInput(row.input)
+			} else {
+				result.ID, err = s.advertSvc.Create(ctx, row.input)
+			}
+		}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.OK = true
+		}
+
+		s.mu.Lock()
+		job.Report.Rows = append(job.Report.Rows, result)
+		job.Report.Processed++
+		if result.OK {
+			job.Report.Succeeded++
+		} else {
+			job.Report.Failed++
+		}
+		s.mu.Unlock()
+	}
+
+	finished := time.Now()
+	s.mu.Lock()
+	job.Status = ImportJobDone
+	job.FinishedAt = &finished
+	s.mu.Unlock()
+}
+
+func (s *importService) Run(ctx context.Context) {
+	ticker := time.NewTicker(importPruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.prune(now)
+		}
+	}
+}
+
+// prune drops finished jobs older than importJobTTL.
+func (s *importService) prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, job := range s.jobs {
+		if job.FinishedAt != nil && now.Sub(*job.FinishedAt) > importJobTTL {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+func newImportJobID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// parseImportRows decodes r into rows. Malformed rows are returned with err set,
+// only problems with the file as a whole are reported as an error.
+func parseImportRows(format ImportFormat, r io.Reader, maxRows int) ([]importRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		return parseCSVRows(r, maxRows)
+	case ImportFormatNDJSON:
+		return parseNDJSONRows(r, maxRows)
+	default:
+		return nil, error_message.ErrWrongImportFormat
+	}
+}
+
+var importCSVColumns = []string{"name", "description", "price", "photos"}
+
+// importCSVOptionalColumns may be present in the CSV header, any other column is rejected.
+var importCSVOptionalColumns = []string{"locale"}
+
+func parseCSVRows(r io.Reader, maxRows int) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		// A failed read of the body is not a problem with the header
+		var parseErr *csv.ParseError
+		if !errors.Is(err, io.EOF) && !errors.As(err, &parseErr) {
+			return nil, err
+		}
+		return nil, error_message.ErrWrongImportHeader
+	}
+	columns := make(map[string]int, len(header))
+	for idx, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(importCSVColumns, name) && !slices.Contains(importCSVOptionalColumns, name) {
+			return nil, error_message.ErrWrongImportHeader
+		}
+		columns[name] = idx
+	}
+	for _, name := range importCSVColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, error_message.ErrWrongImportHeader
+		}
+	}
+
+	var rows []importRow
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if len(rows) >= maxRows {
+			return nil, error_message.ErrTooManyImportRows
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, importRow{row: n, err: parseErr.Err})
+			continue
+		}
+
+		price, err := strconv.ParseFloat(strings.TrimSpace(record[columns["price"]]), 64)
+		if err != nil {
+			rows = append(rows, importRow{row: n, err: error_message.ErrWrongPriceFormat})
+			continue
+		}
+		var photos []string
+		for _, url := range strings.Split(record[columns["photos"]], "|") {
+			if url = strings.TrimSpace(url); url != "" {
+				photos = append(photos, url)
+			}
+		}
+		var locale string
+		if idx, ok := columns["locale"]; ok {
+			locale = strings.TrimSpace(record[idx])
+		}
+		rows = append(rows, importRow{
+			row: n,
+			input: CreateAdvertInput{
+				Name:        strings.TrimSpace(record[columns["name"]]),
+				Description: strings.TrimSpace(record[columns["description"]]),
+				Photos:      photos,
+				Price:       price,
+				Locale:      locale,
+			},
+		})
+	}
+	return rows, nil
+}
+
+func parseNDJSONRows(r io.Reader, maxRows int) ([]importRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLine)
+
+	var rows []importRow
+	n := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		n++
+		if len(rows) >= maxRows {
+			return nil, error_message.ErrTooManyImportRows
+		}
+
+		rows = append(rows, parseNDJSONRow(n, []byte(line)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// importNDJSONFields are the fields an NDJSON row may contain.
+var importNDJSONFields = []string{"name", "description", "photos", "price", "locale", "translations"}
+
+// parseNDJSONRow decodes a single NDJSON row. Fields outside importNDJSONFields,
+// such as owner_id, are rejected rather than silently dropped.
+func parseNDJSONRow(n int, line []byte) importRow {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return importRow{row: n, err: error_message.ErrBadRequestBody}
+	}
+	for name := range fields {
+		if !slices.Contains(importNDJSONFields, name) {
+			return importRow{row: n, err: error_message.ErrUnknownImportField}
+		}
+	}
+
+	var payload struct {
+		Name         string   `json:"name"`
+		Description  string   `json:"description"`
+		Photos       []string `json:"photos"`
+		Price        float64  `json:"price"`
+		Locale       string   `json:"locale"`
+		Translations map[string]struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"translations"`
+	}
+	if err := json.Unmarshal(line, &payload); err != nil {
+		return importRow{row: n, err: error_message.ErrBadRequestBody}
+	}
+	var translations map[string]AdvertText
+	if len(payload.Translations) > 0 {
+		translations = make(map[string]AdvertText, len(payload.Translations))
+		for locale, text := range payload.Translations {
+			translations[locale] = AdvertText{Name: text.Name, Description: text.Description}
+		}
+	}
+	return importRow{
+		row: n,
+		input: CreateAdvertInput{
+			Name:         payload.Name,
+			Description:  payload.Description,
+			Photos:       payload.Photos,
+			Price:        payload.Price,
+			Locale:       payload.Locale,
+			Translations: translations,
+		},
+	}
+}
@@ -0,0 +1,114 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportService_DryRunCSV(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	mockPhRepo := new(MockPhotoRepo)
+	svc := service.NewImportService(service.NewAdvertService(mockAdRepo, mockPhRepo, new(MockFavoriteRepo)), 100, 1000)
+
+	csvBody := "name,description,price,photos\n" +
+		"Bike,Red bike,150,http://img1|http://img2\n" +
+		",No name,10,http://img3\n" +
+		"Chair,Wooden,abc,\n"
+
+	job, err := svc.Import(context.Background(), "", service.ImportFormatCSV, strings.NewReader(csvBody), true)
+	assert.NoError(t, err)
+
+	// Dry run is processed inline and must not touch the repositories
+	assert.Equal(t, service.ImportJobDone, job.Status)
+	assert.True(t, job.DryRun)
+	assert.Equal(t, 3, job.Report.Total)
+	assert.Equal(t, 1, job.Report.Succeeded)
+	assert.Equal(t, 2, job.Report.Failed)
+	assert.Equal(t, service.ImportRowResult{Row: 1, OK: true}, job.Report.Rows[0])
+	assert.Equal(t, "name is required", job.Report.Rows[1].Error)
+	assert.Equal(t, error_message.ErrWrongPriceFormat.Error(), job.Report.Rows[2].Error)
+
+	mockAdRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestImportService_WrongHeader(t *testing.T) {
+	svc := service.NewImportService(service.NewAdvertService(new(MockAdvertRepo), new(MockPhotoRepo), new(MockFavoriteRepo)), 100, 1000)
+
+	_, err := svc.Import(context.Background(), "", service.ImportFormatCSV, strings.NewReader("title,price\nA,1\n"), true)
+	assert.ErrorIs(t, err, error_message.ErrWrongImportHeader)
+
+	_, err = svc.Import(context.Background(), "", "xml", strings.NewReader(""), true)
+	assert.ErrorIs(t, err, error_message.ErrWrongImportFormat)
+}
+
+func TestImportService_BackgroundNDJSON(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	mockPhRepo := new(MockPhotoRepo)
+	// Threshold 0 forces every import into the background
+	svc := service.NewImportService(service.NewAdvertService(mockAdRepo, mockPhRepo, new(MockFavoriteRepo)), 0, 1000)
+
+	mockAdRepo.
+		On("Create", mock.Anything, mock.MatchedBy(func(ad model.Advert) bool {
+			return ad.Name == "Lampa" && ad.OwnerID == "seller-1" && ad.DefaultLocale == "ru" &&
+				len(ad.Translations) == 2 && assert.ObjectsAreEqual([]string{"http://lamp"}, ad.PhotoURLs)
+		})).
+		Return(7, nil).
+		Once()
+
+	body := `{"name":"Lampa","description":"Nastolnaya","price":25,"photos":["http://lamp"],"locale":"ru","translations":{"en":{"name":"Lamp","description":"Desk lamp"}}}
+{"name":"Broken","price":
+`
+	job, err := svc.Import(context.Background(), "seller-1", service.ImportFormatNDJSON, strings.NewReader(body), false)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, job.ID)
+
+	// Poll until the background job finishes
+	assert.Eventually(t, func() bool {
+		job, err = svc.GetJob(context.Background(), job.ID)
+		return err == nil && job.Status == service.ImportJobDone
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 2, job.Report.Processed)
+	assert.Equal(t, service.ImportRowResult{Row: 1, OK: true, ID: 7}, job.Report.Rows[0])
+	assert.Equal(t, error_message.ErrBadRequestBody.Error(), job.Report.Rows[1].Error)
+
+	mockAdRepo.AssertExpectations(t)
+	mockPhRepo.AssertExpectations(t)
+
+	_, err = svc.GetJob(context.Background(), "missing")
+	assert.ErrorIs(t, err, error_message.ErrImportJobNotFound)
+}
+
+func TestImportService_RejectsUnknownFields(t *testing.T) {
+	svc := service.NewImportService(service.NewAdvertService(new(MockAdvertRepo), new(MockPhotoRepo), new(MockFavoriteRepo)), 100, 1000)
+	ctx := context.Background()
+
+	// 1. The owner is the importing user, not a field of the row
+	body := `{"name":"Lamp","description":"Desk lamp","price":25,"photos":["http://lamp"],"owner_id":"someone-else"}
+{"name":"Lamp","description":"Desk lamp","price":25,"photos":["http://lamp"],"locale":"??"}
+{"name":"Lamp","description":"Desk lamp","price":25,"photos":["http://lamp"],"translations":{"ru":{"name":"Lampa"}}}
+`
+	job, err := svc.Import(ctx, "seller-1", service.ImportFormatNDJSON, strings.NewReader(body), true)
+	assert.NoError(t, err)
+	assert.Equal(t, error_message.ErrUnknownImportField.Error(), job.Report.Rows[0].Error)
+	assert.Equal(t, error_message.ErrWrongLocale.Error(), job.Report.Rows[1].Error, "locale is validated, not dropped")
+	assert.Equal(t, service.ImportRowResult{Row: 3, OK: true}, job.Report.Rows[2])
+
+	// 2. CSV takes an optional locale column and nothing else
+	csvBody := "name,description,price,photos,locale\nBike,Red bike,150,http://img1,??\n"
+	job, err = svc.Import(ctx, "seller-1", service.ImportFormatCSV, strings.NewReader(csvBody), true)
+	assert.NoError(t, err)
+	assert.Equal(t, error_message.ErrWrongLocale.Error(), job.Report.Rows[0].Error)
+
+	_, err = svc.Import(ctx, "seller-1", service.ImportFormatCSV, strings.NewReader("name,description,price,photos,owner_id\nBike,Red bike,150,http://img1,x\n"), true)
+	assert.ErrorIs(t, err, error_message.ErrWrongImportHeader)
+}
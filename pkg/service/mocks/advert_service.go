@@ -0,0 +1,55 @@
+// Package mocks provides testify mocks of the services for the tests of the
+// packages built on top of them.
+package mocks
+
+import (
+	"context"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAdvertService implements the AdvertService interface with testify/mock
+type MockAdvertService struct {
+	mock.Mock
+}
+
+var _ service.AdvertService = (*MockAdvertService)(nil)
+
+func (m *MockAdvertService) Create(ctx context.Context, input service.CreateAdvertInput) (int, error) {
+	args := m.Called(ctx, input)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAdvertService) GetByID(ctx context.Context, id int, fields service.AdvertFields, locales ...string) (service.AdvertDetail, error) {
+	args := m.Called(ctx, id, fields, locales)
+	return args.Get(0).(service.AdvertDetail), args.Error(1)
+}
+
+func (m *MockAdvertService) GetByIDs(ctx context.Context, ids []int, fields service.AdvertFields, locales ...string) (map[int]service.AdvertDetail, error) {
+	args := m.Called(ctx, ids, fields, locales)
+	details, _ := args.Get(0).(map[int]service.AdvertDetail)
+	return details, args.Error(1)
+}
+
+func (m *MockAdvertService) List(ctx context.Context, filter model.AdvertFilter, page int, sortField, sortOrder string, fields service.AdvertFields, locales ...string) ([]service.AdvertDetail, error) {
+	args := m.Called(ctx, filter, page, sortField, sortOrder, fields, locales)
+	return args.Get(0).([]service.AdvertDetail), args.Error(1)
+}
+
+func (m *MockAdvertService) Export(ctx context.Context, filter model.AdvertFilter, sortField, sortOrder string, fn func(row service.AdvertExportRow) error) error {
+	args := m.Called(ctx, filter, sortField, sortOrder, fn)
+	return args.Error(0)
+}
+
+func (m *MockAdvertService) Update(ctx context.Context, id int, input service.UpdateAdvertInput) error {
+	args := m.Called(ctx, id, input)
+	return args.Error(0)
+}
+
+func (m *MockAdvertService) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
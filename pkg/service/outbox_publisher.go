@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+// webhookPublisher turns outbox events into webhook deliveries.
+type webhookPublisher struct {
+	webhooks  WebhookService
+	photoRepo repository.PhotoRepo
+}
+
+// NewWebhookPublisher publishes advert events to webhook subscribers; the data of
+// the webhook body is the public projection of the event, see publicData.
+func NewWebhookPublisher(webhooks WebhookService, pr repository.PhotoRepo) Publisher {
+	return &webhookPublisher{webhooks: webhooks, photoRepo: pr}
+}
+
+func (p *webhookPublisher) Name() string {
+	return "webhooks"
+}
+
+func (p *webhookPublisher) Publish(ctx context.Context, e model.OutboxEvent) error {
+	data, _, err := publicData(ctx, p.photoRepo, e)
+	if err != nil {
+		return err
+	}
+	return p.webhooks.Emit(ctx, e.Event, data)
+}
+
+// logPublisher writes events as JSON lines.
+type logPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewLogPublisher writes every event as a JSON line to w, e.g. for debugging
+// or for a log shipper.
+func NewLogPublisher(w io.Writer) Publisher {
+	return &logPublisher{w: w}
+}
+
+func (p *logPublisher) Name() string {
+	return "log"
+}
+
+func (p *logPublisher) Publish(_ context.Context, e model.OutboxEvent) error {
+	line, err := json.Marshal(struct {
+		ID       int64           `json:"id"`
+		AdvertID int             `json:"advert_id"`
+		Event    string          `json:"event"`
+		Payload  json.RawMessage `json:"payload"`
+	}{e.ID, e.AggregateID, e.Event, json.RawMessage(e.Payload)})
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(append(line, '\n'))
+	return err
+}
+
+// changeFeedPublisher broadcasts advert summaries to the change stream of every replica.
+type changeFeedPublisher struct {
+	photoRepo repository.PhotoRepo
+	feed      repository.ChangeFeed
+}
+
+// NewChangeFeedPublisher turns outbox events into AdvertChange messages of the change feed.
+func NewChangeFeedPublisher(pr repository.PhotoRepo, feed repository.ChangeFeed) Publisher {
+	return &changeFeedPublisher{photoRepo: pr, feed: feed}
+}
+
+func (p *changeFeedPublisher) Name() string {
+	return "change_feed"
+}
+
+func (p *changeFeedPublisher) Publish(ctx context.Context, e model.OutboxEvent) error {
+	data, price, err := publicData(ctx, p.photoRepo, e)
+	if err != nil {
+		return err
+	}
+	msg, err := json.Marshal(AdvertChange{ID: e.ID, Event: e.Event, AdvertID: e.AggregateID, Price: price, Data: data})
+	if err != nil {
+		return err
+	}
+	return p.feed.Publish(ctx, string(msg))
+}
+
+// publicData is what an advert event shows outside the service: the AdvertSummary of
+// the advert, or only the ID of a deleted one, and the price if there is one. The
+// owner, the moderation flag and the translations of the outbox payload stay private.
+func publicData(ctx context.Context, pr repository.PhotoRepo, e model.OutboxEvent) (json.RawMessage, *float64, error) {
+	if e.Event == model.EventAdvertDeleted {
+		data, err := json.Marshal(map[string]int{"id": e.AggregateID})
+		return data, nil, err
+	}
+	var ad model.Advert
+	if err := json.Unmarshal([]byte(e.Payload), &ad); err != nil {
+		return nil, nil, err
+	}
+	// Like in the list, an advert whose photos are all broken has no main photo
+	mainURL, err := pr.GetMainPhotoURL(ctx, e.AggregateID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, err
+	}
+	thumbURL, err := pr.GetMainPhotoVariantURL(ctx, e.AggregateID, ThumbVariant)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := json.Marshal(AdvertSummary{
+		ID:                e.AggregateID,
+		Name:              ad.Name,
+		Locale:            ad.DefaultLocale,
+		MainPhotoURL:      mainURL,
+		MainPhotoThumbURL: thumbURL,
+		Price:             ad.Price,
+		CreatedAt:         ad.CreatedAt,
+	})
+	return data, &ad.Price, err
+}
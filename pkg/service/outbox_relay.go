@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// Publisher hands an outbox event to a downstream system.
+// Delivery is at-least-once: an event may be published again after a crash,
+// so publishers and their consumers should tolerate duplicates. A failure of
+// another publisher does not publish the event again.
+type Publisher interface {
+	// Name identifies the publisher in the delivery record of an event, it must be
+	// unique and stable across restarts
+	Name() string
+	Publish(ctx context.Context, e model.OutboxEvent) error
+}
+
+// OutboxMetrics describes the state of the outbox relay.
+type OutboxMetrics struct {
+	// Pending is the number of unpublished events
+	Pending int64 `json:"pending"`
+	// LagSeconds is the age of the oldest unpublished event
+	LagSeconds float64 `json:"lag_seconds"`
+	// Published and Failed count publish attempts since the start of the process
+	Published   uint64     `json:"published"`
+	Failed      uint64     `json:"failed"`
+	LastRelayAt *time.Time `json:"last_relay_at,omitempty"`
+}
+
+// OutboxRelay publishes outbox events to the configured publishers.
+type OutboxRelay interface {
+	// RelayBatch publishes one batch of due events and returns how many were processed.
+	RelayBatch(ctx context.Context) (int, error)
+
+	// Run calls RelayBatch periodically until ctx is cancelled and removes old published events.
+	Run(ctx context.Context)
+
+	// Metrics returns the outbox lag and the relay counters.
+	Metrics(ctx context.Context) (OutboxMetrics, error)
+}
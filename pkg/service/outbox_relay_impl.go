@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+// OutboxOptions configures the outbox relay.
+type OutboxOptions struct {
+	Interval  time.Duration
+	BatchSize int
+	// The n-th retry of a failed event waits BaseBackoff * 2^(n-1), at most MaxBackoff.
+	// Later events of the same advert wait for it.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Retention is how long published events are kept
+	Retention time.Duration
+}
+
+type outboxRelay struct {
+	outboxRepo repository.OutboxRepo
+	publishers []Publisher
+	opts       OutboxOptions
+
+	published atomic.Uint64
+	failed    atomic.Uint64
+	mu        sync.Mutex
+	lastRelay time.Time
+}
+
+func NewOutboxRelay(or repository.OutboxRepo, publishers []Publisher, opts OutboxOptions) OutboxRelay {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 100
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = 5 * time.Second
+	}
+	if opts.MaxBackoff < opts.BaseBackoff {
+		opts.MaxBackoff = opts.BaseBackoff
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = 7 * 24 * time.Hour
+	}
+	return &outboxRelay{outboxRepo: or, publishers: publishers, opts: opts}
+}
+
+func (r *outboxRelay) RelayBatch(ctx context.Context) (int, error) {
+	n, err := r.outboxRepo.Relay(ctx, time.Now(), r.opts.BatchSize, func(e model.OutboxEvent) model.OutboxEvent {
+		return r.publish(ctx, e)
+	})
+	if err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	r.lastRelay = time.Now()
+	r.mu.Unlock()
+	return n, nil
+}
+
+// publish hands the event to every publisher that has not taken it yet and returns
+// it updated with the outcome. A retry only goes to the publishers that failed.
+func (r *outboxRelay) publish(ctx context.Context, e model.OutboxEvent) model.OutboxEvent {
+	var delivered []string
+	if e.DeliveredTo != "" {
+		delivered = strings.Split(e.DeliveredTo, ",")
+	}
+	var errs []error
+	for _, p := range r.publishers {
+		if slices.Contains(delivered, p.Name()) {
+			continue
+		}
+		if err := p.Publish(ctx, e); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		delivered = append(delivered, p.Name())
+	}
+	e.DeliveredTo = strings.Join(delivered, ",")
+	if len(errs) == 0 {
+		now := time.Now()
+		e.PublishedAt = &now
+		e.LastError = ""
+		r.published.Add(1)
+		return e
+	}
+
+	e.Attempts++
+	e.LastError = errors.Join(errs...).Error()
+	e.NextAttemptAt = time.Now().Add(r.backoff(e.Attempts))
+	r.failed.Add(1)
+	log.Printf("outbox event %d (%s) failed, attempt %d: %s", e.ID, e.Event, e.Attempts, e.LastError)
+	return e
+}
+
+// backoff returns the delay before the retry following the given number of attempts.
+func (r *outboxRelay) backoff(attempts int) time.Duration {
+	delay := r.opts.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= r.opts.MaxBackoff {
+			return r.opts.MaxBackoff
+		}
+	}
+	return delay
+}
+
+func (r *outboxRelay) Run(ctx context.Context) {
+	lastCleanup := time.Time{}
+	runBatches(ctx, "outbox relay", r.opts.Interval, r.opts.BatchSize, r.RelayBatch, func(ctx context.Context) {
+		if time.Since(lastCleanup) < time.Hour {
+			return
+		}
+		if _, err := r.outboxRepo.DeletePublished(ctx, time.Now().Add(-r.opts.Retention)); err != nil {
+			log.Printf("outbox cleanup failed: %v", err)
+		}
+		lastCleanup = time.Now()
+	})
+}
+
+func (r *outboxRelay) Metrics(ctx context.Context) (OutboxMetrics, error) {
+	lag, err := r.outboxRepo.Lag(ctx)
+	if err != nil {
+		return OutboxMetrics{}, err
+	}
+	metrics := OutboxMetrics{
+		Pending:    lag.Pending,
+		LagSeconds: lag.OldestAge,
+		Published:  r.published.Load(),
+		Failed:     r.failed.Load(),
+	}
+	r.mu.Lock()
+	if !r.lastRelay.IsZero() {
+		last := r.lastRelay
+		metrics.LastRelayAt = &last
+	}
+	r.mu.Unlock()
+	return metrics, nil
+}
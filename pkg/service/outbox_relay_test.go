@@ -0,0 +1,170 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOutboxRepo implements a mock for repository.OutboxRepo.
+// Relay passes the events given to Return through fn and records the results in Saved.
+type MockOutboxRepo struct {
+	mock.Mock
+	Saved []model.OutboxEvent
+}
+
+func (m *MockOutboxRepo) Relay(ctx context.Context, now time.Time, limit int, fn func(e model.OutboxEvent) model.OutboxEvent) (int, error) {
+	args := m.Called(ctx, now, limit)
+	events := args.Get(0).([]model.OutboxEvent)
+	for _, e := range events {
+		m.Saved = append(m.Saved, fn(e))
+	}
+	return len(events), args.Error(1)
+}
+
+func (m *MockOutboxRepo) Lag(ctx context.Context) (model.OutboxLag, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(model.OutboxLag), args.Error(1)
+}
+
+func (m *MockOutboxRepo) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// recordingPublisher remembers published event IDs and fails while err is set
+type recordingPublisher struct {
+	name string
+	ids  []int64
+	err  error
+}
+
+func (p *recordingPublisher) Name() string {
+	return p.name
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, e model.OutboxEvent) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.ids = append(p.ids, e.ID)
+	return nil
+}
+
+func TestOutboxRelay_RelayBatch(t *testing.T) {
+	mockRepo := new(MockOutboxRepo)
+	first, second := &recordingPublisher{name: "first"}, &recordingPublisher{name: "second"}
+	relay := service.NewOutboxRelay(mockRepo, []service.Publisher{first, second}, service.OutboxOptions{
+		BatchSize:   10,
+		BaseBackoff: time.Minute,
+		MaxBackoff:  90 * time.Second,
+	})
+	ctx := context.Background()
+	events := []model.OutboxEvent{
+		{ID: 1, AggregateID: 9, Event: model.EventAdvertCreated, Payload: `{"id":9}`},
+		{ID: 2, AggregateID: 10, Event: model.EventAdvertDeleted, Payload: `{"id":10}`},
+	}
+
+	t.Run("Published", func(t *testing.T) {
+		mockRepo.On("Relay", mock.Anything, mock.Anything, 10).Return(events, nil).Once()
+
+		n, err := relay.RelayBatch(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []int64{1, 2}, first.ids)
+		assert.Equal(t, []int64{1, 2}, second.ids)
+		for _, e := range mockRepo.Saved {
+			assert.NotNil(t, e.PublishedAt)
+		}
+	})
+
+	t.Run("FailureBacksOff", func(t *testing.T) {
+		mockRepo.Saved = nil
+		second.err = errors.New("broker down")
+		failing := events[0]
+		failing.Attempts = 2
+		before := time.Now()
+		mockRepo.On("Relay", mock.Anything, mock.Anything, 10).Return([]model.OutboxEvent{failing}, nil).Once()
+
+		_, err := relay.RelayBatch(ctx)
+		assert.NoError(t, err)
+		saved := mockRepo.Saved[0]
+		assert.Nil(t, saved.PublishedAt)
+		assert.Equal(t, 3, saved.Attempts)
+		assert.Contains(t, saved.LastError, "second: broker down")
+		assert.Equal(t, "first", saved.DeliveredTo)
+		// 1m * 2^2 is capped at 90s
+		assert.WithinDuration(t, before.Add(90*time.Second), saved.NextAttemptAt, time.Second)
+	})
+
+	t.Run("RetryOnlyFailed", func(t *testing.T) {
+		mockRepo.Saved = nil
+		first.ids, second.ids = nil, nil
+		second.err = nil
+		retried := events[0]
+		retried.Attempts = 3
+		retried.DeliveredTo = "first"
+		mockRepo.On("Relay", mock.Anything, mock.Anything, 10).Return([]model.OutboxEvent{retried}, nil).Once()
+
+		_, err := relay.RelayBatch(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, first.ids)
+		assert.Equal(t, []int64{1}, second.ids)
+		saved := mockRepo.Saved[0]
+		assert.NotNil(t, saved.PublishedAt)
+		assert.Equal(t, "first,second", saved.DeliveredTo)
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		mockRepo.On("Lag", mock.Anything).Return(model.OutboxLag{Pending: 1, OldestAge: 12.5}, nil).Once()
+
+		metrics, err := relay.Metrics(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), metrics.Pending)
+		assert.Equal(t, 12.5, metrics.LagSeconds)
+		assert.Equal(t, uint64(3), metrics.Published)
+		assert.Equal(t, uint64(1), metrics.Failed)
+		assert.NotNil(t, metrics.LastRelayAt)
+	})
+	mockRepo.AssertExpectations(t)
+}
+
+func TestOutboxPublishers(t *testing.T) {
+	event := model.OutboxEvent{ID: 5, AggregateID: 9, Event: model.EventAdvertUpdated, Payload: `{"id":9,"name":"Bike"}`}
+
+	t.Run("Webhook", func(t *testing.T) {
+		mockRepo := new(MockWebhookRepo)
+		mockPhRepo := new(MockPhotoRepo)
+		publisher := service.NewWebhookPublisher(service.NewWebhookService(mockRepo, service.WebhookOptions{}), mockPhRepo)
+		mockPhRepo.On("GetMainPhotoURL", mock.Anything, 9).Return("http://img/9", nil).Once()
+		mockPhRepo.On("GetMainPhotoVariantURL", mock.Anything, 9, service.ThumbVariant).Return("", nil).Once()
+		private := model.OutboxEvent{ID: 6, AggregateID: 9, Event: model.EventAdvertUpdated,
+			Payload: `{"id":9,"owner_id":"seller","name":"Bike","price":120,"flag_reason":"spam","default_locale":"en",` +
+				`"translations":[{"locale":"ru","name":"Велосипед"}]}`}
+		mockRepo.On("EnqueueEvent", mock.Anything, model.EventAdvertUpdated, mock.MatchedBy(func(payload string) bool {
+			// Only the public summary leaves the service
+			return strings.Contains(payload, `"data":{"id":9,"name":"Bike","locale":"en","main_photo_url":"http://img/9","price":120}`)
+		})).Return(1, nil).Once()
+
+		assert.NoError(t, publisher.Publish(context.Background(), private))
+		mockRepo.AssertExpectations(t)
+		mockPhRepo.AssertExpectations(t)
+	})
+
+	t.Run("Log", func(t *testing.T) {
+		var buf bytes.Buffer
+		publisher := service.NewLogPublisher(&buf)
+
+		assert.NoError(t, publisher.Publish(context.Background(), event))
+		assert.Equal(t, `{"id":5,"advert_id":9,"event":"advert.updated","payload":{"id":9,"name":"Bike"}}`+"\n", buf.String())
+	})
+}
@@ -0,0 +1,18 @@
+package service
+
+import "context"
+
+// PhotoCheckService describes background verification of stored photo URLs.
+type PhotoCheckService interface {
+	// Check verifies a single URL: allowed scheme, reachable with HEAD (or GET
+	// as a fallback) within the timeout and an image content type.
+	// It returns model.PhotoStatusOK or model.PhotoStatusBroken with a reason.
+	Check(ctx context.Context, url string) (status, reason string)
+
+	// CheckDue verifies one batch of photos that were never checked
+	// or are due for a recheck, and returns how many were processed.
+	CheckDue(ctx context.Context) (int, error)
+
+	// Run calls CheckDue periodically until ctx is cancelled.
+	Run(ctx context.Context)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+// PhotoCheckOptions configures the photo URL verifier.
+type PhotoCheckOptions struct {
+	// Interval between two batches
+	Interval time.Duration
+	// RecheckAfter is how long a check result stays valid
+	RecheckAfter time.Duration
+	// Timeout of a single HTTP check
+	Timeout        time.Duration
+	BatchSize      int
+	AllowedSchemes []string
+	// AllowPrivateNetworks lets checks reach loopback and private addresses
+	AllowPrivateNetworks bool
+}
+
+type photoCheckService struct {
+	photoRepo   repository.PhotoRepo
+	invalidator AdvertInvalidator
+	client      *http.Client
+	opts        PhotoCheckOptions
+	schemes     map[string]bool
+}
+
+// NewPhotoCheckService creates a PhotoCheckService; inv is told about the adverts
+// of the photos whose status changed, as their main photo may change.
+func NewPhotoCheckService(pr repository.PhotoRepo, inv AdvertInvalidator, opts PhotoCheckOptions) PhotoCheckService {
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 50
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	schemes := schemeSet(opts.AllowedSchemes)
+	return &photoCheckService{
+		photoRepo:   pr,
+		invalidator: inv,
+		client:      newPhotoClient(opts.Timeout, schemes, opts.AllowPrivateNetworks),
+		opts:        opts,
+		schemes:     schemes,
+	}
+}
+
+func (s *photoCheckService) Check(ctx context.Context, rawURL string) (string, string) {
+	// Uploaded photos are served by this service and need no network check
+	if strings.HasPrefix(rawURL, MediaPathPrefix) {
+		return model.PhotoStatusOK, ""
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return model.PhotoStatusBroken, "invalid url"
+	}
+	if !s.schemes[strings.ToLower(u.Scheme)] {
+		return model.PhotoStatusBroken, fmt.Sprintf("scheme %q is not allowed", u.Scheme)
+	}
+
+	resp, err := s.fetch(ctx, http.MethodHead, u.String())
+	// Some servers do not implement HEAD, retry with GET
+	if err != nil || resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+		resp, err = s.fetch(ctx, http.MethodGet, u.String())
+	}
+	if err != nil {
+		return model.PhotoStatusBroken, err.Error()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return model.PhotoStatusBroken, "unexpected status " + resp.Status
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "image/") {
+		return model.PhotoStatusBroken, fmt.Sprintf("content type %q is not an image", mediaType)
+	}
+	return model.PhotoStatusOK, ""
+}
+
+// fetch performs a request and discards the body, only status and headers are needed.
+func (s *photoCheckService) fetch(ctx context.Context, method, u string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	return resp, nil
+}
+
+func (s *photoCheckService) CheckDue(ctx context.Context) (int, error) {
+	photos, err := s.photoRepo.ListDueForCheck(ctx, time.Now().Add(-s.opts.RecheckAfter), s.opts.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("service.CheckDue: photoRepo.ListDueForCheck: %w", err)
+	}
+	for _, photo := range photos {
+		status, reason := s.Check(ctx, photo.URL)
+		if err := s.photoRepo.SetCheckResult(ctx, photo.ID, status, reason, time.Now()); err != nil {
+			return 0, err
+		}
+		if status != photo.Status {
+			s.invalidator.Invalidate(ctx, photo.AdvertID)
+		}
+	}
+	return len(photos), nil
+}
+
+func (s *photoCheckService) Run(ctx context.Context) {
+	runBatches(ctx, "photo check", s.opts.Interval, s.opts.BatchSize, s.CheckDue, nil)
+}
@@ -0,0 +1,124 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newImageServer serves a few URLs covering the verifier's cases
+func newImageServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+	})
+	mux.HandleFunc("/get-only.jpg", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+	})
+	mux.HandleFunc("/page.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	})
+	mux.HandleFunc("/slow.png", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "image/png")
+	})
+	return httptest.NewServer(mux)
+}
+
+func newPhotoCheckService(pr *MockPhotoRepo) service.PhotoCheckService {
+	return service.NewPhotoCheckService(pr, service.NoInvalidation, service.PhotoCheckOptions{
+		Interval:       time.Minute,
+		RecheckAfter:   time.Hour,
+		Timeout:        100 * time.Millisecond,
+		BatchSize:      10,
+		AllowedSchemes: []string{"http", "https"},
+		// The test server listens on loopback
+		AllowPrivateNetworks: true,
+	})
+}
+
+func TestPhotoCheckService_Check(t *testing.T) {
+	srv := newImageServer()
+	defer srv.Close()
+	svc := newPhotoCheckService(new(MockPhotoRepo))
+	ctx := context.Background()
+
+	cases := []struct {
+		name   string
+		url    string
+		status string
+	}{
+		{"Image", srv.URL + "/ok.png", model.PhotoStatusOK},
+		{"HeadNotAllowed", srv.URL + "/get-only.jpg", model.PhotoStatusOK},
+		{"Uploaded", "/media/adverts/1/a.png", model.PhotoStatusOK},
+		{"NotAnImage", srv.URL + "/page.html", model.PhotoStatusBroken},
+		{"NotFound", srv.URL + "/missing.png", model.PhotoStatusBroken},
+		{"Timeout", srv.URL + "/slow.png", model.PhotoStatusBroken},
+		{"DisallowedScheme", "ftp://example.com/a.png", model.PhotoStatusBroken},
+		{"NotAURL", "just text", model.PhotoStatusBroken},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, reason := svc.Check(ctx, tc.url)
+			assert.Equal(t, tc.status, status, reason)
+			if status == model.PhotoStatusBroken {
+				assert.NotEmpty(t, reason)
+			}
+		})
+	}
+}
+
+func TestPhotoCheckService_CheckPrivateAddress(t *testing.T) {
+	srv := newImageServer()
+	defer srv.Close()
+	svc := service.NewPhotoCheckService(new(MockPhotoRepo), service.NoInvalidation, service.PhotoCheckOptions{
+		Timeout:        100 * time.Millisecond,
+		AllowedSchemes: []string{"http", "https"},
+	})
+	ctx := context.Background()
+
+	for _, u := range []string{
+		srv.URL + "/ok.png",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/a.png",
+		"http://[::1]/a.png",
+	} {
+		status, reason := svc.Check(ctx, u)
+		assert.Equal(t, model.PhotoStatusBroken, status, u)
+		assert.Contains(t, reason, "is not public", u)
+	}
+}
+
+func TestPhotoCheckService_CheckDue(t *testing.T) {
+	srv := newImageServer()
+	defer srv.Close()
+	mockPhRepo := new(MockPhotoRepo)
+	svc := newPhotoCheckService(mockPhRepo)
+
+	mockPhRepo.
+		On("ListDueForCheck", mock.Anything, mock.AnythingOfType("time.Time"), 10).
+		Return([]model.Photo{
+			{ID: 1, URL: srv.URL + "/ok.png"},
+			{ID: 2, URL: srv.URL + "/page.html"},
+		}, nil).
+		Once()
+	mockPhRepo.On("SetCheckResult", mock.Anything, 1, model.PhotoStatusOK, "", mock.Anything).Return(nil).Once()
+	mockPhRepo.On("SetCheckResult", mock.Anything, 2, model.PhotoStatusBroken, mock.Anything, mock.Anything).Return(nil).Once()
+
+	n, err := svc.CheckDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	mockPhRepo.AssertExpectations(t)
+}
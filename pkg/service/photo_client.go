@@ -0,0 +1,73 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxPhotoRedirects limits the redirects followed when fetching a photo URL.
+const maxPhotoRedirects = 5
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), not routable on the internet.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// schemeSet lowercases the allowed URL schemes for lookups.
+func schemeSet(allowed []string) map[string]bool {
+	schemes := make(map[string]bool, len(allowed))
+	for _, scheme := range allowed {
+		schemes[strings.ToLower(scheme)] = true
+	}
+	return schemes
+}
+
+// newPhotoClient returns the HTTP client for URLs supplied by users (photos, webhooks). Redirects
+// may only lead to the allowed schemes, and unless allowPrivate is set the client
+// refuses to connect to non-public addresses. The check runs on the resolved IP, so
+// neither a redirect nor a DNS name pointing inside the network gets through.
+func newPhotoClient(timeout time.Duration, schemes map[string]bool, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = denyNonPublic
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be the only address checked, and it is usually a private one
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxPhotoRedirects {
+				return http.ErrUseLastResponse
+			}
+			if !schemes[strings.ToLower(req.URL.Scheme)] {
+				return fmt.Errorf("redirect to disallowed scheme %q", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// denyNonPublic is a net.Dialer Control that rejects loopback, private, link-local
+// (e.g. the 169.254.169.254 metadata endpoint) and other non-public addresses.
+func denyNonPublic(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("address %s is not public", ip)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// DuplicateFlagReason is stored on adverts flagged by the create-time rule.
+const DuplicateFlagReason = "photo is used by an advert of another owner"
+
+// DuplicateGroup is a set of visually identical photos used by different owners.
+type DuplicateGroup struct {
+	// Hash is the perceptual hash in hex
+	Hash   string                 `json:"hash"`
+	Photos []model.DuplicatePhoto `json:"photos"`
+}
+
+// PhotoHashService computes perceptual hashes of photos and finds
+// visually identical photos reused across adverts.
+type PhotoHashService interface {
+	// Hash downloads (or reads from the blob storage) a photo and returns its difference hash.
+	Hash(ctx context.Context, url string) (uint64, error)
+
+	// HashDue hashes one batch of verified photos without a hash
+	// and returns how many were processed.
+	HashDue(ctx context.Context) (int, error)
+
+	// Run calls HashDue periodically until ctx is cancelled.
+	Run(ctx context.Context)
+
+	// FindDuplicates returns up to limit groups of photos shared by different owners.
+	FindDuplicates(ctx context.Context, limit int) ([]DuplicateGroup, error)
+
+	// FlagDuplicates hashes the photos of a freshly created advert and flags the advert
+	// when any of them is already used by another owner. It reports whether the advert was flagged.
+	FlagDuplicates(ctx context.Context, advertID int, ownerID string) (bool, error)
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/imaging"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/storage"
+)
+
+// PhotoHashOptions configures perceptual hashing of photos.
+type PhotoHashOptions struct {
+	// Interval between two batches
+	Interval time.Duration
+	// Timeout of a single download
+	Timeout   time.Duration
+	BatchSize int
+	// MaxSize limits the downloaded image in bytes
+	MaxSize        int64
+	AllowedSchemes []string
+	// AllowPrivateNetworks lets downloads reach loopback and private addresses
+	AllowPrivateNetworks bool
+	// BaseURL is the prefix of uploaded photo URLs (see NewUploadService),
+	// such photos are read from the blob storage instead of the network
+	BaseURL string
+}
+
+type photoHashService struct {
+	advertRepo repository.AdvertRepo
+	photoRepo  repository.PhotoRepo
+	store      storage.BlobStore
+	client     *http.Client
+	opts       PhotoHashOptions
+	schemes    map[string]bool
+}
+
+func NewPhotoHashService(ar repository.AdvertRepo, pr repository.PhotoRepo, store storage.BlobStore, opts PhotoHashOptions) PhotoHashService {
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 50
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = 10 << 20
+	}
+	opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+	schemes := schemeSet(opts.AllowedSchemes)
+	return &photoHashService{
+		advertRepo: ar,
+		photoRepo:  pr,
+		store:      store,
+		client:     newPhotoClient(opts.Timeout, schemes, opts.AllowPrivateNetworks),
+		opts:       opts,
+		schemes:    schemes,
+	}
+}
+
+func (s *photoHashService) Hash(ctx context.Context, rawURL string) (uint64, error) {
+	data, err := s.load(ctx, rawURL)
+	if err != nil {
+		return 0, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	if cfg.Width*cfg.Height > maxVariantSourcePixels {
+		return 0, fmt.Errorf("image is too large: %dx%d", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	return imaging.DHash(img), nil
+}
+
+// load reads an uploaded photo from the blob storage or downloads an external one.
+func (s *photoHashService) load(ctx context.Context, rawURL string) ([]byte, error) {
+	if key, ok := strings.CutPrefix(rawURL, s.opts.BaseURL+MediaPathPrefix); ok {
+		rc, _, err := s.store.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return readLimited(rc, s.opts.MaxSize)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid url %q", rawURL)
+	}
+	if !s.schemes[strings.ToLower(u.Scheme)] {
+		return nil, fmt.Errorf("scheme %q is not allowed", u.Scheme)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return readLimited(resp.Body, s.opts.MaxSize)
+}
+
+// readLimited reads at most max bytes and fails on anything larger.
+func readLimited(r io.Reader, max int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > max {
+		return nil, fmt.Errorf("image is larger than %d bytes", max)
+	}
+	return data, nil
+}
+
+// hashAndStore computes and saves the hash of a photo. Photos that cannot be
+// hashed get 0 so that they are not retried on every batch.
+func (s *photoHashService) hashAndStore(ctx context.Context, photo model.Photo) (int64, error) {
+	hash, err := s.Hash(ctx, photo.URL)
+	if err != nil {
+		log.Printf("failed to hash photo %d: %v", photo.ID, err)
+	}
+	stored := int64(hash)
+	if err := s.photoRepo.SetHash(ctx, photo.ID, stored); err != nil {
+		return 0, err
+	}
+	return stored, nil
+}
+
+func (s *photoHashService) HashDue(ctx context.Context) (int, error) {
+	photos, err := s.photoRepo.ListUnhashed(ctx, s.opts.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("service.HashDue: photoRepo.ListUnhashed: %w", err)
+	}
+	for _, photo := range photos {
+		if _, err := s.hashAndStore(ctx, photo); err != nil {
+			return 0, err
+		}
+	}
+	return len(photos), nil
+}
+
+func (s *photoHashService) Run(ctx context.Context) {
+	runBatches(ctx, "photo hashing", s.opts.Interval, s.opts.BatchSize, s.HashDue, nil)
+}
+
+func (s *photoHashService) FindDuplicates(ctx context.Context, limit int) ([]DuplicateGroup, error) {
+	photos, err := s.photoRepo.ListHashDuplicates(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("service.FindDuplicates: photoRepo.ListHashDuplicates: %w", err)
+	}
+
+	// Photos come ordered by hash, so each group is a contiguous run
+	groups := []DuplicateGroup{}
+	for _, p := range photos {
+		hash := fmt.Sprintf("%016x", uint64(p.PHash))
+		if len(groups) == 0 || groups[len(groups)-1].Hash != hash {
+			groups = append(groups, DuplicateGroup{Hash: hash})
+		}
+		last := &groups[len(groups)-1]
+		last.Photos = append(last.Photos, p)
+	}
+	return groups, nil
+}
+
+func (s *photoHashService) FlagDuplicates(ctx context.Context, advertID int, ownerID string) (bool, error) {
+	photos, err := s.photoRepo.ListByAdvertID(ctx, advertID)
+	if err != nil {
+		return false, fmt.Errorf("service.FlagDuplicates: photoRepo.ListByAdvertID (id=%d): %w", advertID, err)
+	}
+	for _, photo := range photos {
+		hash, err := s.hashAndStore(ctx, photo)
+		if err != nil {
+			return false, err
+		}
+		if hash == 0 {
+			continue
+		}
+		used, err := s.photoRepo.HashUsedByOtherOwner(ctx, hash, ownerID, advertID)
+		if err != nil {
+			return false, err
+		}
+		if used {
+			reason := DuplicateFlagReason + " (photo " + strconv.Itoa(photo.ID) + ")"
+			if err := s.advertRepo.SetFlag(ctx, advertID, reason); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// duplicateFlaggingService flags new adverts whose photos are already used by other owners.
+type duplicateFlaggingService struct {
+	AdvertService
+	hashes PhotoHashService
+}
+
+// NewDuplicateFlaggingService wraps next so that every created advert is checked with
+// hashes.FlagDuplicates. The check never fails the creation, errors are only logged.
+func NewDuplicateFlaggingService(next AdvertService, hashes PhotoHashService) AdvertService {
+	return &duplicateFlaggingService{AdvertService: next, hashes: hashes}
+}
+
+func (s *duplicateFlaggingService) Create(ctx context.Context, input CreateAdvertInput) (int, error) {
+	id, err := s.AdvertService.Create(ctx, input)
+	if err != nil {
+		return id, err
+	}
+	if _, err := s.hashes.FlagDuplicates(ctx, id, input.OwnerID); err != nil {
+		log.Printf("failed to check photos of advert %d for duplicates: %v", id, err)
+	}
+	return id, nil
+}
@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/imaging"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// diagonalPNG encodes a small image with a diagonal gradient
+func diagonalPNG(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			v := uint8((x*3 + y*2) % 256)
+			img.Set(x, y, color.RGBA{R: v, G: 255 - v, B: v / 2, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func newPhotoHashFixture(t *testing.T) (*MockAdvertRepo, *MockPhotoRepo, storage.BlobStore, service.PhotoHashService) {
+	mockAdRepo := new(MockAdvertRepo)
+	mockPhRepo := new(MockPhotoRepo)
+	store, err := storage.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	svc := service.NewPhotoHashService(mockAdRepo, mockPhRepo, store, service.PhotoHashOptions{
+		Interval:             time.Minute,
+		Timeout:              time.Second,
+		BatchSize:            10,
+		MaxSize:              1 << 20,
+		AllowedSchemes:       []string{"http"},
+		AllowPrivateNetworks: true,
+		BaseURL:              "http://cdn.local/",
+	})
+	return mockAdRepo, mockPhRepo, store, svc
+}
+
+func newHashImageServer(t *testing.T, data []byte) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/photo.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(data)
+	})
+	mux.HandleFunc("/page.html", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html></html>"))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPhotoHashService_Hash(t *testing.T) {
+	data := diagonalPNG(t)
+	srv := newHashImageServer(t, data)
+	_, _, store, svc := newPhotoHashFixture(t)
+	ctx := context.Background()
+
+	decoded, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	want := imaging.DHash(decoded)
+
+	t.Run("Downloaded", func(t *testing.T) {
+		got, err := svc.Hash(ctx, srv.URL+"/photo.png")
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("Uploaded", func(t *testing.T) {
+		assert.NoError(t, store.Put(ctx, "adverts/1/a.png", bytes.NewReader(data), int64(len(data)), "image/png"))
+		got, err := svc.Hash(ctx, "http://cdn.local/media/adverts/1/a.png")
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("NotAnImage", func(t *testing.T) {
+		_, err := svc.Hash(ctx, srv.URL+"/page.html")
+		assert.Error(t, err)
+	})
+
+	t.Run("DisallowedScheme", func(t *testing.T) {
+		_, err := svc.Hash(ctx, "ftp://example.com/photo.png")
+		assert.Error(t, err)
+	})
+}
+
+func TestPhotoHashService_HashDue(t *testing.T) {
+	data := diagonalPNG(t)
+	srv := newHashImageServer(t, data)
+	_, mockPhRepo, _, svc := newPhotoHashFixture(t)
+	ctx := context.Background()
+
+	decoded, _ := png.Decode(bytes.NewReader(data))
+	hash := int64(imaging.DHash(decoded))
+
+	mockPhRepo.On("ListUnhashed", mock.Anything, 10).Return([]model.Photo{
+		{ID: 1, URL: srv.URL + "/photo.png"},
+		{ID: 2, URL: srv.URL + "/page.html"},
+	}, nil).Once()
+	mockPhRepo.On("SetHash", mock.Anything, 1, hash).Return(nil).Once()
+	// Photos that cannot be hashed are marked with 0 and not retried
+	mockPhRepo.On("SetHash", mock.Anything, 2, int64(0)).Return(nil).Once()
+
+	n, err := svc.HashDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	mockPhRepo.AssertExpectations(t)
+}
+
+func TestPhotoHashService_FindDuplicates(t *testing.T) {
+	_, mockPhRepo, _, svc := newPhotoHashFixture(t)
+
+	mockPhRepo.On("ListHashDuplicates", mock.Anything, 20).Return([]model.DuplicatePhoto{
+		{PHash: 10, PhotoID: 1, AdvertID: 1, OwnerID: "alice"},
+		{PHash: 10, PhotoID: 2, AdvertID: 2, OwnerID: "bob"},
+		{PHash: -1, PhotoID: 3, AdvertID: 3, OwnerID: "carol"},
+		{PHash: -1, PhotoID: 4, AdvertID: 4},
+	}, nil).Once()
+
+	groups, err := svc.FindDuplicates(context.Background(), 20)
+	assert.NoError(t, err)
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, "000000000000000a", groups[0].Hash)
+		assert.Len(t, groups[0].Photos, 2)
+		assert.Equal(t, "ffffffffffffffff", groups[1].Hash)
+		assert.Equal(t, 4, groups[1].Photos[1].PhotoID)
+	}
+}
+
+func TestDuplicateFlaggingService_Create(t *testing.T) {
+	data := diagonalPNG(t)
+	srv := newHashImageServer(t, data)
+	mockAdRepo, mockPhRepo, _, hashSvc := newPhotoHashFixture(t)
+	svc := service.NewDuplicateFlaggingService(service.NewAdvertService(mockAdRepo, mockPhRepo, new(MockFavoriteRepo)), hashSvc)
+	ctx := context.Background()
+
+	decoded, _ := png.Decode(bytes.NewReader(data))
+	hash := int64(imaging.DHash(decoded))
+	url := srv.URL + "/photo.png"
+
+	mockAdRepo.On("Create", mock.Anything, mock.MatchedBy(func(ad model.Advert) bool {
+		return ad.OwnerID == "mallory"
+	})).Return(7, nil).Once()
+	mockPhRepo.On("ListByAdvertID", mock.Anything, 7).Return([]model.Photo{{ID: 70, AdvertID: 7, URL: url}}, nil).Once()
+	mockPhRepo.On("SetHash", mock.Anything, 70, hash).Return(nil).Once()
+	mockPhRepo.On("HashUsedByOtherOwner", mock.Anything, hash, "mallory", 7).Return(true, nil).Once()
+	mockAdRepo.On("SetFlag", mock.Anything, 7, mock.MatchedBy(func(reason string) bool {
+		return reason != ""
+	})).Return(nil).Once()
+
+	id, err := svc.Create(ctx, service.CreateAdvertInput{
+		OwnerID:     "mallory",
+		Name:        "Stolen bike",
+		Description: "Red bike",
+		Photos:      []string{url},
+		Price:       100,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+	mockAdRepo.AssertExpectations(t)
+	mockPhRepo.AssertExpectations(t)
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// PhotoService describes the business logic for managing
+// individual photos of an advert without replacing the whole set.
+type PhotoService interface {
+	// List returns all photos of the advert ordered by position (1 = main photo).
+	List(ctx context.Context, advertID int) ([]model.Photo, error)
+
+	// ListByAdverts returns the photos of several adverts in one query, keyed by advert ID.
+	// Unknown adverts and adverts without photos are missing from the map.
+	ListByAdverts(ctx context.Context, advertIDs []int) (map[int][]model.Photo, error)
+
+	// Add inserts a photo at position, shifting the following photos.
+	// position == 0 (or beyond the end) appends the photo.
+	Add(ctx context.Context, advertID int, url string, position int) (model.Photo, error)
+
+	// Remove deletes a photo by ID and closes the gap in positions.
+	Remove(ctx context.Context, advertID, photoID int) error
+
+	// Reorder sets a new order of photos; photoIDs must list
+	// every photo of the advert exactly once.
+	Reorder(ctx context.Context, advertID int, photoIDs []int) error
+
+	// SetMain moves the photo to position 1 keeping the order of the rest.
+	SetMain(ctx context.Context, advertID, photoID int) error
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+type photoService struct {
+	advertRepo repository.AdvertRepo
+	photoRepo  repository.PhotoRepo
+}
+
+func NewPhotoService(ar repository.AdvertRepo, pr repository.PhotoRepo) PhotoService {
+	return &photoService{
+		advertRepo: ar,
+		photoRepo:  pr,
+	}
+}
+
+func (s *photoService) List(ctx context.Context, advertID int) ([]model.Photo, error) {
+	if err := s.ensureAdvert(ctx, advertID); err != nil {
+		return nil, err
+	}
+	return s.photoRepo.ListByAdvertID(ctx, advertID)
+}
+
+func (s *photoService) ListByAdverts(ctx context.Context, advertIDs []int) (map[int][]model.Photo, error) {
+	if len(advertIDs) == 0 {
+		return map[int][]model.Photo{}, nil
+	}
+	return s.photoRepo.ListByAdvertIDs(ctx, advertIDs)
+}
+
+func (s *photoService) Add(ctx context.Context, advertID int, url string, position int) (model.Photo, error) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return model.Photo{}, error_message.ErrWrongPhotoURL
+	}
+	if position < 0 {
+		return model.Photo{}, error_message.ErrWrongPhotoPosition
+	}
+	if err := s.ensureAdvert(ctx, advertID); err != nil {
+		return model.Photo{}, err
+	}
+	photo, err := s.photoRepo.Insert(ctx, model.Photo{
+		AdvertID: advertID,
+		URL:      url,
+		Position: position,
+	}, maxPhotos)
+	if errors.Is(err, repository.ErrPhotoCount) {
+		return model.Photo{}, error_message.ErrWrongPhotos
+	}
+	return photo, err
+}
+
+func (s *photoService) Remove(ctx context.Context, advertID, photoID int) error {
+	if err := s.ensureAdvert(ctx, advertID); err != nil {
+		return err
+	}
+	if err := s.photoRepo.Delete(ctx, advertID, photoID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return error_message.ErrPhotoNotFound
+		}
+		if errors.Is(err, repository.ErrPhotoCount) {
+			return error_message.ErrWrongPhotos
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *photoService) Reorder(ctx context.Context, advertID int, photoIDs []int) error {
+	photos, err := s.List(ctx, advertID)
+	if err != nil {
+		return err
+	}
+	if len(photoIDs) != len(photos) {
+		return error_message.ErrWrongPhotoOrder
+	}
+	known := make(map[int]bool, len(photos))
+	for _, p := range photos {
+		known[p.ID] = true
+	}
+	for _, id := range photoIDs {
+		if !known[id] {
+			return error_message.ErrWrongPhotoOrder
+		}
+		// Each photo may appear only once
+		delete(known, id)
+	}
+	return s.photoRepo.Reorder(ctx, advertID, photoIDs)
+}
+
+func (s *photoService) SetMain(ctx context.Context, advertID, photoID int) error {
+	photos, err := s.List(ctx, advertID)
+	if err != nil {
+		return err
+	}
+	order := make([]int, 0, len(photos))
+	order = append(order, photoID)
+	for _, p := range photos {
+		if p.ID != photoID {
+			order = append(order, p.ID)
+		}
+	}
+	if len(order) != len(photos) {
+		return error_message.ErrPhotoNotFound
+	}
+	if photos[0].ID == photoID {
+		// Already the main photo
+		return nil
+	}
+	return s.photoRepo.Reorder(ctx, advertID, order)
+}
+
+// ensureAdvert returns ErrAdvertNotFound if the advert does not exist.
+func (s *photoService) ensureAdvert(ctx context.Context, advertID int) error {
+	if _, err := s.advertRepo.GetByID(ctx, advertID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return error_message.ErrAdvertNotFound
+		}
+		return fmt.Errorf("service.photo: advertRepo.GetByID (id=%d): %w", advertID, err)
+	}
+	return nil
+}
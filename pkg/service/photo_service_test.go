@@ -0,0 +1,115 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func samplePhotoModels(advertID int) []model.Photo {
+	return []model.Photo{
+		{ID: 11, AdvertID: advertID, URL: "http://img1", Position: 1},
+		{ID: 12, AdvertID: advertID, URL: "http://img2", Position: 2},
+		{ID: 13, AdvertID: advertID, URL: "http://img3", Position: 3},
+	}
+}
+
+func TestPhotoService_Add(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	mockPhRepo := new(MockPhotoRepo)
+	svc := service.NewPhotoService(mockAdRepo, mockPhRepo)
+	ctx := context.Background()
+
+	mockAdRepo.On("GetByID", mock.Anything, 1).Return(*sampleAdvertModel(1), nil)
+	mockAdRepo.On("GetByID", mock.Anything, 2).Return(model.Advert{}, sql.ErrNoRows)
+
+	t.Run("Success", func(t *testing.T) {
+		stored := model.Photo{ID: 20, AdvertID: 1, URL: "http://new", Position: 2}
+		mockPhRepo.
+			On("Insert", mock.Anything, model.Photo{AdvertID: 1, URL: "http://new", Position: 2}, 3).
+			Return(stored, nil).
+			Once()
+
+		photo, err := svc.Add(ctx, 1, " http://new ", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, stored, photo)
+		mockPhRepo.AssertExpectations(t)
+	})
+
+	t.Run("TooManyPhotos", func(t *testing.T) {
+		mockPhRepo.
+			On("Insert", mock.Anything, model.Photo{AdvertID: 1, URL: "http://fourth"}, 3).
+			Return(model.Photo{}, fmt.Errorf("wrapped: %w", repository.ErrPhotoCount)).
+			Once()
+
+		_, err := svc.Add(ctx, 1, "http://fourth", 0)
+		assert.ErrorIs(t, err, error_message.ErrWrongPhotos)
+	})
+
+	t.Run("EmptyURL", func(t *testing.T) {
+		_, err := svc.Add(ctx, 1, "  ", 0)
+		assert.ErrorIs(t, err, error_message.ErrWrongPhotoURL)
+	})
+
+	t.Run("AdvertNotFound", func(t *testing.T) {
+		_, err := svc.Add(ctx, 2, "http://new", 0)
+		assert.ErrorIs(t, err, error_message.ErrAdvertNotFound)
+	})
+}
+
+func TestPhotoService_Remove(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	mockPhRepo := new(MockPhotoRepo)
+	svc := service.NewPhotoService(mockAdRepo, mockPhRepo)
+	ctx := context.Background()
+
+	mockAdRepo.On("GetByID", mock.Anything, 1).Return(*sampleAdvertModel(1), nil)
+	mockPhRepo.On("Delete", mock.Anything, 1, 11).Return(nil).Once()
+	mockPhRepo.On("Delete", mock.Anything, 1, 99).Return(fmt.Errorf("wrapped: %w", sql.ErrNoRows)).Once()
+	mockPhRepo.On("Delete", mock.Anything, 1, 12).Return(fmt.Errorf("wrapped: %w", repository.ErrPhotoCount)).Once()
+
+	assert.NoError(t, svc.Remove(ctx, 1, 11))
+	assert.ErrorIs(t, svc.Remove(ctx, 1, 99), error_message.ErrPhotoNotFound)
+	assert.ErrorIs(t, svc.Remove(ctx, 1, 12), error_message.ErrWrongPhotos, "last photo")
+	mockPhRepo.AssertExpectations(t)
+}
+
+func TestPhotoService_Reorder(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	mockPhRepo := new(MockPhotoRepo)
+	svc := service.NewPhotoService(mockAdRepo, mockPhRepo)
+	ctx := context.Background()
+
+	mockAdRepo.On("GetByID", mock.Anything, 1).Return(*sampleAdvertModel(1), nil)
+	mockPhRepo.On("ListByAdvertID", mock.Anything, 1).Return(samplePhotoModels(1), nil)
+
+	t.Run("Success", func(t *testing.T) {
+		mockPhRepo.On("Reorder", mock.Anything, 1, []int{13, 11, 12}).Return(nil).Once()
+		assert.NoError(t, svc.Reorder(ctx, 1, []int{13, 11, 12}))
+	})
+
+	t.Run("NotAPermutation", func(t *testing.T) {
+		assert.ErrorIs(t, svc.Reorder(ctx, 1, []int{13, 11}), error_message.ErrWrongPhotoOrder)
+		assert.ErrorIs(t, svc.Reorder(ctx, 1, []int{13, 13, 12}), error_message.ErrWrongPhotoOrder)
+		assert.ErrorIs(t, svc.Reorder(ctx, 1, []int{13, 11, 99}), error_message.ErrWrongPhotoOrder)
+	})
+
+	t.Run("SetMain", func(t *testing.T) {
+		mockPhRepo.On("Reorder", mock.Anything, 1, []int{12, 11, 13}).Return(nil).Once()
+		assert.NoError(t, svc.SetMain(ctx, 1, 12))
+		// Already main — nothing to do
+		assert.NoError(t, svc.SetMain(ctx, 1, 11))
+		assert.ErrorIs(t, svc.SetMain(ctx, 1, 99), error_message.ErrPhotoNotFound)
+	})
+
+	mockPhRepo.AssertExpectations(t)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// SavedSearchInput contains data for saving a list query.
+// Sort uses the list format, e.g. "price_asc"; empty means the default order.
+type SavedSearchInput struct {
+	Name   string
+	Email  string
+	Filter model.AdvertFilter
+	Sort   string
+}
+
+// SavedSearchService describes the business logic of saved searches.
+type SavedSearchService interface {
+	// Create validates and stores a search of the user.
+	Create(ctx context.Context, userID string, input SavedSearchInput) (model.SavedSearch, error)
+
+	// List returns all searches of the user.
+	List(ctx context.Context, userID string) ([]model.SavedSearch, error)
+
+	// Delete removes a search of the user.
+	Delete(ctx context.Context, userID string, id int) error
+
+	// Results runs the stored query like AdvertService.List.
+	Results(ctx context.Context, userID string, id, page int) ([]AdvertSummary, error)
+}
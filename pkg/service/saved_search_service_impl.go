@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+type savedSearchService struct {
+	searchRepo repository.SavedSearchRepo
+	advertSvc  AdvertService
+}
+
+// NewSavedSearchService creates a SavedSearchService; stored queries are run through advertSvc.
+func NewSavedSearchService(sr repository.SavedSearchRepo, advertSvc AdvertService) SavedSearchService {
+	return &savedSearchService{
+		searchRepo: sr,
+		advertSvc:  advertSvc,
+	}
+}
+
+// splitSort splits a "price_asc"-like sort and validates it with the list rules.
+func splitSort(sort string) (string, string, error) {
+	if sort == "" {
+		return "", "", nil
+	}
+	field, order, ok := strings.Cut(sort, "_")
+	if !ok {
+		return "", "", error_message.ErrWrongSortParams
+	}
+	if _, _, err := resolveSort(field, order); err != nil {
+		return "", "", error_message.ErrWrongSortParams
+	}
+	return field, order, nil
+}
+
+func (s *savedSearchService) Create(ctx context.Context, userID string, input SavedSearchInput) (model.SavedSearch, error) {
+	if userID == "" {
+		return model.SavedSearch{}, error_message.ErrMissingUserID
+	}
+	if input.Name == "" || len(input.Name) > 200 {
+		return model.SavedSearch{}, error_message.ErrWrongSearchName
+	}
+	if input.Email != "" {
+		if addr, err := mail.ParseAddress(input.Email); err != nil || addr.Address != input.Email {
+			return model.SavedSearch{}, error_message.ErrWrongEmail
+		}
+	}
+	if _, _, err := splitSort(input.Sort); err != nil {
+		return model.SavedSearch{}, err
+	}
+
+	search := model.SavedSearch{
+		UserID:      userID,
+		Email:       input.Email,
+		Name:        input.Name,
+		MinPrice:    input.Filter.MinPrice,
+		MaxPrice:    input.Filter.MaxPrice,
+		CreatedFrom: input.Filter.CreatedFrom,
+		CreatedTo:   input.Filter.CreatedTo,
+		Sort:        input.Sort,
+		CreatedAt:   time.Now(),
+	}
+	id, err := s.searchRepo.Create(ctx, search)
+	if err != nil {
+		return model.SavedSearch{}, fmt.Errorf("service.Create: searchRepo.Create: %w", err)
+	}
+	search.ID = id
+	return search, nil
+}
+
+func (s *savedSearchService) List(ctx context.Context, userID string) ([]model.SavedSearch, error) {
+	if userID == "" {
+		return nil, error_message.ErrMissingUserID
+	}
+	return s.searchRepo.ListByUser(ctx, userID)
+}
+
+func (s *savedSearchService) Delete(ctx context.Context, userID string, id int) error {
+	if userID == "" {
+		return error_message.ErrMissingUserID
+	}
+	if err := s.searchRepo.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return error_message.ErrSavedSearchNotFound
+		}
+		return fmt.Errorf("service.Delete: searchRepo.Delete (id=%d): %w", id, err)
+	}
+	return nil
+}
+
+func (s *savedSearchService) Results(ctx context.Context, userID string, id, page int) ([]AdvertSummary, error) {
+	if userID == "" {
+		return nil, error_message.ErrMissingUserID
+	}
+	search, err := s.searchRepo.GetByID(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, error_message.ErrSavedSearchNotFound
+		}
+		return nil, fmt.Errorf("service.Results: searchRepo.GetByID (id=%d): %w", id, err)
+	}
+	field, order, err := splitSort(search.Sort)
+	if err != nil {
+		return nil, err
+	}
+	details, err := s.advertSvc.List(ctx, search.Filter(), page, field, order, SummaryFields)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]AdvertSummary, 0, len(details))
+	for _, detail := range details {
+		summaries = append(summaries, detail.AdvertSummary)
+	}
+	return summaries, nil
+}
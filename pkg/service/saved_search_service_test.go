@@ -0,0 +1,154 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/notify"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSavedSearchRepo implements a mock for repository.SavedSearchRepo
+type MockSavedSearchRepo struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchRepo) Create(ctx context.Context, search model.SavedSearch) (int, error) {
+	args := m.Called(ctx, search)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSavedSearchRepo) ListByUser(ctx context.Context, userID string) ([]model.SavedSearch, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]model.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepo) GetByID(ctx context.Context, userID string, id int) (model.SavedSearch, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Get(0).(model.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchRepo) Delete(ctx context.Context, userID string, id int) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepo) ListMatching(ctx context.Context, ad model.Advert) ([]model.SavedSearch, error) {
+	args := m.Called(ctx, ad)
+	return args.Get(0).([]model.SavedSearch), args.Error(1)
+}
+
+// chanNotifier passes notifications to a channel
+type chanNotifier chan notify.Notification
+
+func (n chanNotifier) Notify(_ context.Context, msg notify.Notification) error {
+	n <- msg
+	return nil
+}
+
+func TestSavedSearchService_Create(t *testing.T) {
+	mockRepo := new(MockSavedSearchRepo)
+	svc := service.NewSavedSearchService(mockRepo, nil)
+	ctx := context.Background()
+	max := 500.0
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(s model.SavedSearch) bool {
+			return s.UserID == "alice" && s.Name == "Bikes" && *s.MaxPrice == 500 && s.Sort == "price_asc"
+		})).Return(3, nil).Once()
+
+		search, err := svc.Create(ctx, "alice", service.SavedSearchInput{
+			Name:   "Bikes",
+			Email:  "alice@example.com",
+			Filter: model.AdvertFilter{MaxPrice: &max},
+			Sort:   "price_asc",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, search.ID)
+	})
+
+	cases := []struct {
+		name   string
+		userID string
+		input  service.SavedSearchInput
+		err    error
+	}{
+		{"Anonymous", "", service.SavedSearchInput{Name: "Bikes"}, error_message.ErrMissingUserID},
+		{"NoName", "alice", service.SavedSearchInput{}, error_message.ErrWrongSearchName},
+		{"BadEmail", "alice", service.SavedSearchInput{Name: "Bikes", Email: "Alice <alice@example.com>"}, error_message.ErrWrongEmail},
+		{"BadSort", "alice", service.SavedSearchInput{Name: "Bikes", Sort: "name_asc"}, error_message.ErrWrongSortParams},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.Create(ctx, tc.userID, tc.input)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSavedSearchService_Results(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	mockPhRepo := new(MockPhotoRepo)
+	mockRepo := new(MockSavedSearchRepo)
+	advertSvc := service.NewAdvertService(mockAdRepo, mockPhRepo, new(MockFavoriteRepo))
+	svc := service.NewSavedSearchService(mockRepo, advertSvc)
+	ctx := context.Background()
+	min := 100.0
+
+	mockRepo.On("GetByID", mock.Anything, "alice", 3).
+		Return(model.SavedSearch{ID: 3, UserID: "alice", MinPrice: &min, Sort: "price_desc"}, nil).Once()
+	mockRepo.On("GetByID", mock.Anything, "alice", 4).Return(model.SavedSearch{}, sql.ErrNoRows).Once()
+	mockAdRepo.On("List", mock.Anything, model.AdvertFilter{MinPrice: &min}, 10, 10, "price", "DESC").
+		Return([]model.Advert{}, nil).Once()
+
+	adverts, err := svc.Results(ctx, "alice", 3, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, adverts)
+
+	_, err = svc.Results(ctx, "alice", 4, 1)
+	assert.ErrorIs(t, err, error_message.ErrSavedSearchNotFound)
+	mockRepo.AssertExpectations(t)
+	mockAdRepo.AssertExpectations(t)
+}
+
+func TestSearchMatcher_NotifiesOnCreate(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	mockPhRepo := new(MockPhotoRepo)
+	mockRepo := new(MockSavedSearchRepo)
+	notifications := make(chanNotifier, 2)
+
+	matcher := service.NewSearchMatcher(mockAdRepo, mockRepo, notifications)
+	svc := service.NewSearchMatchingService(service.NewAdvertService(mockAdRepo, mockPhRepo, new(MockFavoriteRepo)), matcher)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go matcher.Run(ctx)
+
+	ad := model.Advert{ID: 9, OwnerID: "seller", Name: "Bike", Price: 120}
+	mockAdRepo.On("Create", mock.Anything, mock.Anything).Return(9, nil).Once()
+	mockAdRepo.On("GetByID", mock.Anything, 9).Return(ad, nil).Once()
+	mockRepo.On("ListMatching", mock.Anything, ad).Return([]model.SavedSearch{
+		{ID: 1, UserID: "alice", Email: "alice@example.com", Name: "Bikes"},
+		{ID: 2, UserID: "bob", Name: "Cheap"},
+	}, nil).Once()
+
+	_, err := svc.Create(ctx, service.CreateAdvertInput{OwnerID: "seller", Name: "Bike", Description: "Red bike", Price: 120, Photos: []string{"http://img"}})
+	assert.NoError(t, err)
+
+	for _, want := range []string{"alice", "bob"} {
+		select {
+		case n := <-notifications:
+			assert.Equal(t, want, n.UserID)
+			assert.Contains(t, n.Body, "/api/adverts/9")
+		case <-time.After(time.Second):
+			t.Fatalf("no notification for %s", want)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package service
+
+import "context"
+
+// SearchMatcher evaluates newly created adverts against the saved searches
+// in the background and notifies the owners of matching searches.
+type SearchMatcher interface {
+	// Enqueue schedules the advert for matching; it never blocks.
+	Enqueue(advertID int)
+
+	// Run processes queued adverts until ctx is cancelled.
+	Run(ctx context.Context)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/notify"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+// matcherQueueSize is how many new adverts may wait for matching.
+const matcherQueueSize = 1000
+
+type searchMatcher struct {
+	advertRepo repository.AdvertRepo
+	searchRepo repository.SavedSearchRepo
+	notifier   notify.Notifier
+	queue      chan int
+}
+
+func NewSearchMatcher(ar repository.AdvertRepo, sr repository.SavedSearchRepo, notifier notify.Notifier) SearchMatcher {
+	return &searchMatcher{
+		advertRepo: ar,
+		searchRepo: sr,
+		notifier:   notifier,
+		queue:      make(chan int, matcherQueueSize),
+	}
+}
+
+func (m *searchMatcher) Enqueue(advertID int) {
+	select {
+	case m.queue <- advertID:
+	default:
+		log.Printf("saved search queue is full, skipping advert %d", advertID)
+	}
+}
+
+func (m *searchMatcher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-m.queue:
+			if err := m.match(ctx, id); err != nil {
+				log.Printf("failed to match advert %d against saved searches: %v", id, err)
+			}
+		}
+	}
+}
+
+// match notifies the owner of every search matching the advert;
+// a failed notification does not stop the others.
+func (m *searchMatcher) match(ctx context.Context, advertID int) error {
+	ad, err := m.advertRepo.GetByID(ctx, advertID)
+	if err != nil {
+		return err
+	}
+	searches, err := m.searchRepo.ListMatching(ctx, ad)
+	if err != nil {
+		return err
+	}
+	for _, search := range searches {
+		err := m.notifier.Notify(ctx, notify.Notification{
+			UserID:  search.UserID,
+			To:      search.Email,
+			Subject: fmt.Sprintf("New advert for your search %q", search.Name),
+			Body:    fmt.Sprintf("%s — %.2f\n/api/adverts/%d", ad.Name, ad.Price, ad.ID),
+		})
+		if err != nil {
+			log.Printf("failed to notify %s about advert %d: %v", search.UserID, ad.ID, err)
+		}
+	}
+	return nil
+}
+
+// searchMatchingService hands every created advert to the matcher.
+type searchMatchingService struct {
+	AdvertService
+	matcher SearchMatcher
+}
+
+// NewSearchMatchingService wraps next so that adverts it creates are matched against saved searches.
+func NewSearchMatchingService(next AdvertService, matcher SearchMatcher) AdvertService {
+	return &searchMatchingService{AdvertService: next, matcher: matcher}
+}
+
+func (s *searchMatchingService) Create(ctx context.Context, input CreateAdvertInput) (int, error) {
+	id, err := s.AdvertService.Create(ctx, input)
+	if err != nil {
+		return id, err
+	}
+	s.matcher.Enqueue(id)
+	return id, nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// PriceBucket is a single bar of the price histogram: [From, To), To == nil means no upper bound.
+type PriceBucket struct {
+	From  float64  `json:"from"`
+	To    *float64 `json:"to"`
+	Count int64    `json:"count"`
+}
+
+// DailyCount is the number of adverts created on Date (YYYY-MM-DD).
+type DailyCount struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// AdvertStats are catalogue statistics over the filtered adverts.
+// Price fields are omitted when no advert matches the filter.
+type AdvertStats struct {
+	Total       int64         `json:"total"`
+	MinPrice    *float64      `json:"min_price,omitempty"`
+	MaxPrice    *float64      `json:"max_price,omitempty"`
+	AvgPrice    *float64      `json:"avg_price,omitempty"`
+	MedianPrice *float64      `json:"median_price,omitempty"`
+	Histogram   []PriceBucket `json:"histogram"`
+	// PerDay covers every day from From to To, including days without adverts
+	PerDay []DailyCount `json:"per_day"`
+	From   string       `json:"from"`
+	To     string       `json:"to"`
+}
+
+// StatsService describes catalogue statistics for product managers.
+type StatsService interface {
+	// AdvertStats computes statistics over the adverts matching filter.
+	// buckets are ascending histogram bounds, nil uses the configured defaults.
+	// The per-day counts cover the filter's date range, the last 30 days by default.
+	// Results are cached for a short time.
+	AdvertStats(ctx context.Context, filter model.AdvertFilter, buckets []float64) (AdvertStats, error)
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+const (
+	// defaultStatsDays is the per-day range when the filter has no dates.
+	defaultStatsDays = 30
+	maxStatsDays     = 366
+	maxStatsBuckets  = 50
+)
+
+type statsCacheEntry struct {
+	stats   AdvertStats
+	expires time.Time
+}
+
+type statsService struct {
+	statsRepo      repository.StatsRepo
+	defaultBuckets []float64
+	ttl            time.Duration
+
+	mu    sync.Mutex
+	cache map[string]statsCacheEntry
+}
+
+// NewStatsService creates a StatsService caching results for ttl (0 disables the cache).
+func NewStatsService(sr repository.StatsRepo, defaultBuckets []float64, ttl time.Duration) StatsService {
+	return &statsService{
+		statsRepo:      sr,
+		defaultBuckets: defaultBuckets,
+		ttl:            ttl,
+		cache:          make(map[string]statsCacheEntry),
+	}
+}
+
+func (s *statsService) AdvertStats(ctx context.Context, filter model.AdvertFilter, buckets []float64) (AdvertStats, error) {
+	if buckets == nil {
+		buckets = s.defaultBuckets
+	}
+	if err := validateBuckets(buckets); err != nil {
+		return AdvertStats{}, err
+	}
+	from, to, err := statsDateRange(filter, time.Now())
+	if err != nil {
+		return AdvertStats{}, err
+	}
+
+	key := statsCacheKey(filter, buckets)
+	if stats, ok := s.cached(key); ok {
+		return stats, nil
+	}
+
+	priceStats, err := s.statsRepo.PriceStats(ctx, filter)
+	if err != nil {
+		return AdvertStats{}, fmt.Errorf("service.AdvertStats: statsRepo.PriceStats: %w", err)
+	}
+	counts, err := s.statsRepo.PriceHistogram(ctx, filter, buckets)
+	if err != nil {
+		return AdvertStats{}, fmt.Errorf("service.AdvertStats: statsRepo.PriceHistogram: %w", err)
+	}
+	dayFilter := filter
+	dayFilter.CreatedFrom, dayFilter.CreatedTo = &from, &to
+	days, err := s.statsRepo.CreatedPerDay(ctx, dayFilter)
+	if err != nil {
+		return AdvertStats{}, fmt.Errorf("service.AdvertStats: statsRepo.CreatedPerDay: %w", err)
+	}
+
+	stats := AdvertStats{
+		Total:       priceStats.Count,
+		MinPrice:    priceStats.Min,
+		MaxPrice:    priceStats.Max,
+		AvgPrice:    priceStats.Avg,
+		MedianPrice: priceStats.Median,
+		Histogram:   histogram(buckets, counts),
+		PerDay:      fillDays(from, to, days),
+		From:        from.Format(time.DateOnly),
+		To:          to.AddDate(0, 0, -1).Format(time.DateOnly),
+	}
+	s.store(key, stats)
+	return stats, nil
+}
+
+func (s *statsService) cached(key string) (AdvertStats, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.cache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return AdvertStats{}, false
+	}
+	return entry.stats, true
+}
+
+func (s *statsService) store(key string, stats AdvertStats) {
+	if s.ttl <= 0 {
+		return
+	}
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Drop expired entries so that arbitrary filters do not pile up
+	for k, entry := range s.cache {
+		if now.After(entry.expires) {
+			delete(s.cache, k)
+		}
+	}
+	s.cache[key] = statsCacheEntry{stats: stats, expires: now.Add(s.ttl)}
+}
+
+func validateBuckets(buckets []float64) error {
+	if len(buckets) == 0 || len(buckets) > maxStatsBuckets {
+		return error_message.ErrWrongBuckets
+	}
+	for i, b := range buckets {
+		if b < 0 || i > 0 && b <= buckets[i-1] {
+			return error_message.ErrWrongBuckets
+		}
+	}
+	return nil
+}
+
+// statsDateRange returns the [from, to) day range of the per-day counts.
+func statsDateRange(filter model.AdvertFilter, now time.Time) (time.Time, time.Time, error) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	to := today.AddDate(0, 0, 1)
+	if filter.CreatedTo != nil {
+		to = filter.CreatedTo.UTC().Truncate(24 * time.Hour)
+		if to.Before(*filter.CreatedTo) {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+	from := to.AddDate(0, 0, -defaultStatsDays)
+	if filter.CreatedFrom != nil {
+		from = filter.CreatedFrom.UTC().Truncate(24 * time.Hour)
+	}
+	if !from.Before(to) || to.Sub(from) > maxStatsDays*24*time.Hour {
+		return time.Time{}, time.Time{}, error_message.ErrWrongDateRange
+	}
+	return from, to, nil
+}
+
+func statsCacheKey(filter model.AdvertFilter, buckets []float64) string {
+	return fmt.Sprint(buckets) + "|" + filterKey(filter)
+}
+
+// filterKey renders the filter for cache keys.
+func filterKey(filter model.AdvertFilter) string {
+	var parts []string
+	for _, p := range []*float64{filter.MinPrice, filter.MaxPrice} {
+		if p != nil {
+			parts = append(parts, fmt.Sprintf("%g", *p))
+		} else {
+			parts = append(parts, "-")
+		}
+	}
+	for _, t := range []*time.Time{filter.CreatedFrom, filter.CreatedTo} {
+		if t != nil {
+			parts = append(parts, t.UTC().Format(time.RFC3339Nano))
+		} else {
+			parts = append(parts, "-")
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+// histogram turns the per-bucket counts of the repository into bars; the bar below
+// the first bound is omitted when the first bound is 0, as prices are positive.
+func histogram(bounds []float64, counts []int64) []PriceBucket {
+	bars := make([]PriceBucket, 0, len(bounds)+1)
+	if bounds[0] > 0 {
+		upper := bounds[0]
+		bars = append(bars, PriceBucket{From: 0, To: &upper, Count: counts[0]})
+	}
+	for i, from := range bounds {
+		bar := PriceBucket{From: from, Count: counts[i+1]}
+		if i+1 < len(bounds) {
+			upper := bounds[i+1]
+			bar.To = &upper
+		}
+		bars = append(bars, bar)
+	}
+	return bars
+}
+
+// fillDays lists every day of [from, to) with its count, 0 for days without adverts.
+func fillDays(from, to time.Time, days []model.DayCount) []DailyCount {
+	counts := make(map[string]int64, len(days))
+	for _, d := range days {
+		counts[d.Day.Format(time.DateOnly)] = d.Count
+	}
+	result := []DailyCount{}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		result = append(result, DailyCount{Date: date, Count: counts[date]})
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStatsRepo implements a mock for repository.StatsRepo
+type MockStatsRepo struct {
+	mock.Mock
+}
+
+func (m *MockStatsRepo) PriceStats(ctx context.Context, filter model.AdvertFilter) (model.PriceStats, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(model.PriceStats), args.Error(1)
+}
+
+func (m *MockStatsRepo) PriceHistogram(ctx context.Context, filter model.AdvertFilter, bounds []float64) ([]int64, error) {
+	args := m.Called(ctx, filter, bounds)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockStatsRepo) CreatedPerDay(ctx context.Context, filter model.AdvertFilter) ([]model.DayCount, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]model.DayCount), args.Error(1)
+}
+
+func day(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+func TestStatsService_AdvertStats(t *testing.T) {
+	mockRepo := new(MockStatsRepo)
+	svc := service.NewStatsService(mockRepo, []float64{0, 100}, time.Minute)
+	ctx := context.Background()
+
+	from, to := day("2024-03-01"), day("2024-03-04")
+	filter := model.AdvertFilter{CreatedFrom: &from, CreatedTo: &to}
+	min, max, avg, median := 10.0, 300.0, 120.0, 50.0
+
+	mockRepo.On("PriceStats", mock.Anything, filter).
+		Return(model.PriceStats{Count: 3, Min: &min, Max: &max, Avg: &avg, Median: &median}, nil).Once()
+	mockRepo.On("PriceHistogram", mock.Anything, filter, []float64{0, 100}).
+		Return([]int64{0, 2, 1}, nil).Once()
+	mockRepo.On("CreatedPerDay", mock.Anything, filter).
+		Return([]model.DayCount{{Day: day("2024-03-01"), Count: 2}, {Day: day("2024-03-03"), Count: 1}}, nil).Once()
+
+	stats, err := svc.AdvertStats(ctx, filter, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), stats.Total)
+	assert.Equal(t, 50.0, *stats.MedianPrice)
+	assert.Equal(t, "2024-03-01", stats.From)
+	assert.Equal(t, "2024-03-03", stats.To)
+
+	// The empty bar below 0 is omitted, the last bar is open-ended
+	if assert.Len(t, stats.Histogram, 2) {
+		assert.Equal(t, int64(2), stats.Histogram[0].Count)
+		assert.Equal(t, 100.0, *stats.Histogram[0].To)
+		assert.Nil(t, stats.Histogram[1].To)
+	}
+	assert.Equal(t, []service.DailyCount{
+		{Date: "2024-03-01", Count: 2},
+		{Date: "2024-03-02", Count: 0},
+		{Date: "2024-03-03", Count: 1},
+	}, stats.PerDay)
+
+	// The second call is served from the cache
+	cached, err := svc.AdvertStats(ctx, filter, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, stats, cached)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStatsService_Validation(t *testing.T) {
+	svc := service.NewStatsService(new(MockStatsRepo), []float64{0, 100}, time.Minute)
+	ctx := context.Background()
+
+	_, err := svc.AdvertStats(ctx, model.AdvertFilter{}, []float64{100, 10})
+	assert.ErrorIs(t, err, error_message.ErrWrongBuckets)
+
+	from, to := day("2020-01-01"), day("2024-01-01")
+	_, err = svc.AdvertStats(ctx, model.AdvertFilter{CreatedFrom: &from, CreatedTo: &to}, nil)
+	assert.ErrorIs(t, err, error_message.ErrWrongDateRange)
+}
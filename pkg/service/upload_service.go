@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// UploadService describes storing uploaded photo files and attaching them to adverts.
+type UploadService interface {
+	// Upload checks the size and the sniffed content type of file, stores it
+	// in the blob storage and adds a photo pointing at /media/<key> at position
+	// (0 = append), exactly like PhotoService.Add.
+	Upload(ctx context.Context, advertID int, file io.Reader, size int64, position int) (model.Photo, error)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/storage"
+)
+
+// MediaPathPrefix is the URL path under which stored blobs are served.
+const MediaPathPrefix = "/media/"
+
+// photoExtensions lists accepted sniffed content types and the extension used for the key.
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type uploadService struct {
+	advertRepo repository.AdvertRepo
+	photoSvc   PhotoService
+	variants   VariantService
+	store      storage.BlobStore
+	maxSize    int64
+	baseURL    string
+}
+
+// NewUploadService creates an UploadService. Files larger than maxSize bytes are rejected;
+// baseURL is prepended to MediaPathPrefix in the stored photo URLs.
+// Every stored photo is handed to variants for resizing.
+func NewUploadService(
+	ar repository.AdvertRepo,
+	photoSvc PhotoService,
+	variants VariantService,
+	store storage.BlobStore,
+	maxSize int64,
+	baseURL string,
+) UploadService {
+	return &uploadService{
+		advertRepo: ar,
+		photoSvc:   photoSvc,
+		variants:   variants,
+		store:      store,
+		maxSize:    maxSize,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (s *uploadService) Upload(ctx context.Context, advertID int, file io.Reader, size int64, position int) (model.Photo, error) {
+	if size > s.maxSize {
+		return model.Photo{}, error_message.ErrPhotoTooLarge
+	}
+
+	// Never trust the client's Content-Type: sniff the first bytes instead
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return model.Photo{}, err
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	ext, ok := photoExtensions[contentType]
+	if !ok {
+		return model.Photo{}, error_message.ErrUnsupportedPhotoType
+	}
+
+	if _, err := s.advertRepo.GetByID(ctx, advertID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Photo{}, error_message.ErrAdvertNotFound
+		}
+		return model.Photo{}, fmt.Errorf("service.Upload: advertRepo.GetByID (id=%d): %w", advertID, err)
+	}
+
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return model.Photo{}, fmt.Errorf("service.Upload: generate key: %w", err)
+	}
+	key := fmt.Sprintf("adverts/%d/%s%s", advertID, hex.EncodeToString(name), ext)
+
+	body := io.MultiReader(bytes.NewReader(head), file)
+	if err := s.store.Put(ctx, key, io.LimitReader(body, size), size, contentType); err != nil {
+		return model.Photo{}, fmt.Errorf("service.Upload: store %s: %w", key, err)
+	}
+
+	photo, err := s.photoSvc.Add(ctx, advertID, s.baseURL+MediaPathPrefix+key, position)
+	if err != nil {
+		// Do not leave an orphaned blob behind
+		if delErr := s.store.Delete(ctx, key); delErr != nil {
+			log.Printf("failed to delete orphaned blob %s: %v", key, delErr)
+		}
+		return model.Photo{}, err
+	}
+	s.variants.Enqueue(photo, key)
+	return photo, nil
+}
@@ -0,0 +1,74 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// pngHeader is enough for content sniffing to detect image/png
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+func TestUploadService_Upload(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	mockPhRepo := new(MockPhotoRepo)
+	store, err := storage.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+
+	photoSvc := service.NewPhotoService(mockAdRepo, mockPhRepo)
+	// No variant specs: nothing is queued for resizing
+	variantSvc := service.NewVariantService(mockPhRepo, service.NoInvalidation, store, nil, service.VariantOptions{})
+	svc := service.NewUploadService(mockAdRepo, photoSvc, variantSvc, store, 1024, "http://cdn.local/")
+	ctx := context.Background()
+
+	mockAdRepo.On("GetByID", mock.Anything, 1).Return(*sampleAdvertModel(1), nil)
+
+	t.Run("Success", func(t *testing.T) {
+		var stored model.Photo
+		mockPhRepo.
+			On("Insert", mock.Anything, mock.MatchedBy(func(p model.Photo) bool {
+				return p.AdvertID == 1 && p.Position == 0 &&
+					strings.HasPrefix(p.URL, "http://cdn.local/media/adverts/1/") && strings.HasSuffix(p.URL, ".png")
+			}), 3).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(model.Photo) }).
+			Return(model.Photo{ID: 5}, nil).
+			Once()
+
+		body := pngHeader + "image-data"
+		photo, err := svc.Upload(ctx, 1, strings.NewReader(body), int64(len(body)), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, photo.ID)
+
+		// The blob is readable under the key from the URL
+		rc, contentType, err := store.Get(ctx, strings.TrimPrefix(stored.URL, "http://cdn.local/media/"))
+		assert.NoError(t, err)
+		got, _ := io.ReadAll(rc)
+		rc.Close()
+		assert.Equal(t, body, string(got))
+		assert.Equal(t, "image/png", contentType)
+	})
+
+	t.Run("UnsupportedType", func(t *testing.T) {
+		body := "<html>not an image</html>"
+		_, err := svc.Upload(ctx, 1, strings.NewReader(body), int64(len(body)), 0)
+		assert.ErrorIs(t, err, error_message.ErrUnsupportedPhotoType)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		body := bytes.Repeat([]byte("x"), 2048)
+		_, err := svc.Upload(ctx, 1, bytes.NewReader(body), int64(len(body)), 0)
+		assert.ErrorIs(t, err, error_message.ErrPhotoTooLarge)
+	})
+
+	mockPhRepo.AssertExpectations(t)
+}
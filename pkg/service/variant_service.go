@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// ThumbVariant is the variant exposed as main_photo_thumb_url in advert summaries.
+const ThumbVariant = "thumb"
+
+// VariantSpec configures one resized variant: the image is scaled down
+// to fit into Width x Height keeping the aspect ratio.
+type VariantSpec struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// VariantOptions configures variant generation.
+type VariantOptions struct {
+	Workers int
+	// SweepInterval between two sweeps for uploaded photos missing variants:
+	// jobs dropped because the queue was full or lost on restart
+	SweepInterval time.Duration
+	BatchSize     int
+	// BaseURL is the prefix of uploaded photo URLs (see NewUploadService)
+	BaseURL string
+}
+
+// VariantService describes asynchronous generation of resized photo variants.
+type VariantService interface {
+	// Enqueue schedules generation of all configured variants for an uploaded photo
+	// stored under key. It never blocks: if the queue is full, the photo is skipped
+	// and left to GenerateMissing.
+	Enqueue(photo model.Photo, key string)
+
+	// GenerateMissing generates the variants of one batch of uploaded photos lacking
+	// some of them, continuing after the previous batch, and returns how many were processed.
+	GenerateMissing(ctx context.Context) (int, error)
+
+	// Run processes the queue and calls GenerateMissing at start and periodically
+	// until ctx is cancelled.
+	Run(ctx context.Context)
+}
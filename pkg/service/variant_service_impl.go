@@ -0,0 +1,217 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/imaging"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/storage"
+)
+
+const (
+	// variantQueueSize is how many photos may wait for variant generation.
+	variantQueueSize = 100
+	// maxVariantSourcePixels protects the workers from decompression bombs.
+	maxVariantSourcePixels = 50_000_000
+	variantJPEGQuality     = 85
+)
+
+type variantJob struct {
+	photo model.Photo
+	key   string
+}
+
+type variantService struct {
+	photoRepo   repository.PhotoRepo
+	invalidator AdvertInvalidator
+	store       storage.BlobStore
+	specs       []VariantSpec
+	names       []string
+	opts        VariantOptions
+	queue       chan variantJob
+
+	// sweepMu guards sweepAfter, the last photo ID seen by the current sweep
+	sweepMu    sync.Mutex
+	sweepAfter int
+}
+
+// NewVariantService creates a VariantService generating specs;
+// inv is told about the advert of every photo that got its variants.
+func NewVariantService(pr repository.PhotoRepo, inv AdvertInvalidator, store storage.BlobStore, specs []VariantSpec, opts VariantOptions) VariantService {
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	if opts.SweepInterval <= 0 {
+		opts.SweepInterval = 10 * time.Minute
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 50
+	}
+	opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+	names := make([]string, len(specs))
+	for i, spec := range specs {
+		names[i] = spec.Name
+	}
+	return &variantService{
+		photoRepo:   pr,
+		invalidator: inv,
+		store:       store,
+		specs:       specs,
+		names:       names,
+		opts:        opts,
+		queue:       make(chan variantJob, variantQueueSize),
+	}
+}
+
+func (s *variantService) Enqueue(photo model.Photo, key string) {
+	if len(s.specs) == 0 {
+		return
+	}
+	select {
+	case s.queue <- variantJob{photo: photo, key: key}:
+	default:
+		log.Printf("variant queue is full, skipping photo %d", photo.ID)
+	}
+}
+
+func (s *variantService) GenerateMissing(ctx context.Context) (int, error) {
+	if len(s.specs) == 0 {
+		return 0, nil
+	}
+	s.sweepMu.Lock()
+	defer s.sweepMu.Unlock()
+
+	prefix := s.opts.BaseURL + MediaPathPrefix
+	photos, err := s.photoRepo.ListMissingVariants(ctx, prefix, s.names, s.sweepAfter, s.opts.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("service.GenerateMissing: photoRepo.ListMissingVariants: %w", err)
+	}
+	for _, photo := range photos {
+		s.sweepAfter = photo.ID
+		key := strings.TrimPrefix(photo.URL, prefix)
+		// Other formats never get variants, do not load them on every sweep
+		if ext := path.Ext(key); ext != ".jpg" && ext != ".png" {
+			continue
+		}
+		s.process(ctx, variantJob{photo: photo, key: key})
+	}
+	return len(photos), nil
+}
+
+func (s *variantService) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < s.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-s.queue:
+					s.process(ctx, job)
+				}
+			}
+		}()
+	}
+	if len(s.specs) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// A finished sweep starts over from the first photo
+			runBatches(ctx, "variant sweep", s.opts.SweepInterval, s.opts.BatchSize, s.GenerateMissing, func(context.Context) {
+				s.sweepMu.Lock()
+				s.sweepAfter = 0
+				s.sweepMu.Unlock()
+			})
+		}()
+	}
+	wg.Wait()
+}
+
+// process generates the variants of a photo and invalidates its advert.
+func (s *variantService) process(ctx context.Context, job variantJob) {
+	if err := s.generate(ctx, job); err != nil {
+		log.Printf("failed to generate variants of photo %d: %v", job.photo.ID, err)
+		return
+	}
+	s.invalidator.Invalidate(ctx, job.photo.AdvertID)
+}
+
+// generate creates every configured variant of a JPEG or PNG photo;
+// other formats are left as they are.
+func (s *variantService) generate(ctx context.Context, job variantJob) error {
+	rc, _, err := s.store.Get(ctx, job.key)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return err
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if format != "jpeg" && format != "png" {
+		return nil
+	}
+	if cfg.Width*cfg.Height > maxVariantSourcePixels {
+		return fmt.Errorf("image is too large: %dx%d", cfg.Width, cfg.Height)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range s.specs {
+		resized := imaging.Fit(src, spec.Width, spec.Height)
+
+		var buf bytes.Buffer
+		if format == "jpeg" {
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: variantJPEGQuality})
+		} else {
+			err = png.Encode(&buf, resized)
+		}
+		if err != nil {
+			return err
+		}
+
+		key := variantName(job.key, spec.Name)
+		if err := s.store.Put(ctx, key, &buf, int64(buf.Len()), "image/"+format); err != nil {
+			return err
+		}
+		bounds := resized.Bounds()
+		if err := s.photoRepo.SaveVariant(ctx, model.PhotoVariant{
+			PhotoID: job.photo.ID,
+			Name:    spec.Name,
+			URL:     variantName(job.photo.URL, spec.Name),
+			Width:   bounds.Dx(),
+			Height:  bounds.Dy(),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// variantName inserts "_<variant>" before the extension of a key or URL:
+// adverts/1/abc.jpg -> adverts/1/abc_thumb.jpg.
+func variantName(name, variant string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "_" + variant + ext
+}
@@ -0,0 +1,103 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVariantService_GeneratesVariants(t *testing.T) {
+	mockPhRepo := new(MockPhotoRepo)
+	store, err := storage.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 1. Store a 400x200 PNG as if it was just uploaded
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 400, 200))))
+	assert.NoError(t, store.Put(ctx, "adverts/1/abc.png", &buf, int64(buf.Len()), "image/png"))
+
+	specs := []service.VariantSpec{
+		{Name: "thumb", Width: 100, Height: 100},
+		{Name: "large", Width: 1000, Height: 1000},
+	}
+	svc := service.NewVariantService(mockPhRepo, service.NoInvalidation, store, specs, service.VariantOptions{Workers: 1})
+	mockPhRepo.On("ListMissingVariants", mock.Anything, "/media/", []string{"thumb", "large"}, 0, 50).Return([]model.Photo{}, nil)
+
+	// 2. Expect one SaveVariant per spec; images are never upscaled
+	saved := make(chan struct{}, len(specs))
+	mockPhRepo.On("SaveVariant", mock.Anything, model.PhotoVariant{
+		PhotoID: 3, Name: "thumb", URL: "/media/adverts/1/abc_thumb.png", Width: 100, Height: 50,
+	}).Run(func(mock.Arguments) { saved <- struct{}{} }).Return(nil).Once()
+	mockPhRepo.On("SaveVariant", mock.Anything, model.PhotoVariant{
+		PhotoID: 3, Name: "large", URL: "/media/adverts/1/abc_large.png", Width: 400, Height: 200,
+	}).Run(func(mock.Arguments) { saved <- struct{}{} }).Return(nil).Once()
+
+	go svc.Run(ctx)
+	svc.Enqueue(model.Photo{ID: 3, AdvertID: 1, URL: "/media/adverts/1/abc.png"}, "adverts/1/abc.png")
+
+	for range specs {
+		select {
+		case <-saved:
+		case <-time.After(time.Second):
+			t.Fatal("variants were not generated in time")
+		}
+	}
+	mockPhRepo.AssertExpectations(t)
+
+	// 3. The resized blob is stored next to the original
+	rc, contentType, err := store.Get(ctx, "adverts/1/abc_thumb.png")
+	assert.NoError(t, err)
+	defer rc.Close()
+	assert.Equal(t, "image/png", contentType)
+	thumb, err := png.Decode(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), thumb.Bounds())
+}
+
+func TestVariantService_GenerateMissing(t *testing.T) {
+	mockPhRepo := new(MockPhotoRepo)
+	store, err := storage.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	// 1. A PNG whose variants were never generated, e.g. the queue was full
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 400, 200))))
+	assert.NoError(t, store.Put(ctx, "adverts/2/def.png", &buf, int64(buf.Len()), "image/png"))
+
+	specs := []service.VariantSpec{{Name: "thumb", Width: 100, Height: 100}}
+	svc := service.NewVariantService(mockPhRepo, service.NoInvalidation, store, specs, service.VariantOptions{
+		BatchSize: 2,
+		BaseURL:   "https://cdn.example.com/",
+	})
+
+	// 2. The GIF is skipped without being loaded, the next batch starts after it
+	mockPhRepo.On("ListMissingVariants", mock.Anything, "https://cdn.example.com/media/", []string{"thumb"}, 0, 2).Return([]model.Photo{
+		{ID: 5, AdvertID: 2, URL: "https://cdn.example.com/media/adverts/2/def.png"},
+		{ID: 6, AdvertID: 2, URL: "https://cdn.example.com/media/adverts/2/ghi.gif"},
+	}, nil).Once()
+	mockPhRepo.On("ListMissingVariants", mock.Anything, "https://cdn.example.com/media/", []string{"thumb"}, 6, 2).Return([]model.Photo{}, nil).Once()
+	mockPhRepo.On("SaveVariant", mock.Anything, model.PhotoVariant{
+		PhotoID: 5, Name: "thumb", URL: "https://cdn.example.com/media/adverts/2/def_thumb.png", Width: 100, Height: 50,
+	}).Return(nil).Once()
+
+	n, err := svc.GenerateMissing(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	n, err = svc.GenerateMissing(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	mockPhRepo.AssertExpectations(t)
+}
@@ -0,0 +1,17 @@
+package service
+
+import "context"
+
+// ViewService counts advert views without a synchronous write per view:
+// views are buffered in memory and flushed to the repository in batches.
+type ViewService interface {
+	// Record counts a view of the advert unless the same viewer (IP and session)
+	// already viewed it within the dedup window. It never blocks on the database.
+	Record(advertID int, viewer string)
+
+	// Flush writes the buffered views to the repository.
+	Flush(ctx context.Context) error
+
+	// Run flushes periodically until ctx is cancelled, then flushes one last time.
+	Run(ctx context.Context)
+}
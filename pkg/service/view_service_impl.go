@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+type viewKey struct {
+	advertID int
+	viewer   string
+}
+
+type viewService struct {
+	advertRepo    repository.AdvertRepo
+	invalidator   AdvertInvalidator
+	flushInterval time.Duration
+	dedupWindow   time.Duration
+
+	mu      sync.Mutex
+	pending map[int]int64
+	// seen holds the time of the last counted view per advert and viewer
+	seen map[viewKey]time.Time
+}
+
+// NewViewService creates a ViewService flushing every flushInterval;
+// repeated views by one viewer within dedupWindow are counted once. inv is told about
+// the adverts of every flushed batch.
+func NewViewService(ar repository.AdvertRepo, inv AdvertInvalidator, flushInterval, dedupWindow time.Duration) ViewService {
+	if flushInterval <= 0 {
+		flushInterval = 10 * time.Second
+	}
+	return &viewService{
+		advertRepo:    ar,
+		invalidator:   inv,
+		flushInterval: flushInterval,
+		dedupWindow:   dedupWindow,
+		pending:       make(map[int]int64),
+		seen:          make(map[viewKey]time.Time),
+	}
+}
+
+func (s *viewService) Record(advertID int, viewer string) {
+	now := time.Now()
+	key := viewKey{advertID: advertID, viewer: viewer}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.seen[key]; ok && now.Sub(last) < s.dedupWindow {
+		return
+	}
+	s.seen[key] = now
+	s.pending[advertID]++
+}
+
+func (s *viewService) Flush(ctx context.Context) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = make(map[int]int64, len(batch))
+	// Forget viewers whose window has passed so that the map does not grow forever
+	for key, last := range s.seen {
+		if now.Sub(last) >= s.dedupWindow {
+			delete(s.seen, key)
+		}
+	}
+	s.mu.Unlock()
+
+	if err := s.advertRepo.AddViews(ctx, batch); err != nil {
+		// Put the batch back, it is retried on the next flush
+		s.mu.Lock()
+		for id, n := range batch {
+			s.pending[id] += n
+		}
+		s.mu.Unlock()
+		return err
+	}
+	if len(batch) > 0 {
+		ids := make([]int, 0, len(batch))
+		for id := range batch {
+			ids = append(ids, id)
+		}
+		s.invalidator.Invalidate(ctx, ids...)
+	}
+	return nil
+}
+
+func (s *viewService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// ctx is already cancelled, give the last flush its own deadline
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := s.Flush(flushCtx); err != nil {
+				log.Printf("failed to flush advert views: %v", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := s.Flush(ctx); err != nil {
+				log.Printf("failed to flush advert views: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestViewService_RecordAndFlush(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	svc := service.NewViewService(mockAdRepo, service.NoInvalidation, time.Minute, time.Hour)
+	ctx := context.Background()
+
+	svc.Record(1, "ip:10.0.0.1")
+	svc.Record(1, "ip:10.0.0.1") // same viewer within the window
+	svc.Record(1, "ip:10.0.0.2")
+	svc.Record(2, "ip:10.0.0.1")
+
+	mockAdRepo.On("AddViews", mock.Anything, map[int]int64{1: 2, 2: 1}).Return(nil).Once()
+	assert.NoError(t, svc.Flush(ctx))
+
+	// The viewer is still remembered after the flush
+	svc.Record(1, "ip:10.0.0.1")
+	mockAdRepo.On("AddViews", mock.Anything, map[int]int64{}).Return(nil).Once()
+	assert.NoError(t, svc.Flush(ctx))
+
+	mockAdRepo.AssertExpectations(t)
+}
+
+func TestViewService_DedupWindowExpires(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	svc := service.NewViewService(mockAdRepo, service.NoInvalidation, time.Minute, 10*time.Millisecond)
+
+	svc.Record(1, "session:abc")
+	time.Sleep(20 * time.Millisecond)
+	svc.Record(1, "session:abc")
+
+	mockAdRepo.On("AddViews", mock.Anything, map[int]int64{1: 2}).Return(nil).Once()
+	assert.NoError(t, svc.Flush(context.Background()))
+	mockAdRepo.AssertExpectations(t)
+}
+
+func TestViewService_FailedFlushIsRetried(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	svc := service.NewViewService(mockAdRepo, service.NoInvalidation, time.Minute, time.Hour)
+	ctx := context.Background()
+
+	svc.Record(1, "ip:10.0.0.1")
+	mockAdRepo.On("AddViews", mock.Anything, map[int]int64{1: 1}).Return(errors.New("db down")).Once()
+	assert.Error(t, svc.Flush(ctx))
+
+	svc.Record(1, "ip:10.0.0.2")
+	mockAdRepo.On("AddViews", mock.Anything, map[int]int64{1: 2}).Return(nil).Once()
+	assert.NoError(t, svc.Flush(ctx))
+	mockAdRepo.AssertExpectations(t)
+}
+
+func TestViewService_RunFlushesOnShutdown(t *testing.T) {
+	mockAdRepo := new(MockAdvertRepo)
+	svc := service.NewViewService(mockAdRepo, service.NoInvalidation, time.Hour, time.Hour)
+
+	flushed := make(chan struct{})
+	mockAdRepo.On("AddViews", mock.Anything, map[int]int64{3: 1}).
+		Run(func(mock.Arguments) { close(flushed) }).
+		Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	svc.Record(3, "ip:10.0.0.1")
+	go svc.Run(ctx)
+	cancel()
+
+	select {
+	case <-flushed:
+	case <-time.After(time.Second):
+		t.Fatal("views were not flushed on shutdown")
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+)
+
+// Headers of a webhook request.
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookService manages webhook subscriptions and delivers advert events to them.
+type WebhookService interface {
+	// Subscribe registers an http(s) endpoint for the events and returns
+	// the subscription with its generated signing secret.
+	Subscribe(ctx context.Context, url string, events []string) (model.WebhookSubscription, error)
+
+	// List returns all subscriptions without secrets.
+	List(ctx context.Context) ([]model.WebhookSubscription, error)
+
+	// Unsubscribe deletes a subscription together with its delivery log.
+	Unsubscribe(ctx context.Context, id int) error
+
+	// Deliveries returns the latest deliveries of a subscription,
+	// optionally filtered by status (pending, delivered or dead).
+	Deliveries(ctx context.Context, subscriptionID int, status string) ([]model.WebhookDelivery, error)
+
+	// Retry schedules a failed or dead delivery again with a fresh retry budget.
+	Retry(ctx context.Context, subscriptionID int, deliveryID int64) error
+
+	// Emit records a delivery of the event with data as payload for every subscriber.
+	Emit(ctx context.Context, event string, data interface{}) error
+
+	// DispatchDue sends one batch of due deliveries and returns how many were attempted.
+	DispatchDue(ctx context.Context) (int, error)
+
+	// Run calls DispatchDue periodically until ctx is cancelled.
+	Run(ctx context.Context)
+}
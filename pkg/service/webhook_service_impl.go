@@ -0,0 +1,266 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/repository"
+)
+
+// webhookDeliveryLogSize is how many deliveries Deliveries returns.
+const webhookDeliveryLogSize = 100
+
+// webhookEvents lists the events a subscription may ask for.
+var webhookEvents = map[string]bool{
+	model.EventAdvertCreated: true,
+	model.EventAdvertUpdated: true,
+	model.EventAdvertDeleted: true,
+}
+
+// WebhookOptions configures webhook delivery.
+type WebhookOptions struct {
+	// Interval between two dispatch batches
+	Interval time.Duration
+	// Timeout of a single HTTP request
+	Timeout   time.Duration
+	BatchSize int
+	// MaxAttempts after which a delivery becomes dead
+	MaxAttempts int
+	// The n-th retry waits BaseBackoff * 2^(n-1), at most MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// AllowPrivateNetworks lets deliveries reach loopback and private addresses
+	AllowPrivateNetworks bool
+}
+
+// webhookEnvelope is the JSON body of every webhook request.
+type webhookEnvelope struct {
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepo
+	client      *http.Client
+	opts        WebhookOptions
+}
+
+func NewWebhookService(wr repository.WebhookRepo, opts WebhookOptions) WebhookService {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 50
+	}
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 8
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = 10 * time.Second
+	}
+	if opts.MaxBackoff < opts.BaseBackoff {
+		opts.MaxBackoff = opts.BaseBackoff
+	}
+	// Subscription URLs come from outside like photo URLs, so deliveries may not reach
+	// the internal network either
+	client := newPhotoClient(opts.Timeout, schemeSet([]string{"http", "https"}), opts.AllowPrivateNetworks)
+	// A redirect is treated as a failed delivery, the signature is bound to the URL owner
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return &webhookService{
+		webhookRepo: wr,
+		client:      client,
+		opts:        opts,
+	}
+}
+
+// SignWebhook returns the X-Webhook-Signature value of a body sent at timestamp:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">". Receivers recompute it
+// with their secret and should reject old timestamps to prevent replays.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	t := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *webhookService) Subscribe(ctx context.Context, rawURL string, events []string) (model.WebhookSubscription, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return model.WebhookSubscription{}, error_message.ErrWrongWebhookURL
+	}
+	if len(events) == 0 {
+		return model.WebhookSubscription{}, error_message.ErrWrongWebhookEvents
+	}
+	for _, event := range events {
+		if !webhookEvents[event] {
+			return model.WebhookSubscription{}, error_message.ErrWrongWebhookEvents
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return model.WebhookSubscription{}, fmt.Errorf("service.Subscribe: generate secret: %w", err)
+	}
+	sub := model.WebhookSubscription{
+		URL:       u.String(),
+		Secret:    hex.EncodeToString(secret),
+		Events:    events,
+		CreatedAt: time.Now(),
+	}
+	id, err := s.webhookRepo.CreateSubscription(ctx, sub)
+	if err != nil {
+		return model.WebhookSubscription{}, fmt.Errorf("service.Subscribe: webhookRepo.CreateSubscription: %w", err)
+	}
+	sub.ID = id
+	return sub, nil
+}
+
+func (s *webhookService) List(ctx context.Context) ([]model.WebhookSubscription, error) {
+	return s.webhookRepo.ListSubscriptions(ctx)
+}
+
+func (s *webhookService) Unsubscribe(ctx context.Context, id int) error {
+	if err := s.webhookRepo.DeleteSubscription(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return error_message.ErrWebhookNotFound
+		}
+		return fmt.Errorf("service.Unsubscribe: webhookRepo.DeleteSubscription (id=%d): %w", id, err)
+	}
+	return nil
+}
+
+func (s *webhookService) Deliveries(ctx context.Context, subscriptionID int, status string) ([]model.WebhookDelivery, error) {
+	switch status {
+	case "", model.DeliveryStatusPending, model.DeliveryStatusDelivered, model.DeliveryStatusDead:
+	default:
+		return nil, error_message.ErrWrongDeliveryStatus
+	}
+	return s.webhookRepo.ListDeliveries(ctx, subscriptionID, status, webhookDeliveryLogSize)
+}
+
+func (s *webhookService) Retry(ctx context.Context, subscriptionID int, deliveryID int64) error {
+	if err := s.webhookRepo.RetryDelivery(ctx, subscriptionID, deliveryID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return error_message.ErrDeliveryNotFound
+		}
+		return fmt.Errorf("service.Retry: webhookRepo.RetryDelivery (id=%d): %w", deliveryID, err)
+	}
+	return nil
+}
+
+func (s *webhookService) Emit(ctx context.Context, event string, data interface{}) error {
+	payload, err := json.Marshal(webhookEnvelope{Event: event, OccurredAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+	if _, err := s.webhookRepo.EnqueueEvent(ctx, event, string(payload)); err != nil {
+		return fmt.Errorf("service.Emit: webhookRepo.EnqueueEvent (%s): %w", event, err)
+	}
+	return nil
+}
+
+func (s *webhookService) DispatchDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	// The lease must outlive a whole batch of timed out requests
+	lease := s.opts.Timeout*time.Duration(s.opts.BatchSize) + time.Minute
+	deliveries, subs, err := s.webhookRepo.ClaimDue(ctx, now, lease, s.opts.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("service.DispatchDue: webhookRepo.ClaimDue: %w", err)
+	}
+	for _, d := range deliveries {
+		sub, ok := subs[d.SubscriptionID]
+		if !ok {
+			// The subscription was deleted meanwhile, its deliveries are gone too
+			continue
+		}
+		d = s.attempt(ctx, sub, d)
+		if err := s.webhookRepo.UpdateDelivery(ctx, d); err != nil {
+			return 0, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt sends the delivery once and returns it updated with the outcome.
+func (s *webhookService) attempt(ctx context.Context, sub model.WebhookSubscription, d model.WebhookDelivery) model.WebhookDelivery {
+	d.Attempts++
+	code, err := s.send(ctx, sub, d)
+	d.ResponseCode = code
+	if err == nil {
+		now := time.Now()
+		d.Status = model.DeliveryStatusDelivered
+		d.LastError = ""
+		d.DeliveredAt = &now
+		return d
+	}
+
+	d.LastError = err.Error()
+	if d.Attempts >= s.opts.MaxAttempts {
+		d.Status = model.DeliveryStatusDead
+		return d
+	}
+	d.Status = model.DeliveryStatusPending
+	d.NextAttemptAt = time.Now().Add(s.backoff(d.Attempts))
+	return d
+}
+
+// backoff returns the delay before the retry following the given number of attempts.
+func (s *webhookService) backoff(attempts int) time.Duration {
+	delay := s.opts.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= s.opts.MaxBackoff {
+			return s.opts.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// send posts the signed payload and returns the response status code.
+func (s *webhookService) send(ctx context.Context, sub model.WebhookSubscription, d model.WebhookDelivery) (int, error) {
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, d.Event)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(sub.Secret, time.Now().Unix(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (s *webhookService) Run(ctx context.Context) {
+	runBatches(ctx, "webhook dispatch", s.opts.Interval, s.opts.BatchSize, s.DispatchDue, nil)
+}
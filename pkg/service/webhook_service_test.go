@@ -0,0 +1,225 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/error_message"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/model"
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/pkg/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookRepo implements a mock for repository.WebhookRepo
+type MockWebhookRepo struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepo) CreateSubscription(ctx context.Context, sub model.WebhookSubscription) (int, error) {
+	args := m.Called(ctx, sub)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWebhookRepo) ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepo) DeleteSubscription(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) EnqueueEvent(ctx context.Context, event, payload string) (int, error) {
+	args := m.Called(ctx, event, payload)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWebhookRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, map[int]model.WebhookSubscription, error) {
+	args := m.Called(ctx, now, lease, limit)
+	return args.Get(0).([]model.WebhookDelivery), args.Get(1).(map[int]model.WebhookSubscription), args.Error(2)
+}
+
+func (m *MockWebhookRepo) UpdateDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) ListDeliveries(ctx context.Context, subscriptionID int, status string, limit int) ([]model.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID, status, limit)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepo) RetryDelivery(ctx context.Context, subscriptionID int, deliveryID int64) error {
+	args := m.Called(ctx, subscriptionID, deliveryID)
+	return args.Error(0)
+}
+
+// webhookReceiver is a local endpoint that verifies signatures and answers with status
+type webhookReceiver struct {
+	mu       sync.Mutex
+	secret   string
+	status   int
+	received []string
+	invalid  int
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	sig := req.Header.Get(service.WebhookSignatureHeader)
+	ts, _ := strconv.ParseInt(strings.TrimPrefix(strings.Split(sig, ",")[0], "t="), 10, 64)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if sig != service.SignWebhook(r.secret, ts, body) || req.Header.Get(service.WebhookEventHeader) == "" {
+		r.invalid++
+	}
+	r.received = append(r.received, string(body))
+	w.WriteHeader(r.status)
+}
+
+func TestWebhookService_Subscribe(t *testing.T) {
+	mockRepo := new(MockWebhookRepo)
+	svc := service.NewWebhookService(mockRepo, service.WebhookOptions{})
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("CreateSubscription", mock.Anything, mock.MatchedBy(func(s model.WebhookSubscription) bool {
+			return s.URL == "https://crm.example.com/hook" && len(s.Secret) == 64
+		})).Return(4, nil).Once()
+
+		sub, err := svc.Subscribe(ctx, "https://crm.example.com/hook", []string{model.EventAdvertCreated})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, sub.ID)
+		assert.NotEmpty(t, sub.Secret)
+	})
+
+	cases := []struct {
+		name   string
+		url    string
+		events []string
+		err    error
+	}{
+		{"Scheme", "ftp://crm.example.com", []string{model.EventAdvertCreated}, error_message.ErrWrongWebhookURL},
+		{"Relative", "/hook", []string{model.EventAdvertCreated}, error_message.ErrWrongWebhookURL},
+		{"NoEvents", "https://crm.example.com", nil, error_message.ErrWrongWebhookEvents},
+		{"UnknownEvent", "https://crm.example.com", []string{"advert.sold"}, error_message.ErrWrongWebhookEvents},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.Subscribe(ctx, tc.url, tc.events)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_DispatchDue(t *testing.T) {
+	receiver := &webhookReceiver{secret: "s3cret", status: http.StatusNoContent}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	mockRepo := new(MockWebhookRepo)
+	svc := service.NewWebhookService(mockRepo, service.WebhookOptions{
+		BatchSize:   10,
+		MaxAttempts: 3,
+		BaseBackoff: time.Minute,
+		MaxBackoff:  90 * time.Second,
+		// The test receiver listens on loopback
+		AllowPrivateNetworks: true,
+	})
+	ctx := context.Background()
+	subs := map[int]model.WebhookSubscription{1: {ID: 1, URL: server.URL, Secret: "s3cret"}}
+	delivery := model.WebhookDelivery{ID: 7, SubscriptionID: 1, Event: model.EventAdvertCreated, Payload: `{"data":{"id":9}}`}
+
+	t.Run("Delivered", func(t *testing.T) {
+		mockRepo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything, 10).
+			Return([]model.WebhookDelivery{delivery}, subs, nil).Once()
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d model.WebhookDelivery) bool {
+			return d.Status == model.DeliveryStatusDelivered && d.Attempts == 1 &&
+				d.ResponseCode == http.StatusNoContent && d.DeliveredAt != nil
+		})).Return(nil).Once()
+
+		n, err := svc.DispatchDue(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, []string{delivery.Payload}, receiver.received)
+		assert.Zero(t, receiver.invalid)
+	})
+
+	t.Run("BackoffThenDead", func(t *testing.T) {
+		receiver.mu.Lock()
+		receiver.status = http.StatusInternalServerError
+		receiver.mu.Unlock()
+		cases := []struct {
+			attempts int
+			status   string
+			delay    time.Duration
+		}{
+			{0, model.DeliveryStatusPending, time.Minute},
+			{1, model.DeliveryStatusPending, 90 * time.Second},
+			{2, model.DeliveryStatusDead, 0},
+		}
+		for _, tc := range cases {
+			d := delivery
+			d.Attempts = tc.attempts
+			before := time.Now()
+			mockRepo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything, 10).
+				Return([]model.WebhookDelivery{d}, subs, nil).Once()
+			mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(got model.WebhookDelivery) bool {
+				if got.Status != tc.status || got.Attempts != tc.attempts+1 || got.ResponseCode != 500 || got.LastError == "" {
+					return false
+				}
+				return tc.delay == 0 || !got.NextAttemptAt.Before(before.Add(tc.delay))
+			})).Return(nil).Once()
+
+			_, err := svc.DispatchDue(ctx)
+			assert.NoError(t, err)
+		}
+	})
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_DeniesPrivateNetworks(t *testing.T) {
+	receiver := &webhookReceiver{secret: "s3cret", status: http.StatusNoContent}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	mockRepo := new(MockWebhookRepo)
+	svc := service.NewWebhookService(mockRepo, service.WebhookOptions{BatchSize: 10})
+	subs := map[int]model.WebhookSubscription{1: {ID: 1, URL: server.URL, Secret: "s3cret"}}
+	delivery := model.WebhookDelivery{ID: 7, SubscriptionID: 1, Event: model.EventAdvertCreated, Payload: `{"data":{"id":9}}`}
+
+	mockRepo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything, 10).
+		Return([]model.WebhookDelivery{delivery}, subs, nil).Once()
+	mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d model.WebhookDelivery) bool {
+		return d.Status == model.DeliveryStatusPending && strings.Contains(d.LastError, "is not public")
+	})).Return(nil).Once()
+
+	_, err := svc.DispatchDue(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, receiver.received)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_Retry(t *testing.T) {
+	mockRepo := new(MockWebhookRepo)
+	svc := service.NewWebhookService(mockRepo, service.WebhookOptions{})
+
+	mockRepo.On("RetryDelivery", mock.Anything, 1, int64(7)).Return(nil).Once()
+	mockRepo.On("RetryDelivery", mock.Anything, 1, int64(8)).Return(sql.ErrNoRows).Once()
+
+	assert.NoError(t, svc.Retry(context.Background(), 1, 7))
+	assert.ErrorIs(t, svc.Retry(context.Background(), 1, 8), error_message.ErrDeliveryNotFound)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// runBatches calls process every interval until ctx is done, logging its
+// failures under name. After each round it calls idle, if set.
+func runBatches(ctx context.Context, name string, interval time.Duration, batchSize int, process func(ctx context.Context) (int, error), idle func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// Keep going without waiting while there is a backlog
+		for {
+			n, err := process(ctx)
+			if err != nil {
+				log.Printf("%s failed: %v", name, err)
+				break
+			}
+			if n < batchSize {
+				break
+			}
+		}
+		if idle != nil {
+			idle(ctx)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
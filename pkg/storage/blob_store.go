@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/AlexandrPetrenkoTech/Test-task-for-Advertising/configs"
+)
+
+var (
+	// ErrNotFound is returned by BlobStore.Get when there is no object under the key.
+	ErrNotFound = errors.New("blob not found")
+	// ErrInvalidKey is returned for keys that are not clean relative slash-separated paths.
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore keeps uploaded files (e.g. advert photos) under slash-separated keys.
+type BlobStore interface {
+	// Put stores size bytes read from r under key with the given content type.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns the object body and its content type. The caller must close the body.
+	Get(ctx context.Context, key string) (io.ReadCloser, string, error)
+	// Delete removes the object; deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStore builds the BlobStore selected by cfg.Media.Storage ("local" or "s3")
+func NewBlobStore(cfg *configs.Config) (BlobStore, error) {
+	switch cfg.Media.Storage {
+	case "", "local":
+		return NewLocalStore(cfg.Media.Local.Root)
+	case "s3":
+		s3 := cfg.Media.S3
+		return NewS3Store(s3.Endpoint, s3.Region, s3.Bucket, s3.AccessKey, s3.SecretKey)
+	default:
+		return nil, fmt.Errorf("unknown media storage %q", cfg.Media.Storage)
+	}
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	body := "\x89PNG\r\n\x1a\nfake"
+	assert.NoError(t, store.Put(ctx, "adverts/1/a.png", strings.NewReader(body), int64(len(body)), "image/png"))
+
+	rc, contentType, err := store.Get(ctx, "adverts/1/a.png")
+	assert.NoError(t, err)
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, body, string(got))
+	assert.Equal(t, "image/png", contentType)
+
+	assert.NoError(t, store.Delete(ctx, "adverts/1/a.png"))
+	_, _, err = store.Get(ctx, "adverts/1/a.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Deleting twice is fine
+	assert.NoError(t, store.Delete(ctx, "adverts/1/a.png"))
+}
+
+func TestLocalStore_RejectsEscapingKeys(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "a/../../b", "a//b", `a\b`} {
+		err := store.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain")
+		assert.Error(t, err, key)
+	}
+}
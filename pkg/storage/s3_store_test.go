@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is a minimal MinIO stand-in: it checks the request signature
+// and keeps objects of a single bucket in memory.
+type fakeS3 struct {
+	t       *testing.T
+	store   *S3Store
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	body        []byte
+	contentType string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Recompute the signature with the same credentials and compare
+	signedAt, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	assert.NoError(f.t, err)
+	expected := r.Clone(context.Background())
+	expected.URL.Host = r.Host
+	f.store.sign(expected, signedAt)
+	if r.Header.Get("Authorization") != expected.Header.Get("Authorization") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/adverts-bucket/")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = fakeObject{body: body, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet:
+		obj, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		_, _ = w.Write(obj.body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Store_PutGetDelete(t *testing.T) {
+	fake := &fakeS3{t: t, objects: map[string]fakeObject{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	store, err := NewS3Store(srv.URL, "us-east-1", "adverts-bucket", "minioadmin", "minioadmin")
+	assert.NoError(t, err)
+	fake.store = store
+	ctx := context.Background()
+
+	body := "\xff\xd8\xff\xe0fake-jpeg"
+	assert.NoError(t, store.Put(ctx, "adverts/7/photo.jpg", strings.NewReader(body), int64(len(body)), "image/jpeg"))
+
+	rc, contentType, err := store.Get(ctx, "adverts/7/photo.jpg")
+	assert.NoError(t, err)
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, body, string(got))
+	assert.Equal(t, "image/jpeg", contentType)
+
+	assert.NoError(t, store.Delete(ctx, "adverts/7/photo.jpg"))
+	_, _, err = store.Get(ctx, "adverts/7/photo.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestS3Store_WrongCredentials(t *testing.T) {
+	fake := &fakeS3{t: t, objects: map[string]fakeObject{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	server, err := NewS3Store(srv.URL, "us-east-1", "adverts-bucket", "minioadmin", "minioadmin")
+	assert.NoError(t, err)
+	fake.store = server
+
+	client, err := NewS3Store(srv.URL, "us-east-1", "adverts-bucket", "minioadmin", "wrong-secret")
+	assert.NoError(t, err)
+	err = client.Put(context.Background(), "a.png", strings.NewReader("x"), 1, "image/png")
+	assert.ErrorContains(t, err, "403")
+}